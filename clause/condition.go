@@ -0,0 +1,229 @@
+package clause
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ICondition 类型化的过滤条件, 用于 update/upsert 的 when 子句等场景, 属性值通过 Value 编码, 无需手工拼接引号。
+// owner 为属性的所属对象: update 的 when 子句中为空, 直接引用属性名; lookup 中为标签名, 生成 tag.prop
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+type ICondition interface {
+	Build(owner string) (string, error)
+	// Props 返回条件引用的属性名, 用于校验属性是否存在
+	Props() []string
+}
+
+// 属性比较条件: prop op value
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+type compare struct {
+	prop  string
+	op    string
+	value interface{}
+}
+
+// Eq 属性等于值: prop == value
+func Eq(prop string, value interface{}) ICondition {
+	return compare{prop: prop, op: "==", value: value}
+}
+
+// Neq 属性不等于值: prop != value
+func Neq(prop string, value interface{}) ICondition {
+	return compare{prop: prop, op: "!=", value: value}
+}
+
+// Gt 属性大于值: prop > value
+func Gt(prop string, value interface{}) ICondition {
+	return compare{prop: prop, op: ">", value: value}
+}
+
+// Gte 属性大于等于值: prop >= value
+func Gte(prop string, value interface{}) ICondition {
+	return compare{prop: prop, op: ">=", value: value}
+}
+
+// Lt 属性小于值: prop < value
+func Lt(prop string, value interface{}) ICondition {
+	return compare{prop: prop, op: "<", value: value}
+}
+
+// Lte 属性小于等于值: prop <= value
+func Lte(prop string, value interface{}) ICondition {
+	return compare{prop: prop, op: "<=", value: value}
+}
+
+// In 属性在列表中: prop in [values...]
+func In(prop string, values ...interface{}) ICondition {
+	return compare{prop: prop, op: "in", value: values}
+}
+
+// NotIn 属性不在列表中: prop not in [values...]
+func NotIn(prop string, values ...interface{}) ICondition {
+	return compare{prop: prop, op: "not in", value: values}
+}
+
+// StartsWith 字符串属性以前缀开头: prop starts with 'prefix'
+func StartsWith(prop string, prefix string) ICondition {
+	return compare{prop: prop, op: "starts with", value: prefix}
+}
+
+// Contains 字符串属性包含子串: prop contains 'sub'
+func Contains(prop string, sub string) ICondition {
+	return compare{prop: prop, op: "contains", value: sub}
+}
+
+// Build 实现 ICondition 接口
+func (c compare) Build(owner string) (string, error) {
+	if c.prop == "" {
+		return "", fmt.Errorf("条件的属性名不能为空")
+	}
+	value, err := Value(c.value)
+	if err != nil {
+		return "", fmt.Errorf("条件[%s %s]的值: %w", c.prop, c.op, err)
+	}
+	return fmt.Sprintf("%s %s %s", propName(owner, c.prop), c.op, value), nil
+}
+
+// Props 实现 ICondition 接口
+func (c compare) Props() []string {
+	return []string{c.prop}
+}
+
+// 属性判空条件
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+type nullCheck struct {
+	prop string
+	not  bool
+}
+
+// IsNull 属性为空: prop is null
+func IsNull(prop string) ICondition {
+	return nullCheck{prop: prop}
+}
+
+// IsNotNull 属性不为空: prop is not null
+func IsNotNull(prop string) ICondition {
+	return nullCheck{prop: prop, not: true}
+}
+
+// Build 实现 ICondition 接口
+func (c nullCheck) Build(owner string) (string, error) {
+	if c.not {
+		return propName(owner, c.prop) + " is not null", nil
+	}
+	return propName(owner, c.prop) + " is null", nil
+}
+
+// Props 实现 ICondition 接口
+func (c nullCheck) Props() []string {
+	return []string{c.prop}
+}
+
+// 逻辑组合条件
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+type logic struct {
+	op    string
+	conds []ICondition
+}
+
+// And 所有条件同时成立
+func And(conds ...ICondition) ICondition {
+	return logic{op: "and", conds: conds}
+}
+
+// Or 任意条件成立
+func Or(conds ...ICondition) ICondition {
+	return logic{op: "or", conds: conds}
+}
+
+// Build 实现 ICondition 接口, 每个子条件加括号以保证优先级
+func (c logic) Build(owner string) (string, error) {
+	if len(c.conds) == 0 {
+		return "", fmt.Errorf("%s 条件至少需要一个子条件", c.op)
+	}
+	parts := make([]string, len(c.conds))
+	for i, cond := range c.conds {
+		part, err := cond.Build(owner)
+		if err != nil {
+			return "", err
+		}
+		if len(c.conds) == 1 {
+			return part, nil
+		}
+		parts[i] = "(" + part + ")"
+	}
+	return strings.Join(parts, " "+c.op+" "), nil
+}
+
+// Props 实现 ICondition 接口
+func (c logic) Props() []string {
+	var props []string
+	for _, cond := range c.conds {
+		props = append(props, cond.Props()...)
+	}
+	return props
+}
+
+// 取反条件
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+type not struct {
+	cond ICondition
+}
+
+// Not 条件不成立: not (cond)
+func Not(cond ICondition) ICondition {
+	return not{cond: cond}
+}
+
+// Build 实现 ICondition 接口
+func (c not) Build(owner string) (string, error) {
+	part, err := c.cond.Build(owner)
+	if err != nil {
+		return "", err
+	}
+	return "not (" + part + ")", nil
+}
+
+// Props 实现 ICondition 接口
+func (c not) Props() []string {
+	return c.cond.Props()
+}
+
+// 原始条件, 直接输出给定的 nGQL
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+type raw string
+
+// Raw 使用原始 nGQL 作为条件, 不做任何编码与校验
+func Raw(ngql string) ICondition {
+	return raw(ngql)
+}
+
+// Build 实现 ICondition 接口
+func (c raw) Build(string) (string, error) {
+	return string(c), nil
+}
+
+// Props 实现 ICondition 接口
+func (c raw) Props() []string {
+	return nil
+}
+
+// 属性的完整引用
+func propName(owner, prop string) string {
+	if owner == "" {
+		return prop
+	}
+	return owner + "." + prop
+}
@@ -0,0 +1,148 @@
+package clause
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IExpression 更新表达式, 作为 Updates/Upserts 的属性值时生成 prop = <表达式>, 而不是字面量。
+// prop 为被赋值的属性名, Inc、Append 等表达式据此引用属性的当前值
+//
+// @Author: 罗德
+// @Date: 2024/7/2
+type IExpression interface {
+	BuildExpr(prop string) (string, error)
+}
+
+// Expression 带 ? 占位符的 nGQL 表达式, 参数通过 Value 编码后替换占位符, 引号内的 ? 不作为占位符
+//
+// @Author: 罗德
+// @Date: 2024/7/2
+type Expression struct {
+	SQL  string
+	Vars []interface{}
+}
+
+// Expr 创建表达式, 例如 Expr("cnt + ?", 1)、Expr("name + ?", "后缀")
+//
+// @Author: 罗德
+// @Date: 2024/7/2
+func Expr(sql string, vars ...interface{}) Expression {
+	return Expression{SQL: sql, Vars: vars}
+}
+
+// BuildExpr 实现 IExpression 接口
+func (e Expression) BuildExpr(string) (string, error) {
+	var sb strings.Builder
+	var quote rune
+	escaped := false
+	index := 0
+	for _, r := range e.SQL {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && r == '\\':
+			escaped = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '\'' || r == '"'):
+			quote = r
+		case quote == 0 && r == '?':
+			if index >= len(e.Vars) {
+				return "", fmt.Errorf("表达式[%s]的占位符数量多于参数数量%d", e.SQL, len(e.Vars))
+			}
+			value, err := Value(e.Vars[index])
+			if err != nil {
+				return "", fmt.Errorf("表达式[%s]的第%d个参数: %w", e.SQL, index+1, err)
+			}
+			sb.WriteString(value)
+			index++
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	if index != len(e.Vars) {
+		return "", fmt.Errorf("表达式[%s]的占位符数量%d与参数数量%d不一致", e.SQL, index, len(e.Vars))
+	}
+	return sb.String(), nil
+}
+
+// 引用被赋值属性的表达式, 由 build 根据属性名生成
+//
+// @Author: 罗德
+// @Date: 2024/7/2
+type propExpr struct {
+	name  string
+	build func(prop string) (string, error)
+}
+
+// BuildExpr 实现 IExpression 接口
+func (e propExpr) BuildExpr(prop string) (string, error) {
+	if prop == "" {
+		return "", fmt.Errorf("%s 只能作为 Updates/Upserts 的属性值使用", e.name)
+	}
+	return e.build(prop)
+}
+
+// Inc 属性自增: prop = coalesce(prop, 0) + n, 属性为 null 时按 0 计算
+//
+// @Author: 罗德
+// @Date: 2024/7/2
+func Inc(n interface{}) IExpression {
+	return arithmetic("Inc", "+", n)
+}
+
+// Dec 属性自减: prop = coalesce(prop, 0) - n, 属性为 null 时按 0 计算
+//
+// @Author: 罗德
+// @Date: 2024/7/2
+func Dec(n interface{}) IExpression {
+	return arithmetic("Dec", "-", n)
+}
+
+func arithmetic(name, op string, n interface{}) IExpression {
+	return propExpr{name: name, build: func(prop string) (string, error) {
+		value, err := Value(n)
+		if err != nil {
+			return "", fmt.Errorf("%s 的参数: %w", name, err)
+		}
+		return fmt.Sprintf("coalesce(%s, 0) %s %s", prop, op, value), nil
+	}}
+}
+
+// Append 向列表属性追加元素: prop = coalesce(prop, []) + [values...], 属性为 null 时视为空列表。
+// nebula 3.x 的标签属性不支持列表类型, 字符串拼接请使用 Expr("prop + ?", s)
+//
+// @Author: 罗德
+// @Date: 2024/7/2
+func Append(values ...interface{}) IExpression {
+	return propExpr{name: "Append", build: func(prop string) (string, error) {
+		// 不传参数时 values 为 nil, 编码为空列表而不是 NULL
+		list, err := Value(append([]interface{}{}, values...))
+		if err != nil {
+			return "", fmt.Errorf("Append 的参数: %w", err)
+		}
+		return fmt.Sprintf("coalesce(%s, []) + %s", prop, list), nil
+	}}
+}
+
+// Coalesce 属性为 null 时依次使用候选值: prop = coalesce(prop, values...), 属性已有值时保持不变
+//
+// @Author: 罗德
+// @Date: 2024/7/2
+func Coalesce(values ...interface{}) IExpression {
+	return propExpr{name: "Coalesce", build: func(prop string) (string, error) {
+		if len(values) == 0 {
+			return "", fmt.Errorf("Coalesce 至少需要一个候选值")
+		}
+		items := []string{prop}
+		for i, v := range values {
+			value, err := Value(v)
+			if err != nil {
+				return "", fmt.Errorf("Coalesce 的第%d个参数: %w", i+1, err)
+			}
+			items = append(items, value)
+		}
+		return "coalesce(" + strings.Join(items, ", ") + ")", nil
+	}}
+}
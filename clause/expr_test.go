@@ -0,0 +1,87 @@
+package clause
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpr(t *testing.T) {
+	tests := []struct {
+		name string
+		expr Expression
+		want string
+		err  string
+	}{
+		{name: "无参数", expr: Expr("now()"), want: "now()"},
+		{name: "按顺序替换占位符", expr: Expr("cnt + ? * ?", 1, 2.5), want: "cnt + 1 * 2.5"},
+		{name: "字符串参数转义", expr: Expr("name + ?", "'后缀"), want: `name + '\'后缀'`},
+		{name: "引号内的问号不替换", expr: Expr(`name + '?' + "?" + ?`, "a"), want: `name + '?' + "?" + 'a'`},
+		{name: "转义引号后的问号", expr: Expr(`'it\'s ?' + ?`, 1), want: `'it\'s ?' + 1`},
+		{name: "参数不足", expr: Expr("? + ?", 1), err: "占位符数量多于参数数量1"},
+		{name: "参数多余", expr: Expr("?", 1, 2), err: "占位符数量1与参数数量2不一致"},
+		{name: "参数无法编码", expr: Expr("?", struct{}{}), err: "第1个参数"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.expr.BuildExpr("cnt")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("BuildExpr() error = %v, 期望包含 %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("BuildExpr() = %s, 期望 %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPropExpr(t *testing.T) {
+	tests := []struct {
+		name string
+		expr IExpression
+		want string
+		err  string
+	}{
+		{name: "Inc", expr: Inc(2), want: "coalesce(cnt, 0) + 2"},
+		{name: "Inc 浮点数", expr: Inc(0.5), want: "coalesce(cnt, 0) + 0.5"},
+		{name: "Dec", expr: Dec(int64(3)), want: "coalesce(cnt, 0) - 3"},
+		{name: "Inc 参数无法编码", expr: Inc([]func(){nil}), err: "Inc 的参数"},
+		{name: "Append", expr: Append("a", 1), want: "coalesce(cnt, []) + ['a', 1]"},
+		{name: "Append 无参数", expr: Append(), want: "coalesce(cnt, []) + []"},
+		{name: "Append 参数无法编码", expr: Append(struct{}{}), err: "Append 的参数"},
+		{name: "Coalesce", expr: Coalesce(nil, "默认"), want: "coalesce(cnt, NULL, '默认')"},
+		{name: "Coalesce 无候选值", expr: Coalesce(), err: "至少需要一个候选值"},
+		{name: "Coalesce 参数无法编码", expr: Coalesce(1, struct{}{}), err: "Coalesce 的第2个参数"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.expr.BuildExpr("cnt")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("BuildExpr() error = %v, 期望包含 %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("BuildExpr() = %s, 期望 %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Inc、Append 等引用属性当前值的表达式只能作为更新的属性值使用
+func TestPropExprWithoutProp(t *testing.T) {
+	for _, expr := range []IExpression{Inc(1), Dec(1), Append(1), Coalesce(1)} {
+		if _, err := Value(expr); err == nil || !strings.Contains(err.Error(), "只能作为 Updates/Upserts 的属性值使用") {
+			t.Errorf("Value(%T) error = %v", expr, err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Package clause 生成 nGQL 子句: 将 Go 值编码为 nGQL 字面量, 以及 when/where 中使用的类型化条件。
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+package clause
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateTimeLayout datetime 字面量使用的时间格式
+const DateTimeLayout = "2006-01-02T15:04:05.000000"
+
+// Value 将 Go 值编码为 nGQL 字面量:
+//   - nil 与 nil 指针为 NULL
+//   - 字符串加单引号并转义反斜杠、单引号与换行
+//   - 整数、布尔值原样输出, 浮点数始终带小数点以保持 double 类型, NaN 与 ±Inf 返回错误
+//   - time.Time 转换为 UTC 后编码为 datetime('...'), time.Duration 为纳秒整数
+//   - 切片、数组为列表 [...], 键为字符串的 map 为 {k: v}, 键必须是合法的标识符
+//   - IExpression 输出表达式本身, 不加引号
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func Value(v interface{}) (string, error) {
+	switch value := v.(type) {
+	case nil:
+		return "NULL", nil
+	case IExpression:
+		return value.BuildExpr("")
+	case string:
+		return Quote(value), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case time.Time:
+		// datetime 字面量不带时区, graphd 按 UTC 解析
+		return fmt.Sprintf("datetime(%s)", Quote(value.UTC().Format(DateTimeLayout))), nil
+	case time.Duration:
+		return strconv.FormatInt(int64(value), 10), nil
+	}
+
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return "NULL", nil
+		}
+		return Value(val.Elem().Interface())
+	case reflect.String:
+		return Quote(val.String()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(val.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(val.Float()) || math.IsInf(val.Float(), 0) {
+			return "", fmt.Errorf("不支持将%v编码为nGQL字面量", val.Float())
+		}
+		return formatFloat(val.Float(), val.Type().Bits()), nil
+	case reflect.Slice, reflect.Array:
+		if val.Kind() == reflect.Slice && val.IsNil() {
+			return "NULL", nil
+		}
+		items := make([]string, val.Len())
+		for i := range items {
+			item, err := Value(val.Index(i).Interface())
+			if err != nil {
+				return "", err
+			}
+			items[i] = item
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return "", fmt.Errorf("不支持键类型为%s的map", val.Type().Key())
+		}
+		if val.IsNil() {
+			return "NULL", nil
+		}
+		keys := make([]string, 0, val.Len())
+		for _, key := range val.MapKeys() {
+			if !identifierRegexp.MatchString(key.String()) {
+				return "", fmt.Errorf("map的键[%s]不是合法的标识符", key.String())
+			}
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, key := range keys {
+			item, err := Value(val.MapIndex(reflect.ValueOf(key).Convert(val.Type().Key())).Interface())
+			if err != nil {
+				return "", err
+			}
+			items[i] = key + ": " + item
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+	}
+	return "", fmt.Errorf("不支持将%T编码为nGQL字面量", v)
+}
+
+// Quote 生成单引号字符串字面量
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func Quote(s string) string {
+	return "'" + escaper.Replace(s) + "'"
+}
+
+// map 字面量的键: 字母或下划线开头, 由字母、数字与下划线组成
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// 字符串字面量需要转义的字符
+var escaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// 浮点数始终带小数点, 避免被识别为整数
+func formatFloat(f float64, bits int) string {
+	text := strconv.FormatFloat(f, 'g', -1, bits)
+	if !strings.ContainsAny(text, ".e") {
+		text += ".0"
+	}
+	return text
+}
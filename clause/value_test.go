@@ -0,0 +1,98 @@
+package clause
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{s: "", want: "''"},
+		{s: "甲", want: "'甲'"},
+		{s: `it's`, want: `'it\'s'`},
+		{s: `a\b`, want: `'a\\b'`},
+		{s: "a\nb\r\tc", want: `'a\nb\r\tc'`},
+		{s: `"双引号"`, want: `'"双引号"'`},
+	}
+	for _, tt := range tests {
+		if got := Quote(tt.s); got != tt.want {
+			t.Errorf("Quote(%q) = %s, 期望 %s", tt.s, got, tt.want)
+		}
+	}
+}
+
+type testString string
+
+func TestValue(t *testing.T) {
+	name := "甲"
+	var nilPtr *int
+	var nilSlice []int
+	var nilMap map[string]int
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "nil", value: nil, want: "NULL"},
+		{name: "nil 指针", value: nilPtr, want: "NULL"},
+		{name: "nil 切片", value: nilSlice, want: "NULL"},
+		{name: "nil map", value: nilMap, want: "NULL"},
+		{name: "字符串", value: "it's", want: `'it\'s'`},
+		{name: "字符串指针", value: &name, want: "'甲'"},
+		{name: "自定义字符串类型", value: testString("a"), want: "'a'"},
+		{name: "布尔值", value: true, want: "true"},
+		{name: "整数", value: int8(-3), want: "-3"},
+		{name: "无符号整数", value: uint64(math.MaxUint64), want: "18446744073709551615"},
+		{name: "整数值的浮点数", value: 2.0, want: "2.0"},
+		{name: "小数", value: float32(0.5), want: "0.5"},
+		{name: "科学计数法", value: 1e21, want: "1e+21"},
+		{name: "时间", value: time.Date(2024, 7, 1, 8, 0, 0, 1000, time.FixedZone("CST", 8*3600)), want: "datetime('2024-07-01T00:00:00.000001')"},
+		{name: "时长", value: time.Second, want: "1000000000"},
+		{name: "列表", value: []interface{}{1, "a", nil, []bool{true}}, want: "[1, 'a', NULL, [true]]"},
+		{name: "数组", value: [2]float64{1, 1.5}, want: "[1.0, 1.5]"},
+		{name: "map 按键排序", value: map[string]interface{}{"b": 1, "a_1": "x", "_c": nil}, want: "{_c: NULL, a_1: 'x', b: 1}"},
+		{name: "表达式", value: Expr("now() + ?", 1), want: "now() + 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Value(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Value(%v) = %s, 期望 %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValueErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		err   string
+	}{
+		{name: "NaN", value: math.NaN(), err: "不支持将NaN编码为nGQL字面量"},
+		{name: "+Inf", value: math.Inf(1), err: "不支持将+Inf编码为nGQL字面量"},
+		{name: "-Inf", value: float32(math.Inf(-1)), err: "不支持将-Inf编码为nGQL字面量"},
+		{name: "列表中的 NaN", value: []float64{1, math.NaN()}, err: "NaN"},
+		{name: "键含冒号", value: map[string]int{"a: 1, b": 2}, err: "map的键[a: 1, b]不是合法的标识符"},
+		{name: "键以数字开头", value: map[string]int{"1a": 1}, err: "不是合法的标识符"},
+		{name: "空键", value: map[string]int{"": 1}, err: "不是合法的标识符"},
+		{name: "非字符串键", value: map[int]int{1: 1}, err: "不支持键类型为int的map"},
+		{name: "结构体", value: struct{}{}, err: "不支持将struct {}编码为nGQL字面量"},
+		{name: "表达式参数", value: Expr("?", math.Inf(1)), err: "第1个参数"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Value(tt.value); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("Value() error = %v, 期望包含 %q", err, tt.err)
+			}
+		})
+	}
+}
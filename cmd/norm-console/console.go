@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"nebula-orm-go/dialectors"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 结果的输出格式
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// 可以切换图空间的拨号器, 例如 dialectors.NebulaDialer
+//
+// @Author: 罗德
+// @Date: 2024/7/8
+type spaceUser interface {
+	UseSpace(space string)
+}
+
+// 交互式控制台: 读取以分号结尾的 nGQL(可以跨多行), 执行后按指定格式输出结果与耗时, 以冒号开头的行为控制台命令
+//
+// @Author: 罗德
+// @Date: 2024/7/8
+type console struct {
+	dialer      dialectors.IDialer
+	in          *bufio.Scanner
+	out         io.Writer
+	space       string   // 当前图空间
+	explain     string   // 执行计划格式(row/dot/tck), 为空时正常执行
+	format      string   // 输出格式
+	history     []string // 执行过的语句
+	historyFile string   // 历史记录文件, 为空时不保存
+}
+
+// 创建控制台, 从历史记录文件加载历史语句
+//
+// @Author: 罗德
+// @Date: 2024/7/8
+func newConsole(dialer dialectors.IDialer, in io.Reader, out io.Writer, historyFile string) *console {
+	c := &console{
+		dialer:      dialer,
+		in:          bufio.NewScanner(in),
+		out:         out,
+		format:      formatTable,
+		historyFile: historyFile,
+	}
+	c.in.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	if historyFile != "" {
+		if data, err := os.ReadFile(historyFile); err == nil {
+			for _, line := range strings.Split(string(data), "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					c.history = append(c.history, line)
+				}
+			}
+		}
+	}
+	return c
+}
+
+// 读取并执行输入, 直到输入结束或执行 :quit
+//
+// @Author: 罗德
+// @Date: 2024/7/8
+func (c *console) run() {
+	var buf strings.Builder
+	for {
+		if buf.Len() == 0 {
+			fmt.Fprintf(c.out, "(%s) > ", c.space)
+		} else {
+			fmt.Fprint(c.out, "     -> ")
+		}
+		if !c.in.Scan() {
+			fmt.Fprintln(c.out)
+			return
+		}
+		line := strings.TrimSpace(c.in.Text())
+		if buf.Len() == 0 {
+			if line == "" {
+				continue
+			}
+			if strings.HasPrefix(line, ":") {
+				if !c.command(line) {
+					return
+				}
+				continue
+			}
+		}
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(line)
+		// 以分号结尾时执行, 否则继续读取下一行
+		if !strings.HasSuffix(line, ";") {
+			continue
+		}
+		stmt := strings.TrimSpace(strings.TrimSuffix(buf.String(), ";"))
+		buf.Reset()
+		if stmt != "" {
+			c.addHistory(stmt)
+			c.execute(stmt)
+		}
+	}
+}
+
+// 执行控制台命令, 返回false表示退出
+//
+// @Author: 罗德
+// @Date: 2024/7/8
+func (c *console) command(line string) bool {
+	fields := strings.Fields(line)
+	arg := ""
+	if len(fields) > 1 {
+		arg = fields[1]
+	}
+	switch fields[0] {
+	case ":quit", ":exit", ":q":
+		return false
+	case ":help", ":h":
+		fmt.Fprint(c.out, help)
+	case ":space":
+		if arg == "" {
+			fmt.Fprintf(c.out, "当前图空间: %s\n", c.space)
+			break
+		}
+		if err := c.useSpace(arg); err != nil {
+			fmt.Fprintln(c.out, "[ERROR]", err)
+		}
+	case ":explain":
+		switch arg {
+		case "":
+			// 不带参数时切换开关
+			if c.explain == "" {
+				c.explain = "row"
+			} else {
+				c.explain = ""
+			}
+		case "off":
+			c.explain = ""
+		case "row", "dot", "tck":
+			c.explain = arg
+		default:
+			fmt.Fprintf(c.out, "[ERROR] 不支持的执行计划格式: %s, 可选 row、dot、tck、off\n", arg)
+			return true
+		}
+		if c.explain == "" {
+			fmt.Fprintln(c.out, "explain 已关闭")
+		} else {
+			fmt.Fprintf(c.out, "explain 已开启, 格式: %s\n", c.explain)
+		}
+	case ":format":
+		switch arg {
+		case formatTable, formatJSON, formatCSV:
+			c.format = arg
+			fmt.Fprintf(c.out, "输出格式: %s\n", c.format)
+		default:
+			fmt.Fprintf(c.out, "[ERROR] 不支持的输出格式: %s, 可选 table、json、csv\n", arg)
+		}
+	case ":history":
+		start := 0
+		if len(c.history) > 20 {
+			start = len(c.history) - 20
+		}
+		for i := start; i < len(c.history); i++ {
+			fmt.Fprintf(c.out, "%4d  %s\n", i+1, c.history[i])
+		}
+	default:
+		fmt.Fprintf(c.out, "[ERROR] 未知的命令: %s, 输入 :help 查看帮助\n", fields[0])
+	}
+	return true
+}
+
+// 切换图空间, 先执行 use 校验图空间是否存在
+//
+// @Author: 罗德
+// @Date: 2024/7/8
+func (c *console) useSpace(space string) error {
+	if _, err := c.dialer.Execute(fmt.Sprintf("use `%s`", space)); err != nil {
+		return err
+	}
+	c.setSpace(space)
+	return nil
+}
+
+func (c *console) setSpace(space string) {
+	c.space = space
+	if user, ok := c.dialer.(spaceUser); ok {
+		user.UseSpace(space)
+	}
+}
+
+// 执行语句并输出结果与耗时
+//
+// @Author: 罗德
+// @Date: 2024/7/8
+func (c *console) execute(stmt string) {
+	ngql := stmt
+	if c.explain != "" {
+		// 多条语句需要以花括号包裹
+		if strings.Contains(stmt, ";") {
+			stmt = "{" + stmt + "}"
+		}
+		ngql = fmt.Sprintf(`explain format="%s" %s`, c.explain, stmt)
+	}
+	start := time.Now()
+	result, err := c.dialer.Execute(ngql)
+	elapsed := time.Since(start)
+	if err != nil {
+		fmt.Fprintln(c.out, "[ERROR]", err)
+		return
+	}
+	// 语句中包含 use 时同步当前图空间
+	if space := result.GetSpaceName(); space != "" && space != c.space {
+		c.setSpace(space)
+	}
+
+	if c.explain != "" {
+		if p, err := result.Plan(); err == nil {
+			fmt.Fprint(c.out, p.String())
+		}
+	} else if len(result.GetColNames()) > 0 {
+		if err = c.print(result); err != nil {
+			fmt.Fprintln(c.out, "[ERROR]", err)
+			return
+		}
+	}
+	fmt.Fprintf(c.out, "%d 行 (耗时 %s, 服务端 %s)\n\n", result.GetRowSize(), elapsed.Round(time.Microsecond),
+		time.Duration(result.GetLatency())*time.Microsecond)
+}
+
+// 按输出格式打印结果集
+//
+// @Author: 罗德
+// @Date: 2024/7/8
+func (c *console) print(result *dialectors.ResultSet) error {
+	switch c.format {
+	case formatJSON:
+		rows := make([]map[string]interface{}, 0, result.GetRowSize())
+		if err := result.UnmarshalResultSet(&rows); err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.out, string(data))
+	case formatCSV:
+		w := csv.NewWriter(c.out)
+		table := result.AsStringTable()
+		for i, row := range table {
+			if i > 0 {
+				// 字符串值去掉 nebula 输出的引号, null 输出为空
+				for j, cell := range row {
+					if cell == "__NULL__" {
+						cell = ""
+					}
+					row[j] = unquote(cell)
+				}
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		return w.Error()
+	default:
+		printTable(c.out, result.AsStringTable())
+	}
+	return nil
+}
+
+// 记录历史语句, 多行语句合并为一行
+//
+// @Author: 罗德
+// @Date: 2024/7/8
+func (c *console) addHistory(stmt string) {
+	stmt = strings.Join(strings.Fields(stmt), " ")
+	c.history = append(c.history, stmt)
+	if c.historyFile == "" {
+		return
+	}
+	file, err := os.OpenFile(c.historyFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintln(file, stmt)
+}
+
+// 打印对齐的表格, 第一行为表头
+//
+// @Author: 罗德
+// @Date: 2024/7/8
+func printTable(out io.Writer, table [][]string) {
+	widths := make([]int, len(table[0]))
+	for _, row := range table {
+		for j, cell := range row {
+			if w := displayWidth(cell); w > widths[j] {
+				widths[j] = w
+			}
+		}
+	}
+	var border strings.Builder
+	border.WriteString("+")
+	for _, w := range widths {
+		border.WriteString(strings.Repeat("-", w+2) + "+")
+	}
+	fmt.Fprintln(out, border.String())
+	for i, row := range table {
+		var line strings.Builder
+		line.WriteString("|")
+		for j, cell := range row {
+			line.WriteString(" " + cell + strings.Repeat(" ", widths[j]-displayWidth(cell)) + " |")
+		}
+		fmt.Fprintln(out, line.String())
+		if i == 0 {
+			fmt.Fprintln(out, border.String())
+		}
+	}
+	fmt.Fprintln(out, border.String())
+}
+
+// 终端显示宽度, 中日韩等宽字符占两列
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hangul, r) || unicode.Is(unicode.Hiragana, r) ||
+			unicode.Is(unicode.Katakana, r) || (r >= 0xFF01 && r <= 0xFF60) || (r >= 0x3000 && r <= 0x303F) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// 去掉 nebula 字符串值的双引号
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+const help = `输入以分号结尾的 nGQL 执行, 可以跨多行输入。控制台命令:
+  :space <name>                   切换图空间, 不带参数时显示当前图空间
+  :explain [row|dot|tck|off]      开启或关闭执行计划, 不带参数时切换开关
+  :format table|json|csv          设置输出格式
+  :history                        显示最近 20 条历史语句
+  :help                           显示帮助
+  :quit                           退出
+`
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"nebula-orm-go/dialectors/mock"
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{s: "", want: 0},
+		{s: "name", want: 4},
+		{s: "罗德", want: 4},
+		{s: "a甲b", want: 4},
+		{s: "한글", want: 4},
+		{s: "カナ", want: 4},
+		{s: "ａ，", want: 4},
+		{s: "é", want: 1},
+	}
+	for _, tt := range tests {
+		if got := displayWidth(tt.s); got != tt.want {
+			t.Errorf("displayWidth(%q) = %d, 期望 %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestPrintTable(t *testing.T) {
+	var out bytes.Buffer
+	printTable(&out, [][]string{
+		{"name", "age"},
+		{`"罗德"`, "30"},
+		{`"Tom"`, "__NULL__"},
+	})
+	want := "+--------+----------+\n" +
+		"| name   | age      |\n" +
+		"+--------+----------+\n" +
+		"| \"罗德\" | 30       |\n" +
+		"| \"Tom\"  | __NULL__ |\n" +
+		"+--------+----------+\n"
+	if out.String() != want {
+		t.Errorf("输出:\n%s\n期望:\n%s", out.String(), want)
+	}
+}
+
+// 使用模拟拨号器运行控制台, 返回控制台的输出
+func runConsole(t *testing.T, dialer *mock.Dialer, input string) string {
+	t.Helper()
+	var out bytes.Buffer
+	newConsole(dialer, strings.NewReader(input), &out, "").run()
+	if err := dialer.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	return out.String()
+}
+
+// 跨多行的语句在分号处合并执行, 空行与控制台命令只在语句开始时处理
+func TestRunMultiLine(t *testing.T) {
+	dialer := mock.New()
+	dialer.Expect("use `test`")
+	dialer.Expect("fetch prop on person 'a'\nyield person.name as name").
+		WillReturnRows([]string{"name"}, []interface{}{"甲"})
+	dialer.Expect("match (v)\n:format\nreturn v").WillReturnError(errors.New("语法错误"))
+
+	out := runConsole(t, dialer, ":space test\n"+
+		"\n"+
+		"fetch prop on person 'a'\n"+
+		"  yield person.name as name;\n"+
+		"match (v)\n"+
+		":format\n"+
+		"return v;\n"+
+		";\n"+
+		":quit\n"+
+		"show spaces;\n")
+
+	for _, want := range []string{
+		"(test) > ",
+		"     -> ",
+		"| name |\n",
+		"| \"甲\" |\n",
+		"1 行 (耗时 ",
+		"[ERROR] 语法错误\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("输出中没有 %q:\n%s", want, out)
+		}
+	}
+	if executed := dialer.Executed(); len(executed) != 3 {
+		t.Errorf("执行了 %d 条语句, 期望 3 条: %q", len(executed), executed)
+	}
+}
+
+// 多行语句记录为一行历史
+func TestRunHistory(t *testing.T) {
+	dialer := mock.New()
+	dialer.Expect("show\nspaces")
+	dialer.Expect("show spaces")
+	var out bytes.Buffer
+	c := newConsole(dialer, strings.NewReader("show\n  spaces;\nshow spaces;\n:history\n"), &out, "")
+	c.run()
+	if err := dialer.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	if len(c.history) != 2 || c.history[0] != "show spaces" {
+		t.Errorf("history = %q", c.history)
+	}
+	if !strings.Contains(out.String(), "   1  show spaces\n   2  show spaces\n") {
+		t.Errorf("输出:\n%s", out.String())
+	}
+}
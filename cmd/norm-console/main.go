@@ -0,0 +1,97 @@
+// norm-console 基于 NebulaDialer 的交互式 nGQL 控制台, 以对齐的表格、JSON 或 CSV 输出结果, 并显示每条语句的耗时。
+//
+// 用法:
+//
+//	norm-console -addr 127.0.0.1:9669 -user root -password nebula -space space_luode
+//	norm-console -config dialer.json -format json
+//
+// -config 为 config.DialerConfig 的 JSON 文件, 命令行参数会覆盖文件中的同名配置。输入 :help 查看控制台命令。
+//
+// @Author: 罗德
+// @Date: 2024/7/8
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"nebula-orm-go/config"
+	"nebula-orm-go/dialectors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "norm-console:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		configPath = flag.String("config", "", "config.DialerConfig 的 JSON 文件")
+		addr       = flag.String("addr", "", "graphd 地址, 多个以逗号分隔, 例如 127.0.0.1:9669")
+		user       = flag.String("user", "", "认证用户名")
+		password   = flag.String("password", "", "认证密码")
+		space      = flag.String("space", "", "启动后使用的图空间")
+		format     = flag.String("format", formatTable, "输出格式: table、json、csv")
+		history    = flag.String("history", defaultHistoryFile(), "历史记录文件, 为空时不保存")
+	)
+	flag.Parse()
+
+	var cfg config.DialerConfig
+	if *configPath != "" {
+		data, err := os.ReadFile(*configPath)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(data, &cfg); err != nil {
+			return fmt.Errorf("解析配置文件失败: %w", err)
+		}
+	}
+	if *addr != "" {
+		cfg.Addresses = strings.Split(*addr, ",")
+	}
+	if *user != "" {
+		cfg.Username = *user
+	}
+	if *password != "" {
+		cfg.Password = *password
+	}
+	if *space != "" {
+		cfg.Space = *space
+	}
+	if len(cfg.Addresses) == 0 {
+		flag.Usage()
+		return fmt.Errorf("必须指定 graphd 地址")
+	}
+
+	dialer, err := dialectors.NewNebulaDialer(cfg)
+	if err != nil {
+		return err
+	}
+	defer dialer.Close()
+
+	c := newConsole(dialer, os.Stdin, os.Stdout, *history)
+	if !c.command(":format " + *format) {
+		return nil
+	}
+	if cfg.Space != "" {
+		if err = c.useSpace(cfg.Space); err != nil {
+			return err
+		}
+	}
+	c.run()
+	return nil
+}
+
+// 默认的历史记录文件: ~/.norm_console_history
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".norm_console_history")
+}
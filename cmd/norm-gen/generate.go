@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"nebula-orm-go/dialectors"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 生成选项
+//
+// @Author: 罗德
+// @Date: 2024/7/7
+type options struct {
+	space   string   // 图空间名称
+	pkg     string   // 生成代码的包名
+	tags    []string // 只生成这些标签, 为空时生成全部
+	edges   []string // 只生成这些边类型, 为空时生成全部
+	noTags  bool     // 不生成标签
+	noEdges bool     // 不生成边类型
+}
+
+// 属性定义, 对应 describe tag/edge 的一行
+//
+// @Author: 罗德
+// @Date: 2024/7/7
+type prop struct {
+	name    string
+	typ     string
+	comment string
+}
+
+// 标签或边类型定义
+//
+// @Author: 罗德
+// @Date: 2024/7/7
+type schema struct {
+	name  string
+	edge  bool
+	props []prop
+}
+
+// 读取图空间的标签与边类型, 生成格式化后的Go源码
+//
+// @Author: 罗德
+// @Date: 2024/7/7
+func generate(dialer dialectors.IDialer, opt options) ([]byte, error) {
+	var schemas []*schema
+	if !opt.noTags {
+		tags, err := readSchemas(dialer, opt.space, "tag", opt.tags)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, tags...)
+	}
+	if !opt.noEdges {
+		edges, err := readSchemas(dialer, opt.space, "edge", opt.edges)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, edges...)
+	}
+	if len(schemas) == 0 {
+		return nil, fmt.Errorf("图空间[%s]中没有需要生成的标签或边类型", opt.space)
+	}
+	return render(opt, schemas)
+}
+
+// 结构定义的中文名称
+var kinds = map[string]string{"tag": "标签", "edge": "边类型"}
+
+// 通过 show tags/edges 与 describe 读取结构定义, names 不为空时只读取其中的标签或边类型
+//
+// @Author: 罗德
+// @Date: 2024/7/7
+func readSchemas(dialer dialectors.IDialer, space, kind string, names []string) ([]*schema, error) {
+	if len(names) == 0 {
+		result, err := execute(dialer, space, fmt.Sprintf("show %ss", kind))
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range result {
+			names = append(names, row["Name"])
+		}
+	}
+	schemas := make([]*schema, 0, len(names))
+	for _, name := range names {
+		result, err := execute(dialer, space, fmt.Sprintf("describe %s `%s`", kind, name))
+		if err != nil {
+			return nil, fmt.Errorf("读取%s[%s]失败: %w", kinds[kind], name, err)
+		}
+		item := &schema{name: name, edge: kind == "edge"}
+		for _, row := range result {
+			item.props = append(item.props, prop{name: row["Field"], typ: row["Type"], comment: row["Comment"]})
+		}
+		schemas = append(schemas, item)
+	}
+	return schemas, nil
+}
+
+// 在指定图空间中执行语句, 每行按列名返回字符串形式的值, null 为空字符串
+//
+// @Author: 罗德
+// @Date: 2024/7/7
+func execute(dialer dialectors.IDialer, space, ngql string) ([]map[string]string, error) {
+	result, err := dialer.Execute(fmt.Sprintf("use `%s`; %s", space, ngql))
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]map[string]string, 0, result.GetRowSize())
+	for i := 0; i < result.GetRowSize(); i++ {
+		record, err := result.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]string, len(result.GetColNames()))
+		for _, col := range result.GetColNames() {
+			value, err := record.GetValueByColName(col)
+			if err != nil {
+				return nil, err
+			}
+			if s, err := value.AsString(); err == nil {
+				row[col] = s
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// 生成源码
+//
+// @Author: 罗德
+// @Date: 2024/7/7
+func render(opt options, schemas []*schema) ([]byte, error) {
+	var body bytes.Buffer
+	imports := map[string]bool{"nebula-orm-go/model": true}
+	for _, item := range schemas {
+		name := goName(item.name)
+		embed, method, kind := "model.VModel", "TagName", "标签"
+		if item.edge {
+			embed, method, kind = "model.EModel", "EdgeName", "边类型"
+		}
+		fmt.Fprintf(&body, "\n// %s %s %s\ntype %s struct {\n\t%s\n", name, kind, item.name, name, embed)
+		fields := make(map[string]bool)
+		for _, p := range item.props {
+			field := goName(p.name)
+			// 属性名转换后重名时追加序号
+			for i := 2; fields[field]; i++ {
+				field = fmt.Sprintf("%s%d", goName(p.name), i)
+			}
+			fields[field] = true
+			typ, note := goType(p.typ)
+			if typ == "time.Time" {
+				imports["time"] = true
+			}
+			comment := strings.TrimSpace(strings.Join([]string{p.comment, note}, " "))
+			if comment != "" {
+				comment = " // " + strings.ReplaceAll(comment, "\n", " ")
+			}
+			fmt.Fprintf(&body, "\t%s %s `nebula:\"%s\"`%s\n", field, typ, p.name, comment)
+		}
+		fmt.Fprintf(&body, "}\n\n// %s 返回%s名称\nfunc (v %s) %s() string {\n\treturn %q\n}\n",
+			method, kind, name, method, item.name)
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by norm-gen from space %s. DO NOT EDIT.\n\npackage %s\n\nimport (\n", opt.space, opt.pkg)
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(&src, "\t%q\n", path)
+	}
+	src.WriteString(")\n")
+	src.Write(body.Bytes())
+	return format.Source(src.Bytes())
+}
+
+// 将 nebula 属性类型映射为Go类型, 解码不支持的类型映射为 string 并返回说明
+//
+// @Author: 罗德
+// @Date: 2024/7/7
+func goType(typ string) (string, string) {
+	switch lower := strings.ToLower(typ); {
+	case lower == "bool":
+		return "bool", ""
+	case lower == "int64" || lower == "int" || lower == "timestamp":
+		return "int64", ""
+	case lower == "int32" || lower == "int16" || lower == "int8":
+		return lower, ""
+	case lower == "float":
+		return "float32", ""
+	case lower == "double":
+		return "float64", ""
+	case lower == "string" || strings.HasPrefix(lower, "fixed_string"):
+		return "string", ""
+	case lower == "datetime":
+		return "time.Time", ""
+	}
+	return "string", fmt.Sprintf("nebula 类型为 %s, 需要手工转换", typ)
+}
+
+// 常见缩写, 转换为全大写
+var initialisms = map[string]bool{"id": true, "vid": true, "uid": true, "url": true, "ip": true, "api": true,
+	"http": true, "json": true, "uuid": true, "sql": true}
+
+// 将 snake_case 的名称转换为导出的 CamelCase, 例如 user_id -> UserID
+//
+// @Author: 罗德
+// @Date: 2024/7/7
+func goName(name string) string {
+	var sb strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if initialisms[strings.ToLower(word)] {
+			sb.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		sb.WriteString(string(runes))
+	}
+	result := sb.String()
+	// 以数字或没有大小写的字符(例如中文)开头时无法导出
+	if result == "" || !unicode.IsUpper([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 使用 go test ./cmd/norm-gen -update 重新生成 testdata 下的快照文件
+var update = flag.Bool("update", false, "重新生成 testdata 下的 golden 快照文件")
+
+// 属性名转换为导出的Go名称
+func TestGoName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"chain_key", "ChainKey"},
+		{"user_id", "UserID"},
+		{"api_url", "APIURL"},
+		{"Vid", "VID"},
+		{"level", "Level"},
+		{"a-b.c", "ABC"},
+		{"2nd_level", "X2ndLevel"},
+		{"__", "X"},
+		{"节点_名称", "X节点名称"},
+	}
+	for _, tt := range tests {
+		if got := goName(tt.name); got != tt.want {
+			t.Errorf("goName(%q) = %q, 期望 %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// nebula 属性类型映射为Go类型
+func TestGoType(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want string
+		note bool // 是否需要手工转换的说明
+	}{
+		{"bool", "bool", false},
+		{"int64", "int64", false},
+		{"INT", "int64", false},
+		{"timestamp", "int64", false},
+		{"int32", "int32", false},
+		{"int16", "int16", false},
+		{"int8", "int8", false},
+		{"float", "float32", false},
+		{"double", "float64", false},
+		{"string", "string", false},
+		{"fixed_string(32)", "string", false},
+		{"datetime", "time.Time", false},
+		{"date", "string", true},
+		{"geography(point)", "string", true},
+		{"duration", "string", true},
+	}
+	for _, tt := range tests {
+		got, note := goType(tt.typ)
+		if got != tt.want || (note != "") != tt.note {
+			t.Errorf("goType(%q) = %q, %q, 期望 %q, 说明 %v", tt.typ, got, note, tt.want, tt.note)
+		}
+	}
+}
+
+// 生成的源码与快照一致
+func TestRenderGolden(t *testing.T) {
+	schemas := []*schema{
+		{name: "player", props: []prop{
+			{name: "name", typ: "fixed_string(32)", comment: "姓名"},
+			{name: "age", typ: "int64"},
+			{name: "birthday", typ: "datetime"},
+			{name: "home", typ: "geography(point)", comment: "住址\n坐标"},
+			{name: "user_id", typ: "string"},
+			{name: "user-id", typ: "string"},
+		}},
+		{name: "follow", edge: true, props: []prop{
+			{name: "degree", typ: "double"},
+		}},
+	}
+	src, err := render(options{space: "basketball", pkg: "models"}, schemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "render", string(src))
+
+	// 没有 datetime 属性时不导入 time
+	src, err = render(options{space: "basketball", pkg: "models"}, schemas[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "render_edge", string(src))
+}
+
+// 与 testdata/<name>.golden 比较, -update 时重新生成
+func assertGolden(t *testing.T, name string, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取快照失败, 请使用 -update 生成: %v", err)
+	}
+	if string(want) != got {
+		t.Errorf("%s 与快照不一致\n期望:\n%s\n实际:\n%s", name, want, got)
+	}
+}
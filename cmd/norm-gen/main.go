@@ -0,0 +1,107 @@
+// norm-gen 读取已有图空间的标签与边类型, 生成嵌入 model.VModel/model.EModel 的Go结构体。
+//
+// 用法:
+//
+//	norm-gen -addr 127.0.0.1:9669 -user root -password nebula -space space_luode -package models -out models/gen.go
+//	norm-gen -config dialer.json -tags player,team -no-edges
+//
+// -config 为 config.DialerConfig 的 JSON 文件, 命令行参数会覆盖文件中的同名配置。
+//
+// @Author: 罗德
+// @Date: 2024/7/7
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"nebula-orm-go/config"
+	"nebula-orm-go/dialectors"
+	"os"
+	"strings"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "norm-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		configPath = flag.String("config", "", "config.DialerConfig 的 JSON 文件")
+		addr       = flag.String("addr", "", "graphd 地址, 多个以逗号分隔, 例如 127.0.0.1:9669")
+		user       = flag.String("user", "", "认证用户名")
+		password   = flag.String("password", "", "认证密码")
+		space      = flag.String("space", "", "图空间名称")
+		pkg        = flag.String("package", "models", "生成代码的包名")
+		out        = flag.String("out", "", "输出文件, 为空时输出到标准输出")
+		tags       = flag.String("tags", "", "只生成这些标签, 以逗号分隔")
+		edges      = flag.String("edges", "", "只生成这些边类型, 以逗号分隔")
+		noTags     = flag.Bool("no-tags", false, "不生成标签")
+		noEdges    = flag.Bool("no-edges", false, "不生成边类型")
+	)
+	flag.Parse()
+
+	var cfg config.DialerConfig
+	if *configPath != "" {
+		data, err := os.ReadFile(*configPath)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(data, &cfg); err != nil {
+			return fmt.Errorf("解析配置文件失败: %w", err)
+		}
+	}
+	if *addr != "" {
+		cfg.Addresses = splitList(*addr)
+	}
+	if *user != "" {
+		cfg.Username = *user
+	}
+	if *password != "" {
+		cfg.Password = *password
+	}
+	if *space != "" {
+		cfg.Space = *space
+	}
+	if len(cfg.Addresses) == 0 || cfg.Space == "" {
+		flag.Usage()
+		return fmt.Errorf("必须指定 graphd 地址与图空间")
+	}
+
+	dialer, err := dialectors.NewNebulaDialer(cfg)
+	if err != nil {
+		return err
+	}
+	defer dialer.Close()
+
+	src, err := generate(dialer, options{
+		space:   cfg.Space,
+		pkg:     *pkg,
+		tags:    splitList(*tags),
+		edges:   splitList(*edges),
+		noTags:  *noTags,
+		noEdges: *noEdges,
+	})
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*out, src, 0o644)
+}
+
+// 解析逗号分隔的列表, 忽略空项
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"nebula-orm-go/constants"
+	"nebula-orm-go/metrics"
 	"time" // 引入time包，用于处理时间相关的功能，如超时和空闲时间设定
 )
 
@@ -19,6 +20,7 @@ type DialerConfig struct {
 	MaxConnPoolSize int           `json:"max_conn_pool_size" yaml:"max_conn_pool_size"` // 连接池最大连接数
 	MinConnPoolSize int           `json:"min_conn_pool_size" yaml:"min_conn_pool_size"` // 连接池最小连接数
 	InitSql         string        `json:"init_sql" yaml:"init_sql"`                     // 初始化sql(立刻尝试使用刚创建的空间、点、边可能会失败，因为创建是异步实现的)
+	Metrics         metrics.ISink `json:"-" yaml:"-"`                                   // 连接池指标采集(会话等待时间、使用中的会话数), 默认不采集
 }
 
 // LoadDefault 方法用于加载默认配置项到DialerConfig实例中，如果相应配置项未被显式设置
@@ -35,4 +37,7 @@ func (config *DialerConfig) LoadDefault() {
 	if config.MaxConnPoolSize <= 0 {
 		config.MaxConnPoolSize = constants.DefaultMaxConnPoolSize // 若最大连接池大小未设置或设置不合理，则使用默认值
 	}
+	if config.Metrics == nil {
+		config.Metrics = metrics.Nop // 未配置指标采集时不做记录
+	}
 }
@@ -3,6 +3,10 @@ package config
 import (
 	nebula "github.com/vesoft-inc/nebula-go/v3"
 	"nebula-orm-go/constants"
+	"nebula-orm-go/logger"
+	"nebula-orm-go/metrics"
+	"nebula-orm-go/tracing"
+	"time"
 )
 
 // Option 定义了一个函数类型，该类型的函数接收一个指向Config的指针，并对其进行修改以应用特定的配置选项。
@@ -15,7 +19,16 @@ type Option func(c *Config)
 type Config struct {
 	Limit     int           // Limit 限制查询记录
 	DebugMode bool          // DebugMode 指示是否开启调试模式，若为true，则可能会输出额外的日志信息以帮助调试。
-	Logger    nebula.Logger // Logger 提供日志记录功能的接口，用于记录与数据库交互过程中的信息。
+	Logger    nebula.Logger // Logger 提供日志记录功能的接口，用于记录与数据库交互过程中的信息。(仅支持字符串日志, 推荐使用 StructuredLogger)
+
+	StructuredLogger logger.Logger   // StructuredLogger 结构化日志记录器, 未设置时由 Logger 适配而来
+	SlowThreshold    time.Duration   // SlowThreshold 慢语句阈值, 执行耗时超过该值时以Warn级别记录nGQL、耗时、服务端耗时和图空间, <=0 表示关闭
+	RedactValues     bool            // RedactValues 日志中是否对nGQL的属性值做脱敏处理
+	Metrics          metrics.ISink   // Metrics 语句指标采集(按语句类型与错误码计数、耗时直方图), 默认不采集
+	Tracer           tracing.ITracer // Tracer 语句级链路追踪, 每条语句创建一个 Span, 默认不追踪
+	BatchSize        int             // BatchSize 分块写入时每条语句包含的点/边数量, 默认 500
+	BatchConcurrency int             // BatchConcurrency 分块写入时同时执行的语句数量, 默认 4, 不应超过连接池大小
+	VidType          string          // VidType 图空间的VID类型(INT64 或 FIXED_STRING(n)), 设置后写入前校验点边的VID, 为空时不校验
 }
 
 // LoadDefault 方法为Config结构体提供了默认配置加载逻辑。
@@ -26,7 +39,102 @@ func (config *Config) LoadDefault() {
 	if config.Logger == nil {
 		config.Logger = &nebula.DefaultLogger{} // 当用户未提供日志记录器时，使用Nebula官方的默认日志记录器。
 	}
+	if config.StructuredLogger == nil {
+		config.StructuredLogger = logger.NewNebulaLogger(config.Logger) // 当用户未提供结构化日志记录器时，适配 Logger 使用。
+	}
 	if config.Limit <= 0 {
 		config.Limit = constants.DefaultLimit // 若限制查询记录不合理，则使用默认值
 	}
+	if config.Metrics == nil {
+		config.Metrics = metrics.Nop // 未配置指标采集时不做记录
+	}
+	if config.Tracer == nil {
+		config.Tracer = tracing.Nop // 未配置链路追踪时不做记录
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = constants.DefaultBatchSize // 若分块大小不合理，则使用默认值
+	}
+	if config.BatchConcurrency <= 0 {
+		config.BatchConcurrency = constants.DefaultBatchConcurrency // 若分块并发数不合理，则使用默认值
+	}
+}
+
+// WithStructuredLogger 设置结构化日志记录器
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+func WithStructuredLogger(log logger.Logger) Option {
+	return func(c *Config) {
+		c.StructuredLogger = log
+	}
+}
+
+// WithSlowThreshold 设置慢语句阈值, 执行耗时超过该值的语句会以Warn级别记录
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+func WithSlowThreshold(threshold time.Duration) Option {
+	return func(c *Config) {
+		c.SlowThreshold = threshold
+	}
+}
+
+// WithRedactValues 开启日志中nGQL属性值的脱敏
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+func WithRedactValues() Option {
+	return func(c *Config) {
+		c.RedactValues = true
+	}
+}
+
+// WithMetrics 设置语句指标采集
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func WithMetrics(sink metrics.ISink) Option {
+	return func(c *Config) {
+		c.Metrics = sink
+	}
+}
+
+// WithTracer 设置语句级链路追踪
+//
+// @Author: 罗德
+// @Date: 2024/6/20
+func WithTracer(tracer tracing.ITracer) Option {
+	return func(c *Config) {
+		c.Tracer = tracer
+	}
+}
+
+// WithBatchSize 设置分块写入时每条语句包含的点/边数量
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func WithBatchSize(size int) Option {
+	return func(c *Config) {
+		c.BatchSize = size
+	}
+}
+
+// WithBatchConcurrency 设置分块写入时同时执行的语句数量
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func WithBatchConcurrency(concurrency int) Option {
+	return func(c *Config) {
+		c.BatchConcurrency = concurrency
+	}
+}
+
+// WithVidType 设置图空间的VID类型(INT64 或 FIXED_STRING(n)), 写入前按该类型校验点边的VID
+//
+// @Author: 罗德
+// @Date: 2024/7/5
+func WithVidType(vidType string) Option {
+	return func(c *Config) {
+		c.VidType = vidType
+	}
 }
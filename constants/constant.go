@@ -29,12 +29,14 @@ const (
 	PolicyHash // 自动赋予下一个整数值，即1
 )
 
-// 常量定义了默认的超时、空闲时间和最大连接池大小、限制查询记录
+// 常量定义了默认的超时、空闲时间和最大连接池大小、限制查询记录、分块写入的块大小与并发数
 const (
-	DefaultTimeout         = 60 * time.Second // 默认请求超时时间为60秒
-	DefaultIdleTime        = 10 * time.Minute // 默认连接空闲时间10分钟，超过此时间的空闲连接将被关闭
-	DefaultMaxConnPoolSize = 20               // 默认的最大连接池大小为20
-	DefaultLimit           = 1000             // 限制查询记录
+	DefaultTimeout          = 60 * time.Second // 默认请求超时时间为60秒
+	DefaultIdleTime         = 10 * time.Minute // 默认连接空闲时间10分钟，超过此时间的空闲连接将被关闭
+	DefaultMaxConnPoolSize  = 20               // 默认的最大连接池大小为20
+	DefaultLimit            = 1000             // 限制查询记录
+	DefaultBatchSize        = 500              // 分块写入时每条语句包含的点/边数量
+	DefaultBatchConcurrency = 4                // 分块写入时同时执行的语句数量
 )
 
 // 常量定义了查询的返回别名
@@ -0,0 +1,403 @@
+package converts
+
+import (
+	"flag"
+	"nebula-orm-go/clause"
+	"nebula-orm-go/constants"
+	"nebula-orm-go/model"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 使用 go test ./converts -update 重新生成 testdata 下的快照文件
+var update = flag.Bool("update", false, "重新生成 testdata 下的 golden 快照文件")
+
+// 测试用点结构体
+type goldenVertex struct {
+	model.VModel
+	ChainKey  string `nebula:"chain_key"`
+	ParentKey string `nebula:"parent_key"`
+	Level     int    `nebula:"level"`
+}
+
+func (v goldenVertex) TagName() string {
+	return "test_vertex"
+}
+
+// 测试用边结构体
+type goldenEdge struct {
+	model.EModel
+	Test   string  `nebula:"test"`
+	Weight float64 `nebula:"weight"`
+}
+
+func (v goldenEdge) EdgeName() string {
+	return "test_edge"
+}
+
+// 与 goldenVertex 不同标签的点结构体
+type goldenOtherVertex struct {
+	model.VModel
+	Name string `nebula:"name"`
+}
+
+func (v goldenOtherVertex) TagName() string {
+	return "other_vertex"
+}
+
+// 与 goldenVertex 相同标签但属性列不同的点结构体
+type goldenPartialVertex struct {
+	model.VModel
+	ChainKey string `nebula:"chain_key"`
+}
+
+func (v goldenPartialVertex) TagName() string {
+	return "test_vertex"
+}
+
+// 与 goldenEdge 不同边类型的边结构体
+type goldenOtherEdge struct {
+	model.EModel
+	Name string `nebula:"name"`
+}
+
+func (v goldenOtherEdge) EdgeName() string {
+	return "other_edge"
+}
+
+// 带乐观锁版本号的点结构体
+type goldenVersionVertex struct {
+	model.VModel
+	ChainKey string `nebula:"chain_key"`
+	Version  int64  `nebula:"version,version"`
+}
+
+func (v goldenVersionVertex) TagName() string {
+	return "version_vertex"
+}
+
+// 带自动时间与默认值字段的点结构体
+type goldenAutoVertex struct {
+	model.VModel
+	Status    string    `nebula:"status,default:'new'"`
+	CreatedAt int64     `nebula:"created_at,autoCreateTime"`
+	UpdatedAt time.Time `nebula:"updated_at,autoUpdateTime"`
+	DeletedAt time.Time `nebula:"deleted_at,autoUpdateTime:timestamp"`
+}
+
+func (v goldenAutoVertex) TagName() string {
+	return "auto_vertex"
+}
+
+// 混合标签与属性列的点, 用于分组用例
+func goldenMixedVertexs() []model.IVertex {
+	return []model.IVertex{
+		newGoldenVertex("a", constants.PolicyNothing, 1),
+		goldenOtherVertex{VModel: model.VModel{Vid: "x"}, Name: "其它"},
+		goldenPartialVertex{VModel: model.VModel{Vid: "p"}, ChainKey: "部分"},
+		newGoldenVertex("b", constants.PolicyNothing, 2),
+		goldenOtherVertex{VModel: model.VModel{Vid: "y"}, Name: "其它"},
+	}
+}
+
+// 混合边类型的边, 用于分组用例
+func goldenMixedEdges() []model.IEdge {
+	return []model.IEdge{
+		newGoldenEdge("a", "b", constants.PolicyNothing, 1),
+		goldenOtherEdge{EModel: model.EModel{Src: "x", Dst: "y"}, Name: "其它"},
+		newGoldenEdge("b", "c", constants.PolicyNothing, 2),
+	}
+}
+
+func newGoldenVertex(vid interface{}, policy constants.Policy, level int) goldenVertex {
+	return goldenVertex{
+		VModel:    model.VModel{Vid: vid, Policy: policy},
+		ChainKey:  "链路",
+		ParentKey: "无",
+		Level:     level,
+	}
+}
+
+func newGoldenEdge(src, dst interface{}, policy constants.Policy, weight float64) goldenEdge {
+	return goldenEdge{
+		EModel: model.EModel{Src: src, SrcPolicy: policy, Dst: dst, DstPolicy: policy},
+		Test:   "测试",
+		Weight: weight,
+	}
+}
+
+// 批量用例共用的输入, 顺序即期望的输出顺序
+func goldenVertexs() []model.IVertex {
+	vertexs := make([]model.IVertex, 0, 8)
+	for i := 0; i < 8; i++ {
+		vertexs = append(vertexs, newGoldenVertex(string(rune('a'+i)), constants.PolicyNothing, i))
+	}
+	return vertexs
+}
+
+func goldenEdges() []model.IEdge {
+	edges := make([]model.IEdge, 0, 8)
+	for i := 0; i < 8; i++ {
+		edges = append(edges, newGoldenEdge(string(rune('a'+i)), string(rune('b'+i)), constants.PolicyNothing, float64(i)/2))
+	}
+	return edges
+}
+
+func TestConvertGolden(t *testing.T) {
+	cases := []struct {
+		name    string
+		convert func() (string, error)
+	}{
+		{"insert_vertex", func() (string, error) {
+			return ConvertToInsertVertexSql(newGoldenVertex("根节点", constants.PolicyNothing, 1))
+		}},
+		{"insert_vertex_hash", func() (string, error) {
+			return ConvertToInsertVertexSql(newGoldenVertex("根节点", constants.PolicyHash, 1))
+		}},
+		{"insert_vertex_int_vid", func() (string, error) {
+			return ConvertToInsertVertexSql(newGoldenVertex(int64(1024), constants.PolicyNothing, 1))
+		}},
+		{"insert_vertex_pointer", func() (string, error) {
+			v := newGoldenVertex("根节点", constants.PolicyNothing, 1)
+			return ConvertToInsertVertexSql(&v)
+		}},
+		{"insert_vertex_ignore", func() (string, error) {
+			return ConvertToInsertVertexIgnoreSql(newGoldenVertex("根节点", constants.PolicyNothing, 1))
+		}},
+		{"insert_batch_vertex", func() (string, error) {
+			return ConvertToInsertVertexBatchSql(goldenVertexs())
+		}},
+		{"insert_batch_vertex_empty", func() (string, error) {
+			return ConvertToInsertVertexBatchSql(nil)
+		}},
+		{"insert_batch_vertex_mixed_tag", func() (string, error) {
+			return ConvertToInsertVertexBatchSql(goldenMixedVertexs()[:2])
+		}},
+		{"insert_batch_vertex_mixed_fields", func() (string, error) {
+			return ConvertToInsertVertexBatchSql([]model.IVertex{goldenMixedVertexs()[0], goldenMixedVertexs()[2]})
+		}},
+		{"insert_group_vertex", func() (string, error) {
+			sqls, err := ConvertToInsertVertexGroupSql(goldenMixedVertexs())
+			return strings.Join(sqls, "\n"), err
+		}},
+		{"insert_group_vertex_empty", func() (string, error) {
+			sqls, err := ConvertToInsertVertexGroupSql(nil)
+			return strings.Join(sqls, "\n"), err
+		}},
+		{"insert_edge", func() (string, error) {
+			return ConvertToInsertEdgeSql(newGoldenEdge("根节点", "子节点", constants.PolicyNothing, 1.5))
+		}},
+		{"insert_edge_hash", func() (string, error) {
+			return ConvertToInsertEdgeSql(newGoldenEdge("根节点", "子节点", constants.PolicyHash, 1.5))
+		}},
+		{"insert_edge_ignore", func() (string, error) {
+			return ConvertToInsertEdgeIgnoreSql(newGoldenEdge("根节点", "子节点", constants.PolicyNothing, 1.5))
+		}},
+		{"insert_batch_edge", func() (string, error) {
+			return ConvertToInsertEdgeBatchSql(goldenEdges())
+		}},
+		{"insert_batch_edge_empty", func() (string, error) {
+			return ConvertToInsertEdgeBatchSql(nil)
+		}},
+		{"insert_batch_edge_mixed", func() (string, error) {
+			return ConvertToInsertEdgeBatchSql(goldenMixedEdges())
+		}},
+		{"insert_group_edge", func() (string, error) {
+			sqls, err := ConvertToInsertEdgeGroupSql(goldenMixedEdges())
+			return strings.Join(sqls, "\n"), err
+		}},
+		{"delete_vertex", func() (string, error) {
+			return ConvertToDeleteVertexSql(newGoldenVertex("根节点", constants.PolicyNothing, 1))
+		}},
+		{"delete_vertex_hash", func() (string, error) {
+			return ConvertToDeleteVertexSql(newGoldenVertex("根节点", constants.PolicyHash, 1))
+		}},
+		{"delete_batch_vertex", func() (string, error) {
+			return ConvertToDeleteVertexBatchSql(goldenVertexs())
+		}},
+		{"delete_edge", func() (string, error) {
+			return ConvertToDeleteEdgeSql(newGoldenEdge("根节点", "子节点", constants.PolicyNothing, 0))
+		}},
+		{"delete_batch_edge", func() (string, error) {
+			return ConvertToDeleteEdgeBatchSql(goldenEdges())
+		}},
+		{"delete_batch_edge_empty", func() (string, error) {
+			return ConvertToDeleteEdgeBatchSql(nil)
+		}},
+		{"delete_batch_edge_mixed", func() (string, error) {
+			return ConvertToDeleteEdgeBatchSql(goldenMixedEdges())
+		}},
+		{"update_vertex", func() (string, error) {
+			return ConvertToUpdateVertexSql(newGoldenVertex("根节点", constants.PolicyNothing, 1), "parent_key = 'O(∩_∩)O'", "")
+		}},
+		{"update_vertex_where", func() (string, error) {
+			return ConvertToUpdateVertexSql(newGoldenVertex("根节点", constants.PolicyHash, 1), "parent_key = 'O(∩_∩)O'", "parent_key == '无'")
+		}},
+		{"update_vertex_empty_set", func() (string, error) {
+			return ConvertToUpdateVertexSql(newGoldenVertex("根节点", constants.PolicyNothing, 1), "", "")
+		}},
+		{"update_edge", func() (string, error) {
+			return ConvertToUpdateEdgeSql(newGoldenEdge("根节点", "子节点", constants.PolicyNothing, 0), "test = 'O(∩_∩)O'", "")
+		}},
+		{"update_edge_where", func() (string, error) {
+			return ConvertToUpdateEdgeSql(newGoldenEdge("根节点", "子节点", constants.PolicyHash, 0), "test = 'O(∩_∩)O'", "test == '无'")
+		}},
+		{"update_edge_empty_set", func() (string, error) {
+			return ConvertToUpdateEdgeSql(newGoldenEdge("根节点", "子节点", constants.PolicyNothing, 0), "", "")
+		}},
+		{"upsert_vertex", func() (string, error) {
+			return ConvertToUpsertVertexSql(newGoldenVertex("根节点", constants.PolicyNothing, 1), "parent_key = 'O(∩_∩)O'", "")
+		}},
+		{"upsert_vertex_where", func() (string, error) {
+			return ConvertToUpsertVertexSql(newGoldenVertex("根节点", constants.PolicyHash, 1), "parent_key = 'O(∩_∩)O'", "parent_key == '无'")
+		}},
+		{"upsert_vertex_empty_set", func() (string, error) {
+			return ConvertToUpsertVertexSql(newGoldenVertex("根节点", constants.PolicyNothing, 1), "", "")
+		}},
+		{"upsert_edge", func() (string, error) {
+			return ConvertToUpsertEdgeSql(newGoldenEdge("根节点", "子节点", constants.PolicyNothing, 0), "test = 'O(∩_∩)O'", "")
+		}},
+		{"upsert_edge_where", func() (string, error) {
+			return ConvertToUpsertEdgeSql(newGoldenEdge("根节点", "子节点", constants.PolicyHash, 0), "test = 'O(∩_∩)O'", "test == '无'")
+		}},
+		{"upsert_edge_empty_set", func() (string, error) {
+			return ConvertToUpsertEdgeSql(newGoldenEdge("根节点", "子节点", constants.PolicyNothing, 0), "", "")
+		}},
+		{"update_vertex_values_struct", func() (string, error) {
+			v := newGoldenVertex("根节点", constants.PolicyNothing, 0)
+			v.ParentKey = "it's"
+			return ConvertToUpdateVertexValuesSql(v, nil, UpdateOption{})
+		}},
+		{"update_vertex_values_select", func() (string, error) {
+			v := newGoldenVertex("根节点", constants.PolicyNothing, 0)
+			return ConvertToUpdateVertexValuesSql(v, v, UpdateOption{Select: []string{"Level", "parent_key"}})
+		}},
+		{"update_vertex_values_map", func() (string, error) {
+			v := newGoldenVertex("根节点", constants.PolicyHash, 1)
+			return ConvertToUpdateVertexValuesSql(v, map[string]interface{}{"level": 0, "parent_key": nil, "chain_key": "a\\b"},
+				UpdateOption{Omit: []string{"chain_key"}, When: clause.And(clause.Eq("parent_key", "无"), clause.Gt("level", 1))})
+		}},
+		{"update_vertex_values_unknown", func() (string, error) {
+			v := newGoldenVertex("根节点", constants.PolicyNothing, 1)
+			return ConvertToUpdateVertexValuesSql(v, map[string]interface{}{"unknown": 1}, UpdateOption{})
+		}},
+		{"update_vertex_values_empty", func() (string, error) {
+			v := newGoldenVertex("根节点", constants.PolicyNothing, 1)
+			return ConvertToUpdateVertexValuesSql(v, goldenVertex{}, UpdateOption{})
+		}},
+		{"upsert_edge_values", func() (string, error) {
+			e := newGoldenEdge("根节点", "子节点", constants.PolicyNothing, 2)
+			return ConvertToUpsertEdgeValuesSql(e, nil, UpdateOption{When: clause.Or(clause.IsNull("test"), clause.In("test", "a", "b"))})
+		}},
+		{"save_vertex", func() (string, error) {
+			return ConvertToSaveVertexSql(goldenVertex{VModel: model.VModel{Vid: "根节点"}, ChainKey: "链路"})
+		}},
+		{"save_edge", func() (string, error) {
+			return ConvertToSaveEdgeSql(newGoldenEdge("根节点", "子节点", constants.PolicyHash, 0))
+		}},
+		{"update_vertex_values_version", func() (string, error) {
+			v := goldenVersionVertex{VModel: model.VModel{Vid: "根节点"}, ChainKey: "链路", Version: 3}
+			return ConvertToUpdateVertexValuesSql(v, nil, UpdateOption{When: clause.IsNotNull("chain_key")})
+		}},
+		{"save_vertex_version", func() (string, error) {
+			return ConvertToSaveVertexSql(goldenVersionVertex{VModel: model.VModel{Vid: "根节点"}, ChainKey: "链路"})
+		}},
+		{"insert_vertex_auto", func() (string, error) {
+			return ConvertToInsertVertexSql(goldenAutoVertex{VModel: model.VModel{Vid: "根节点"}, CreatedAt: 1719964800})
+		}},
+		{"update_vertex_values_auto", func() (string, error) {
+			v := goldenAutoVertex{VModel: model.VModel{Vid: "根节点"}, Status: "done"}
+			return ConvertToUpdateVertexValuesSql(v, nil, UpdateOption{Omit: []string{"DeletedAt"}})
+		}},
+		{"save_vertex_auto", func() (string, error) {
+			return ConvertToSaveVertexSql(goldenAutoVertex{VModel: model.VModel{Vid: "根节点"}})
+		}},
+		{"update_vertex_values_expr", func() (string, error) {
+			v := newGoldenVertex("根节点", constants.PolicyNothing, 1)
+			return ConvertToUpdateVertexValuesSql(v, map[string]interface{}{
+				"level":      clause.Inc(1),
+				"parent_key": clause.Coalesce("无"),
+				"chain_key":  clause.Expr("chain_key + ? + '?'", "it's"),
+			}, UpdateOption{})
+		}},
+		{"upsert_edge_values_expr", func() (string, error) {
+			e := newGoldenEdge("根节点", "子节点", constants.PolicyNothing, 0)
+			return ConvertToUpsertEdgeValuesSql(e, map[string]interface{}{"weight": clause.Dec(0.5), "test": clause.Append("a", 1)}, UpdateOption{})
+		}},
+		{"update_vertex_values_time_zone", func() (string, error) {
+			v := goldenAutoVertex{VModel: model.VModel{Vid: "根节点"}}
+			at := time.Date(2024, 7, 1, 8, 30, 0, 0, time.FixedZone("CST", 8*3600))
+			return ConvertToUpdateVertexValuesSql(v, map[string]interface{}{"updated_at": at}, UpdateOption{Omit: []string{"DeletedAt"}})
+		}},
+		{"insert_vertex_auto_time", func() (string, error) {
+			at := time.Date(2024, 7, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+			return ConvertToInsertVertexSql(goldenAutoVertex{VModel: model.VModel{Vid: "根节点"}, Status: "done",
+				CreatedAt: 1719964800, UpdatedAt: at, DeletedAt: at})
+		}},
+		{"update_vertex_values_auto_timestamp", func() (string, error) {
+			v := goldenAutoVertex{VModel: model.VModel{Vid: "根节点"}, DeletedAt: time.Unix(1719964800, 0)}
+			return ConvertToUpdateVertexValuesSql(v, nil, UpdateOption{Select: []string{"DeletedAt"}})
+		}},
+		{"update_vertex_values_auto_timestamp_map", func() (string, error) {
+			v := goldenAutoVertex{VModel: model.VModel{Vid: "根节点"}}
+			return ConvertToUpdateVertexValuesSql(v, map[string]interface{}{"deleted_at": time.Unix(1719964800, 0)}, UpdateOption{})
+		}},
+		{"update_vertex_values_expr_vars", func() (string, error) {
+			v := newGoldenVertex("根节点", constants.PolicyNothing, 1)
+			return ConvertToUpdateVertexValuesSql(v, map[string]interface{}{"level": clause.Expr("level + ?", 1, 2)}, UpdateOption{})
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sql, err := c.convert()
+			got := sql + "\n"
+			if err != nil {
+				got = "error: " + err.Error() + "\n"
+			}
+			assertGolden(t, c.name, got)
+		})
+	}
+}
+
+// 批量语句需要保持输入顺序, 多次生成的结果必须完全一致
+func TestConvertBatchDeterministic(t *testing.T) {
+	first, err := ConvertToInsertVertexBatchSql(goldenVertexs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		sql, err := ConvertToInsertVertexBatchSql(goldenVertexs())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sql != first {
+			t.Fatalf("第%d次生成的语句与首次不一致:\n%s\n%s", i, first, sql)
+		}
+	}
+}
+
+// 比较生成结果与 testdata/<name>.golden, 指定 -update 时覆盖快照
+func assertGolden(t *testing.T, name string, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取快照失败, 请使用 -update 生成: %v", err)
+	}
+	if string(want) != got {
+		t.Errorf("%s 与快照不一致\n期望: %s实际: %s", name, want, got)
+	}
+}
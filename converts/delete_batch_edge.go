@@ -4,8 +4,7 @@ import (
 	"fmt"
 	"nebula-orm-go/model"
 	"nebula-orm-go/utils"
-	"strings" // 字符串操作包
-	"sync"
+	"strings"       // 字符串操作包
 	"text/template" // 模板处理包，用于生成文本输出
 )
 
@@ -32,6 +31,7 @@ var deleteEdgeBatchTemplate = template.Must(template.New("delete_batch_edge").
 	Parse("delete edge {{.Name}} {{range $i, $edge := .Edges}}{{if $i}}, {{end}}{{$edge.Src}} -> {{$edge.Dst}}{{end}}"))
 
 // ConvertToDeleteEdgeBatchSql 是一个通用函数，将输入的结构转换为批量删除边的SQL语句
+// 所有边需要属于同一个边类型, 否则返回错误
 //
 // 参数:
 // edges ([]model.IEdge): 边实体切片的接口，需实现IEdge接口。
@@ -47,29 +47,22 @@ func ConvertToDeleteEdgeBatchSql(edges []model.IEdge) (string, error) {
 		return "", fmt.Errorf("参数为空")
 	}
 
-	// 获取边名称
+	// 获取边名称, 并校验所有边属于同一个边类型, 避免删除其它类型的边
 	edgeName := edges[0].EdgeName()
+	for i, edge := range edges {
+		if edge.EdgeName() != edgeName {
+			return "", fmt.Errorf("第%d条边的类型[%s]与第1条边的类型[%s]不一致, 不能在同一条语句中删除", i+1, edge.EdgeName(), edgeName)
+		}
+	}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var batch []deleteBatchEdges
+	// 按输入顺序构建源顶点, 目标顶点, 保证生成的语句稳定可复现
+	batch := make([]deleteBatchEdges, 0, len(edges))
 	for _, edge := range edges {
-		wg.Add(1)
-		go func(edge model.IEdge) {
-			defer wg.Done()
-
-			// 构建源顶点, 目标顶点
-			batchValue := deleteBatchEdges{
-				Src: utils.GetVidWithPolicy(edge.GetVidSrc(), edge.GetVidSrcPolicy()),
-				Dst: utils.GetVidWithPolicy(edge.GetVidDst(), edge.GetVidDstPolicy()),
-			}
-
-			mu.Lock()
-			batch = append(batch, batchValue)
-			mu.Unlock()
-		}(edge)
+		batch = append(batch, deleteBatchEdges{
+			Src: utils.GetVidWithPolicy(edge.GetVidSrc(), edge.GetVidSrcPolicy()),
+			Dst: utils.GetVidWithPolicy(edge.GetVidDst(), edge.GetVidDstPolicy()),
+		})
 	}
-	wg.Wait()
 
 	// 使用模板生成最终的SQL语句
 	buf := new(strings.Builder)
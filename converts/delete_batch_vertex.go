@@ -3,8 +3,7 @@ package converts
 import (
 	"nebula-orm-go/model"
 	"nebula-orm-go/utils"
-	"strings" // 字符串操作包
-	"sync"
+	"strings"       // 字符串操作包
 	"text/template" // 模板处理包，用于生成文本输出
 )
 
@@ -35,20 +34,11 @@ var deleteVertexBatchTemplate = template.Must(template.New("delete_batch_vertex"
 // @Author: 罗德
 // @Date: 2024/6/6
 func ConvertToDeleteVertexBatchSql(vertexs []model.IVertex) (string, error) {
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var batch []string
+	// 按输入顺序构建顶点ID, 保证生成的语句稳定可复现
+	batch := make([]string, 0, len(vertexs))
 	for _, vertex := range vertexs {
-		wg.Add(1)
-		go func(vertex model.IVertex) {
-			defer wg.Done()
-
-			mu.Lock()
-			batch = append(batch, utils.GetVidWithPolicy(vertex.GetVid(), vertex.GetPolicy()))
-			mu.Unlock()
-		}(vertex)
+		batch = append(batch, utils.GetVidWithPolicy(vertex.GetVid(), vertex.GetPolicy()))
 	}
-	wg.Wait()
 
 	// 使用模板生成最终的SQL语句
 	buf := new(strings.Builder)
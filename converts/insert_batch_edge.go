@@ -5,7 +5,6 @@ import (
 	"nebula-orm-go/model"
 	"nebula-orm-go/utils"
 	"strings"
-	"sync"
 	"text/template" // 模板处理包，用于生成文本输出
 )
 
@@ -37,6 +36,7 @@ var insertEdgeBatchTemplate = template.Must(template.New("insert_batch_edge").
 	Parse("insert edge {{.Name}}({{.Keys}}) values {{range $i, $edge := .Edges}}{{if $i}}, {{end}}{{$edge.Src}} -> {{$edge.Dst}}:({{$edge.Values}}){{end}}"))
 
 // ConvertToInsertEdgeBatchSql 是一个通用函数，将输入的结构转换为创建边的SQL语句
+// 所有边需要属于同一个边类型且属性列一致, 否则返回错误, 混合不同的边模型请使用 ConvertToInsertEdgeGroupSql
 //
 // 参数:
 // edges ([]model.IEdge): 边实体切片的接口，需实现IEdge接口。
@@ -52,36 +52,26 @@ func ConvertToInsertEdgeBatchSql(edges []model.IEdge) (string, error) {
 		return "", fmt.Errorf("参数为空")
 	}
 
-	// 获取边名称, 边属性名称
-	tagName := edges[0].EdgeName()
-	fields, _ := utils.GetNebulaTag(edges[0])
+	// 获取边名称, 边属性名称, 并校验所有边的类型与属性列一致, 避免写入错误的列
+	tagName, fields, err := checkEdgeBatch(edges)
+	if err != nil {
+		return "", err
+	}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var batch []batchEdges
+	// 按输入顺序构建源顶点, 目标顶点, 边属性值, 保证生成的语句稳定可复现
+	batch := make([]batchEdges, 0, len(edges))
 	for _, edge := range edges {
-		wg.Add(1)
-		go func(edge model.IEdge) {
-			defer wg.Done()
-
-			// 构建源顶点, 目标顶点, 边属性值
-			_, values := utils.GetNebulaTag(edge)
-			batchValue := batchEdges{
-				Src:    utils.GetVidWithPolicy(edge.GetVidSrc(), edge.GetVidSrcPolicy()),
-				Dst:    utils.GetVidWithPolicy(edge.GetVidDst(), edge.GetVidDstPolicy()),
-				Values: strings.Join(values, ","),
-			}
-
-			mu.Lock()
-			batch = append(batch, batchValue)
-			mu.Unlock()
-		}(edge)
+		_, values := utils.GetNebulaTag(edge)
+		batch = append(batch, batchEdges{
+			Src:    utils.GetVidWithPolicy(edge.GetVidSrc(), edge.GetVidSrcPolicy()),
+			Dst:    utils.GetVidWithPolicy(edge.GetVidDst(), edge.GetVidDstPolicy()),
+			Values: strings.Join(values, ","),
+		})
 	}
-	wg.Wait()
 
 	// 使用模板生成最终的SQL语句
 	buf := new(strings.Builder)
-	err := insertEdgeBatchTemplate.Execute(buf, &insertEdgeBatchStruct{
+	err = insertEdgeBatchTemplate.Execute(buf, &insertEdgeBatchStruct{
 		Name:  tagName,
 		Keys:  strings.Join(fields, ","),
 		Edges: batch,
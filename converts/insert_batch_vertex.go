@@ -5,7 +5,6 @@ import (
 	"nebula-orm-go/model"
 	"nebula-orm-go/utils"
 	"strings"
-	"sync"
 	"text/template"
 )
 
@@ -37,6 +36,7 @@ var insertVertexBatchTemplate = template.Must(template.New("insert_batch_vertex"
 
 // ConvertToInsertVertexBatchSql 将给定的顶点切片实体模型转换为Nebula图数据库的insert vertex sql语句。
 // 存在则默认覆盖结构体所有属性, 参数 vertexs 没有被明确赋值的属性也会被覆盖为零值
+// 所有点需要属于同一个标签且属性列一致, 否则返回错误, 混合不同的点模型请使用 ConvertToInsertVertexGroupSql
 //
 // 参数:
 // vertexs ([]model.IVertex): 点实体切片的接口，需实现IVertex接口。
@@ -52,35 +52,25 @@ func ConvertToInsertVertexBatchSql(vertexs []model.IVertex) (string, error) {
 		return "", fmt.Errorf("参数为空")
 	}
 
-	// 获取点名称, 点属性名称
-	tagName := vertexs[0].TagName()
-	fields, _ := utils.GetNebulaTag(vertexs[0])
+	// 获取点名称, 点属性名称, 并校验所有点的标签与属性列一致, 避免写入错误的列
+	tagName, fields, err := checkVertexBatch(vertexs)
+	if err != nil {
+		return "", err
+	}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var batch []batchVertexs
+	// 按输入顺序构建顶点id, 点属性值, 保证生成的语句稳定可复现
+	batch := make([]batchVertexs, 0, len(vertexs))
 	for _, vertex := range vertexs {
-		wg.Add(1)
-		go func(vertex model.IVertex) {
-			defer wg.Done()
-
-			// 构建顶点id, 点属性值
-			_, values := utils.GetNebulaTag(vertex)
-			batchValue := batchVertexs{
-				Vid:    utils.GetVidWithPolicy(vertex.GetVid(), vertex.GetPolicy()),
-				Values: strings.Join(values, ","),
-			}
-
-			mu.Lock()
-			batch = append(batch, batchValue)
-			mu.Unlock()
-		}(vertex)
+		_, values := utils.GetNebulaTag(vertex)
+		batch = append(batch, batchVertexs{
+			Vid:    utils.GetVidWithPolicy(vertex.GetVid(), vertex.GetPolicy()),
+			Values: strings.Join(values, ","),
+		})
 	}
-	wg.Wait()
 
 	// 使用模板生成最终的SQL语句
 	buf := new(strings.Builder)
-	err := insertVertexBatchTemplate.Execute(buf, &insertVertexBatchStruct{
+	err = insertVertexBatchTemplate.Execute(buf, &insertVertexBatchStruct{
 		Name:    tagName,
 		Keys:    strings.Join(fields, ","),
 		Vertexs: batch,
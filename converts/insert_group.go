@@ -0,0 +1,147 @@
+package converts
+
+import (
+	"fmt"
+	"nebula-orm-go/model"
+	"nebula-orm-go/utils"
+	"strings"
+)
+
+// 校验一批点属于同一个标签且属性列一致, 返回标签名称与属性列
+//
+// @Author: 罗德
+// @Date: 2024/6/27
+func checkVertexBatch(vertexs []model.IVertex) (string, []string, error) {
+	tagName := vertexs[0].TagName()
+	fields, _ := utils.GetNebulaTag(vertexs[0])
+	key := strings.Join(fields, ",")
+	for i, vertex := range vertexs[1:] {
+		if vertex.TagName() != tagName {
+			return "", nil, fmt.Errorf("第%d个点的标签[%s]与第1个点的标签[%s]不一致, 请使用 ConvertToInsertVertexGroupSql 按标签分组插入",
+				i+2, vertex.TagName(), tagName)
+		}
+		if other, _ := utils.GetNebulaTag(vertex); strings.Join(other, ",") != key {
+			return "", nil, fmt.Errorf("第%d个点的属性列(%s)与第1个点的属性列(%s)不一致, 请使用 ConvertToInsertVertexGroupSql 按属性列分组插入",
+				i+2, strings.Join(other, ","), key)
+		}
+	}
+	return tagName, fields, nil
+}
+
+// 校验一批边属于同一个边类型且属性列一致, 返回边名称与属性列
+//
+// @Author: 罗德
+// @Date: 2024/6/27
+func checkEdgeBatch(edges []model.IEdge) (string, []string, error) {
+	edgeName := edges[0].EdgeName()
+	fields, _ := utils.GetNebulaTag(edges[0])
+	key := strings.Join(fields, ",")
+	for i, edge := range edges[1:] {
+		if edge.EdgeName() != edgeName {
+			return "", nil, fmt.Errorf("第%d条边的类型[%s]与第1条边的类型[%s]不一致, 请使用 ConvertToInsertEdgeGroupSql 按边类型分组插入",
+				i+2, edge.EdgeName(), edgeName)
+		}
+		if other, _ := utils.GetNebulaTag(edge); strings.Join(other, ",") != key {
+			return "", nil, fmt.Errorf("第%d条边的属性列(%s)与第1条边的属性列(%s)不一致, 请使用 ConvertToInsertEdgeGroupSql 按属性列分组插入",
+				i+2, strings.Join(other, ","), key)
+		}
+	}
+	return edgeName, fields, nil
+}
+
+// GroupVertexs 按标签名称与属性列将点分组, 分组按首次出现的顺序排列, 组内保持输入顺序
+//
+// @Author: 罗德
+// @Date: 2024/6/27
+func GroupVertexs(vertexs []model.IVertex) [][]model.IVertex {
+	var groups [][]model.IVertex
+	index := make(map[string]int)
+	for _, vertex := range vertexs {
+		fields, _ := utils.GetNebulaTag(vertex)
+		key := vertex.TagName() + "(" + strings.Join(fields, ",") + ")"
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], vertex)
+	}
+	return groups
+}
+
+// GroupEdges 按边名称与属性列将边分组, 分组按首次出现的顺序排列, 组内保持输入顺序
+//
+// @Author: 罗德
+// @Date: 2024/6/27
+func GroupEdges(edges []model.IEdge) [][]model.IEdge {
+	var groups [][]model.IEdge
+	index := make(map[string]int)
+	for _, edge := range edges {
+		fields, _ := utils.GetNebulaTag(edge)
+		key := edge.EdgeName() + "(" + strings.Join(fields, ",") + ")"
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], edge)
+	}
+	return groups
+}
+
+// ConvertToInsertVertexGroupSql 将不同标签、不同属性列的点按 GroupVertexs 分组, 每组生成一条insert vertex sql语句。
+// 存在则默认覆盖结构体所有属性, 参数 vertexs 没有被明确赋值的属性也会被覆盖为零值
+//
+// 参数:
+// vertexs ([]model.IVertex): 点实体切片的接口，需实现IVertex接口, 可以混合不同的点模型。
+//
+// 返回:
+// []string: 按分组顺序生成的insert vertex sql语句。
+// error: 如果转换过程中发生错误，则返回具体的错误信息。
+//
+// @Author: 罗德
+// @Date: 2024/6/27
+func ConvertToInsertVertexGroupSql(vertexs []model.IVertex) ([]string, error) {
+	if len(vertexs) == 0 {
+		return nil, fmt.Errorf("参数为空")
+	}
+	groups := GroupVertexs(vertexs)
+	sqls := make([]string, 0, len(groups))
+	for _, group := range groups {
+		sql, err := ConvertToInsertVertexBatchSql(group)
+		if err != nil {
+			return nil, err
+		}
+		sqls = append(sqls, sql)
+	}
+	return sqls, nil
+}
+
+// ConvertToInsertEdgeGroupSql 将不同边类型、不同属性列的边按 GroupEdges 分组, 每组生成一条insert edge sql语句。
+//
+// 参数:
+// edges ([]model.IEdge): 边实体切片的接口，需实现IEdge接口, 可以混合不同的边模型。
+//
+// 返回:
+// []string: 按分组顺序生成的insert edge sql语句。
+// error: 如果转换过程中发生错误，则返回具体的错误信息。
+//
+// @Author: 罗德
+// @Date: 2024/6/27
+func ConvertToInsertEdgeGroupSql(edges []model.IEdge) ([]string, error) {
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("参数为空")
+	}
+	groups := GroupEdges(edges)
+	sqls := make([]string, 0, len(groups))
+	for _, group := range groups {
+		sql, err := ConvertToInsertEdgeBatchSql(group)
+		if err != nil {
+			return nil, err
+		}
+		sqls = append(sqls, sql)
+	}
+	return sqls, nil
+}
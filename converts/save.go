@@ -0,0 +1,57 @@
+package converts
+
+import (
+	"fmt"
+	"nebula-orm-go/model"
+	"reflect"
+	"sort"
+)
+
+// ConvertToSaveVertexSql 生成覆盖全部属性的 upsert vertex 语句, 所有带 nebula 标签的字段(包括零值)都会写入,
+// yield 全部属性以便回填到结构体。
+//
+// 参数:
+// vertex (model.IVertex): 点实体的接口，需实现IVertex接口
+//
+// 返回:
+// string: 成功生成的upsert vertex sql语句。
+// error: 如果转换过程中发生错误，则返回具体的错误信息。
+//
+// @Author: 罗德
+// @Date: 2024/7/1
+func ConvertToSaveVertexSql(vertex model.IVertex) (string, error) {
+	props, err := allProps(vertex)
+	if err != nil {
+		return "", err
+	}
+	return ConvertToUpsertVertexValuesSql(vertex, nil, UpdateOption{Select: props})
+}
+
+// ConvertToSaveEdgeSql 生成覆盖全部属性的 upsert edge 语句, 参数同 ConvertToSaveVertexSql。
+//
+// @Author: 罗德
+// @Date: 2024/7/1
+func ConvertToSaveEdgeSql(edge model.IEdge) (string, error) {
+	props, err := allProps(edge)
+	if err != nil {
+		return "", err
+	}
+	return ConvertToUpsertEdgeValuesSql(edge, nil, UpdateOption{Select: props})
+}
+
+// 模型的全部属性名
+func allProps(m interface{}) ([]string, error) {
+	typ := reflect.Indirect(reflect.ValueOf(m)).Type()
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("模型必须是结构体, 实际为 %T", m)
+	}
+	props := make([]string, 0, typ.NumField())
+	for prop := range propNames(typ) {
+		props = append(props, prop)
+	}
+	if len(props) == 0 {
+		return nil, fmt.Errorf("%s 没有带 nebula 标签的属性", typ)
+	}
+	sort.Strings(props)
+	return props, nil
+}
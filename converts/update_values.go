@@ -0,0 +1,371 @@
+package converts
+
+import (
+	"fmt"
+	"nebula-orm-go/clause"
+	"nebula-orm-go/constants"
+	"nebula-orm-go/model"
+	"nebula-orm-go/utils"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UpdateOption 按结构体或 map 生成 set 子句时的选项
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+type UpdateOption struct {
+	Select []string          // 只更新这些属性(属性名或字段名), 零值也会更新; 为空时更新结构体的非零值字段或 map 的全部键
+	Omit   []string          // 不更新这些属性(属性名或字段名)
+	When   clause.ICondition // 更新条件, 为空时不添加 when 子句
+}
+
+// ConvertToUpdateVertexValuesSql 按结构体或 map 生成多属性的 update vertex 语句, 属性值按类型编码为 nGQL 字面量。
+//
+// 参数:
+// vertex (model.IVertex): 点实体的接口，需实现IVertex接口
+// values (interface{}): 结构体(指针)或 map[string]interface{}, 为 nil 时使用 vertex 本身
+// opt (UpdateOption): 属性过滤与更新条件
+//
+// 返回:
+// string: 成功生成的update vertex sql语句。
+// error: 如果转换过程中发生错误，则返回具体的错误信息。
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func ConvertToUpdateVertexValuesSql(vertex model.IVertex, values interface{}, opt UpdateOption) (string, error) {
+	set, where, err := setAndWhen(vertex, values, opt, false)
+	if err != nil {
+		return "", err
+	}
+	return ConvertToUpdateVertexSql(vertex, set, where)
+}
+
+// ConvertToUpsertVertexValuesSql 按结构体或 map 生成多属性的 upsert vertex 语句, 参数同 ConvertToUpdateVertexValuesSql。
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func ConvertToUpsertVertexValuesSql(vertex model.IVertex, values interface{}, opt UpdateOption) (string, error) {
+	set, where, err := setAndWhen(vertex, values, opt, true)
+	if err != nil {
+		return "", err
+	}
+	return ConvertToUpsertVertexSql(vertex, set, where)
+}
+
+// ConvertToUpdateEdgeValuesSql 按结构体或 map 生成多属性的 update edge 语句, 参数同 ConvertToUpdateVertexValuesSql。
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func ConvertToUpdateEdgeValuesSql(edge model.IEdge, values interface{}, opt UpdateOption) (string, error) {
+	set, where, err := setAndWhen(edge, values, opt, false)
+	if err != nil {
+		return "", err
+	}
+	return ConvertToUpdateEdgeSql(edge, set, where)
+}
+
+// ConvertToUpsertEdgeValuesSql 按结构体或 map 生成多属性的 upsert edge 语句, 参数同 ConvertToUpdateVertexValuesSql。
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func ConvertToUpsertEdgeValuesSql(edge model.IEdge, values interface{}, opt UpdateOption) (string, error) {
+	set, where, err := setAndWhen(edge, values, opt, true)
+	if err != nil {
+		return "", err
+	}
+	return ConvertToUpsertEdgeSql(edge, set, where)
+}
+
+// 生成 set 与 when 子句。自动时间与默认值字段按 autoEntries 的规则处理;
+// 模型带有版本号字段时, 版本号不从 values 更新, 而是追加 version = version + 1,
+// 并以 version == 模型当前的版本号 作为更新条件(与 opt.When 同时成立)
+//
+// @Author: 罗德
+// @Date: 2024/7/3
+func setAndWhen(m interface{}, values interface{}, opt UpdateOption, upsert bool) (string, string, error) {
+	versionProp, versionField, versioned, err := utils.GetVersionField(m)
+	if err != nil {
+		return "", "", err
+	}
+	if versioned {
+		opt.Omit = append(opt.Omit[:len(opt.Omit):len(opt.Omit)], versionProp)
+	}
+	entries, err := setEntries(m, values, opt)
+	if err != nil {
+		return "", "", err
+	}
+	set := joinSets(autoEntries(m, entries, opt, upsert))
+	when := opt.When
+	if versioned {
+		// upsert 插入新点边时版本号属性为 null, 按 0 计算
+		if upsert {
+			set += fmt.Sprintf(", %s = coalesce(%s, 0) + 1", versionProp, versionProp)
+		} else {
+			set += fmt.Sprintf(", %s = %s + 1", versionProp, versionProp)
+		}
+		// 版本号为 0 的模型也可以更新版本号属性为 null 的存量点边
+		version := clause.Eq(versionProp, versionField.Int())
+		if versionField.Int() == 0 {
+			version = clause.Or(clause.IsNull(versionProp), version)
+		}
+		if when == nil {
+			when = version
+		} else {
+			when = clause.And(when, version)
+		}
+	}
+	if when == nil {
+		return set, "", nil
+	}
+	where, err := when.Build("")
+	if err != nil {
+		return "", "", fmt.Errorf("生成when条件失败: %w", err)
+	}
+	return set, where, nil
+}
+
+// 处理带 autoCreateTime、autoUpdateTime、default 选项的字段, 值为零值或未指定时:
+//   - autoUpdateTime 写入当前时间: updated_at = timestamp()
+//   - upsert 时 autoCreateTime、default 只在属性为 null(新插入)时写入: created_at = coalesce(created_at, timestamp())
+//   - update 时 autoCreateTime、default 不处理
+//
+// 显式指定的非零值保持不变, Omit 的属性不处理
+//
+// @Author: 罗德
+// @Date: 2024/7/4
+func autoEntries(m interface{}, entries []setEntry, opt UpdateOption, upsert bool) []setEntry {
+	typ := reflect.Indirect(reflect.ValueOf(m)).Type()
+	omits := make(map[string]bool, len(opt.Omit))
+	for _, name := range opt.Omit {
+		omits[name] = true
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := utils.ParseNebulaTag(field.Tag.Get(constants.StructTagName))
+		prop := utils.GetPropName(field)
+		if prop == "" || omits[prop] || omits[field.Name] {
+			continue
+		}
+		var value string
+		switch {
+		case tag.Has(utils.TagOptionAutoUpdateTime):
+			value = tag.TimeFunc(utils.TagOptionAutoUpdateTime, field.Type)
+		case !upsert:
+			continue
+		case tag.Has(utils.TagOptionAutoCreateTime):
+			value = fmt.Sprintf("coalesce(%s, %s)", prop, tag.TimeFunc(utils.TagOptionAutoCreateTime, field.Type))
+		case tag.Options[utils.TagOptionDefault] != "":
+			value = fmt.Sprintf("coalesce(%s, %s)", prop, tag.Options[utils.TagOptionDefault])
+		default:
+			continue
+		}
+		entry := setEntry{prop: prop, sql: prop + " = " + value}
+		index := -1
+		for j := range entries {
+			if entries[j].prop == prop {
+				index = j
+			}
+		}
+		switch {
+		case index < 0:
+			entries = append(entries, entry)
+		case entries[index].zero:
+			entries[index] = entry
+		}
+	}
+	return entries
+}
+
+// SetClause 按结构体或 map 生成 set 子句(例: chain_key = 'a', parent_key = 'b'), 属性必须是模型中带 nebula 标签的字段。
+// 结构体按字段顺序输出, map 按键排序输出, 以保证生成的语句稳定
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func SetClause(m interface{}, values interface{}, opt UpdateOption) (string, error) {
+	entries, err := setEntries(m, values, opt)
+	if err != nil {
+		return "", err
+	}
+	return joinSets(entries), nil
+}
+
+// set 子句中的一个赋值
+//
+// @Author: 罗德
+// @Date: 2024/7/4
+type setEntry struct {
+	prop string // 属性名
+	sql  string // 赋值语句, 例如 chain_key = 'a'
+	zero bool   // 值是否为零值
+}
+
+// 按结构体或 map 生成 set 子句的各个赋值, 规则同 SetClause
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func setEntries(m interface{}, values interface{}, opt UpdateOption) ([]setEntry, error) {
+	modelType := reflect.Indirect(reflect.ValueOf(m)).Type()
+	if modelType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("模型必须是结构体, 实际为 %T", m)
+	}
+	props := propNames(modelType)
+	resolve := func(name string) (string, error) {
+		if _, ok := props[name]; ok {
+			return name, nil
+		}
+		for prop, field := range props {
+			if field == name {
+				return prop, nil
+			}
+		}
+		return "", fmt.Errorf("属性[%s]在 %s 中不存在", name, modelType)
+	}
+	selects, err := resolveAll(opt.Select, resolve)
+	if err != nil {
+		return nil, err
+	}
+	omits, err := resolveAll(opt.Omit, resolve)
+	if err != nil {
+		return nil, err
+	}
+	// explicit 表示值是否由调用方显式给出(map 的键), 显式给出的零值同样会更新
+	include := func(prop string, zero bool, explicit bool) bool {
+		if omits[prop] {
+			return false
+		}
+		if len(selects) > 0 {
+			return selects[prop]
+		}
+		return explicit || !zero
+	}
+
+	if values == nil {
+		values = m
+	}
+	var sets []setEntry
+	val := reflect.Indirect(reflect.ValueOf(values))
+	switch val.Kind() {
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map 的键必须是字符串, 实际为 %s", val.Type().Key())
+		}
+		// 键可以是属性名或字段名, 解析为属性名后排序
+		entries := make(map[string]reflect.Value, val.Len())
+		keys := make([]string, 0, val.Len())
+		for _, key := range val.MapKeys() {
+			prop, err := resolve(key.String())
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := entries[prop]; ok {
+				return nil, fmt.Errorf("属性[%s]重复指定", prop)
+			}
+			entries[prop] = val.MapIndex(key)
+			keys = append(keys, prop)
+		}
+		sort.Strings(keys)
+		for _, prop := range keys {
+			if !include(prop, false, true) {
+				continue
+			}
+			field, _ := modelType.FieldByName(props[prop])
+			set, err := setItem(prop, field, entries[prop].Interface())
+			if err != nil {
+				return nil, err
+			}
+			sets = append(sets, setEntry{prop: prop, sql: set, zero: isZero(entries[prop])})
+		}
+	case reflect.Struct:
+		typ := val.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			tag := utils.GetPropName(typ.Field(i))
+			if tag == "" {
+				continue
+			}
+			if _, ok := props[tag]; !ok {
+				return nil, fmt.Errorf("属性[%s]在 %s 中不存在", tag, modelType)
+			}
+			if !include(tag, val.Field(i).IsZero(), false) {
+				continue
+			}
+			set, err := setItem(tag, typ.Field(i), val.Field(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			sets = append(sets, setEntry{prop: tag, sql: set, zero: val.Field(i).IsZero()})
+		}
+	default:
+		return nil, fmt.Errorf("更新的值必须是结构体或 map, 实际为 %T", values)
+	}
+
+	if len(sets) == 0 {
+		return nil, fmt.Errorf("没有需要更新的属性, 零值字段需要通过 Select 显式指定")
+	}
+	return sets, nil
+}
+
+func joinSets(entries []setEntry) string {
+	sets := make([]string, len(entries))
+	for i, entry := range entries {
+		sets[i] = entry.sql
+	}
+	return strings.Join(sets, ", ")
+}
+
+// map 的值为 interface{}, 取实际值判断是否为零值
+func isZero(value reflect.Value) bool {
+	if value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return true
+		}
+		value = value.Elem()
+	}
+	return value.IsZero()
+}
+
+// 生成一个属性的赋值, 值为更新表达式时直接输出表达式, time.Time 按字段标签声明的属性类型编码
+func setItem(prop string, field reflect.StructField, value interface{}) (string, error) {
+	if expr, ok := value.(clause.IExpression); ok {
+		sql, err := expr.BuildExpr(prop)
+		if err != nil {
+			return "", fmt.Errorf("属性[%s]: %w", prop, err)
+		}
+		return prop + " = " + sql, nil
+	}
+	if t, ok := value.(time.Time); ok {
+		return prop + " = " + utils.ParseNebulaTag(field.Tag.Get(constants.StructTagName)).TimeValue(t), nil
+	}
+	literal, err := clause.Value(value)
+	if err != nil {
+		return "", fmt.Errorf("属性[%s]: %w", prop, err)
+	}
+	return prop + " = " + literal, nil
+}
+
+// 模型的属性名 -> 字段名
+func propNames(typ reflect.Type) map[string]string {
+	props := make(map[string]string, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		tag := utils.GetPropName(typ.Field(i))
+		if tag != "" {
+			props[tag] = typ.Field(i).Name
+		}
+	}
+	return props
+}
+
+// 将属性名或字段名解析为属性名集合
+func resolveAll(names []string, resolve func(string) (string, error)) (map[string]bool, error) {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		prop, err := resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		set[prop] = true
+	}
+	return set, nil
+}
@@ -15,7 +15,7 @@ import (
 type upsertEdgeStruct struct {
 	Name     string // 边的名称
 	Src, Dst string // 源顶点和目标顶点的标识
-	Set      string // 用于set更新字段的属性,一次只能修改一个字段
+	Set      string // 用于set更新字段的属性, 多个属性以逗号分隔
 	Where    string // 用于补充过滤条件
 	Yield    string // return返回字段
 }
@@ -32,7 +32,7 @@ var upsertEdgeTemplate = template.Must(template.New("upsert_edge").
 //
 // 参数:
 // edge (model.IEdge): 边实体的接口，需实现IEdge接口
-// set (string): 用于set更新字段的属性,一次只能修改一个字段(例: test = 'O(∩_∩)O')
+// set (string): 用于set更新字段的属性, 多个属性以逗号分隔(例: test = 'O(∩_∩)O')
 // where (string): 用于补充过滤条件(例: test == '无')
 //
 // 返回:
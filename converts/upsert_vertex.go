@@ -15,7 +15,7 @@ import (
 type upsertVertexStruct struct {
 	Name  string // 顶点标签名称
 	Vid   string // 顶点ID，可能包含ID策略
-	Set   string // 用于set更新字段的属性,一次只能修改一个字段
+	Set   string // 用于set更新字段的属性, 多个属性以逗号分隔
 	Where string // 用于补充过滤条件
 	Yield string // return返回字段
 }
@@ -32,7 +32,7 @@ var upsertVertexTemplate = template.Must(template.New("upsert_vertex").
 //
 // 参数:
 // vertex (model.IVertex): 点实体的接口，需实现IVertex接口
-// set (string): 用于set更新字段的属性,一次只能修改一个字段(例: parent_key = 'O(∩_∩)O')
+// set (string): 用于set更新字段的属性, 多个属性以逗号分隔(例: parent_key = 'O(∩_∩)O')
 // where (string): 用于补充过滤条件(例: parent_key == '无')
 //
 // 返回:
@@ -0,0 +1,85 @@
+package dialectors
+
+import (
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebula_type "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+	"nebula-orm-go/utils"
+)
+
+// NewResultSet 使用列名与 Go 语言的行数据构造一个执行成功的结果集, 行中的值通过 utils.InterfaceToNValue 转换,
+// 主要用于模拟拨号器、内存图引擎等不经过graphd的场景。
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func NewResultSet(columns []string, rows ...[]interface{}) (*ResultSet, error) {
+	dataSet := &nebula_type.DataSet{
+		ColumnNames: make([][]byte, len(columns)),
+		Rows:        make([]*nebula_type.Row, len(rows)),
+	}
+	for i, column := range columns {
+		dataSet.ColumnNames[i] = []byte(column)
+	}
+	for i, row := range rows {
+		values := make([]*nebula_type.Value, len(row))
+		for j, item := range row {
+			value, err := utils.InterfaceToNValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values[j] = value
+		}
+		dataSet.Rows[i] = &nebula_type.Row{Values: values}
+	}
+
+	return NewResultSetFromResponse(&graph.ExecutionResponse{
+		ErrorCode: nebula_type.ErrorCode_SUCCEEDED,
+		Data:      dataSet,
+	})
+}
+
+// NewResultSetFromResponse 使用 graphd 的执行响应构造结果集
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func NewResultSetFromResponse(resp *graph.ExecutionResponse) (*ResultSet, error) {
+	nSet, err := nebula.GenResultSet(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &ResultSet{nSet}, nil
+}
+
+// ToResponse 将结果集还原为 graphd 的执行响应, 用于序列化保存(例如录制拨号器)
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (resultSet *ResultSet) ToResponse() *graph.ExecutionResponse {
+	resp := &graph.ExecutionResponse{ErrorCode: nebula_type.ErrorCode_SUCCEEDED}
+	if resultSet.ResultSet == nil {
+		return resp
+	}
+
+	resp.ErrorCode = nebula_type.ErrorCode(resultSet.GetErrorCode())
+	resp.LatencyInUs = resultSet.GetLatency()
+	if resultSet.IsSetData() {
+		dataSet := &nebula_type.DataSet{Rows: resultSet.GetRows()}
+		for _, column := range resultSet.GetColNames() {
+			dataSet.ColumnNames = append(dataSet.ColumnNames, []byte(column))
+		}
+		resp.Data = dataSet
+	}
+	if space := resultSet.GetSpaceName(); space != "" {
+		resp.SpaceName = []byte(space)
+	}
+	if msg := resultSet.GetErrorMsg(); msg != "" {
+		resp.ErrorMsg = []byte(msg)
+	}
+	if resultSet.IsSetPlanDesc() {
+		resp.PlanDesc = resultSet.GetPlanDesc()
+	}
+	if resultSet.IsSetComment() {
+		resp.Comment = []byte(resultSet.GetComment())
+	}
+	return resp
+}
@@ -0,0 +1,38 @@
+package dialectors
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// ExecuteError graphd 返回的执行错误, 保留了nebula错误码, 便于按错误码做统计与判断
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+type ExecuteError struct {
+	Code nebula.ErrorCode // nebula错误码
+	Msg  string           // graphd 返回的错误信息
+}
+
+// Error 实现error接口, 格式与历史版本保持一致: code: xx, msg: xx
+func (e *ExecuteError) Error() string {
+	return fmt.Sprintf("code: %d, msg: %s", e.Code, e.Msg)
+}
+
+// GetErrorCode 获取错误对应的nebula错误码。
+// 成功(err为nil)返回 ErrorCode_SUCCEEDED; graphd 返回的执行错误返回其错误码;
+// 其他客户端错误(获取会话失败、网络异常等)统一返回 ErrorCode_E_RPC_FAILURE。
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func GetErrorCode(err error) nebula.ErrorCode {
+	if err == nil {
+		return nebula.ErrorCode_SUCCEEDED
+	}
+	var executeError *ExecuteError
+	if errors.As(err, &executeError) {
+		return executeError.Code
+	}
+	return nebula.ErrorCode_E_RPC_FAILURE
+}
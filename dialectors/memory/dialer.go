@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"github.com/pkg/errors"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebula_type "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+	"nebula-orm-go/dialectors"
+	"sync"
+	"time"
+)
+
+// 确保满足IDialer定义的所有要求
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+var _ dialectors.IDialer = new(Dialer)
+
+// Dialer 内存图引擎, 在进程内保存图空间、标签、边类型、点与边, 并执行本库生成的nGQL子集,
+// 返回与graphd结构一致的结果集, 用于在没有Nebula集群的情况下测试遍历代码。
+//
+// 支持的语句:
+//   - create/drop space、tag、edge、tag index、edge index, alter tag/edge, use, show, describe
+//   - insert vertex/edge、delete vertex/edge、update/upsert vertex/edge
+//   - fetch prop on、go、lookup on、match、get subgraph、yield, 以及管道中的 limit、order by、yield
+//
+// 多条语句以分号分隔时依次执行, 返回最后一条语句的结果。所有语句串行执行, 可安全地并发调用。
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type Dialer struct {
+	mu         sync.Mutex
+	spaces     map[string]*space
+	spaceOrder []string
+	current    string // 当前使用的图空间
+	autoSchema bool   // 写入不存在的标签、边类型、属性时自动创建
+	closed     bool
+}
+
+// Option 内存图引擎的配置项
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type Option func(d *Dialer) error
+
+// WithSpace 创建并使用指定的图空间, vidType 为 FIXED_STRING(n) 或 INT64, 为空时使用 FIXED_STRING(64)
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func WithSpace(name string, vidType string) Option {
+	return func(d *Dialer) error {
+		if vidType == "" {
+			vidType = "FIXED_STRING(64)"
+		}
+		_, err := d.execute("create space if not exists `" + name + "`(vid_type=" + vidType + "); use `" + name + "`")
+		return err
+	}
+}
+
+// WithAutoSchema 写入不存在的标签、边类型或属性时自动创建结构定义(属性均可为空, 类型由首次写入的值推断),
+// 适合只关心数据、不想维护建表语句的测试。默认关闭, 与graphd一样严格校验结构定义。
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func WithAutoSchema() Option {
+	return func(d *Dialer) error {
+		d.autoSchema = true
+		return nil
+	}
+}
+
+// WithInitSql 创建后执行初始化nGQL, 例如建空间、建标签、建边的语句, 与 config.DialerConfig.InitSql 对应
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func WithInitSql(ngql string) Option {
+	return func(d *Dialer) error {
+		_, err := d.execute(ngql)
+		return err
+	}
+}
+
+// New 创建内存图引擎, 未使用 WithSpace 时需要先执行 create space 与 use 语句
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func New(opts ...Option) (*Dialer, error) {
+	d := &Dialer{spaces: make(map[string]*space)}
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// MustNew 创建内存图引擎, 出错时panic
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func MustNew(opts ...Option) *Dialer {
+	d, err := New(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Execute 执行nGQL, 执行失败时返回 dialectors.ExecuteError, 错误码与graphd保持一致
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func (d *Dialer) Execute(sql string) (*dialectors.ResultSet, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return &dialectors.ResultSet{}, errors.New("内存图引擎已关闭")
+	}
+	return d.execute(sql)
+}
+
+// Close 关闭内存图引擎, 关闭后执行语句会返回错误
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func (d *Dialer) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+}
+
+// 解析并依次执行语句, 调用方需持有锁
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func (d *Dialer) execute(sql string) (*dialectors.ResultSet, error) {
+	start := time.Now()
+	statements, err := parseStatements(sql)
+	if err == nil && len(statements) == 0 {
+		err = &statementError{code: nebula.ErrorCode_E_STATEMENT_EMPTY, msg: "StatementEmpty: "}
+	}
+
+	var res *result
+	for _, stmt := range statements {
+		if err != nil {
+			break
+		}
+		res, err = stmt.execute(d)
+	}
+	if err != nil {
+		return &dialectors.ResultSet{}, errors.WithStack(toExecuteError(err))
+	}
+
+	resp := &graph.ExecutionResponse{
+		ErrorCode:   nebula_type.ErrorCode_SUCCEEDED,
+		LatencyInUs: time.Since(start).Microseconds(),
+	}
+	if d.current != "" {
+		resp.SpaceName = []byte(d.current)
+	}
+	if res != nil {
+		if resp.Data, err = res.toDataSet(d.spaces[d.current]); err != nil {
+			return &dialectors.ResultSet{}, errors.WithStack(err)
+		}
+	}
+	return dialectors.NewResultSetFromResponse(resp)
+}
+
+// 将语句错误转换为 dialectors.ExecuteError
+func toExecuteError(err error) error {
+	var stmtErr *statementError
+	if errors.As(err, &stmtErr) {
+		return &dialectors.ExecuteError{Code: stmtErr.code, Msg: stmtErr.msg}
+	}
+	return err
+}
+
+// 当前使用的图空间
+func (d *Dialer) space() (*space, error) {
+	s, ok := d.spaces[d.current]
+	if !ok {
+		return nil, semanticError("Space was not chosen.")
+	}
+	return s, nil
+}
+
+// 语句
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type statement interface {
+	execute(d *Dialer) (*result, error)
+}
+
+// 语句的执行结果, 为nil时表示语句没有返回数据(例如 insert)
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type result struct {
+	columns []string
+	rows    [][]interface{}
+}
+
+// 转换为nebula的数据集
+func (r *result) toDataSet(s *space) (*nebula_type.DataSet, error) {
+	dataSet := &nebula_type.DataSet{
+		ColumnNames: make([][]byte, len(r.columns)),
+		Rows:        make([]*nebula_type.Row, len(r.rows)),
+	}
+	for i, column := range r.columns {
+		dataSet.ColumnNames[i] = []byte(column)
+	}
+	for i, row := range r.rows {
+		values := make([]*nebula_type.Value, len(row))
+		for j, item := range row {
+			value, err := toNValue(item, s)
+			if err != nil {
+				return nil, err
+			}
+			values[j] = value
+		}
+		dataSet.Rows[i] = &nebula_type.Row{Values: values}
+	}
+	return dataSet, nil
+}
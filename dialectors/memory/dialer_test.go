@@ -0,0 +1,259 @@
+package memory
+
+import (
+	"errors"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"nebula-orm-go/dialectors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// 测试用图空间: 人物标签与关注边, 关注关系 a -> b -> c, a -> c
+const testInitSql = "create space test(vid_type=FIXED_STRING(32)); use test; " +
+	"create tag person(name string, age int); create edge follow(degree double); " +
+	"create tag index person_name on person(name(16)); " +
+	"insert vertex person(name, age) values 'a':('甲', 30), 'b':('乙', 20), 'c':('丙', 40); " +
+	"insert edge follow(degree) values 'a'->'b':(0.5), 'b'->'c':(0.8), 'a'->'c':(1.0)"
+
+// 测试用点结构体
+type person struct {
+	Name string `nebula:"name"`
+	Age  int    `nebula:"age"`
+}
+
+func newTestDialer(t *testing.T) *Dialer {
+	t.Helper()
+	d, err := New(WithInitSql(testInitSql))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// 执行语句, 出错时终止测试
+func mustExecute(t *testing.T, d *Dialer, ngql string) *dialectors.ResultSet {
+	t.Helper()
+	result, err := d.Execute(ngql)
+	if err != nil {
+		t.Fatalf("执行 %s 失败: %v", ngql, err)
+	}
+	return result
+}
+
+// 写入后通过 fetch 读取, 并解码到结构体
+func TestFetch(t *testing.T) {
+	d := newTestDialer(t)
+	result := mustExecute(t, d, "fetch prop on person 'a', 'b' yield person.name as name, person.age as age")
+	var got []person
+	if err := result.UnmarshalResultSet(&got); err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].Age > got[j].Age })
+	want := []person{{"甲", 30}, {"乙", 20}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("fetch = %v, 期望 %v", got, want)
+	}
+
+	// 点值包含全部属性
+	result = mustExecute(t, d, "fetch prop on person 'c' yield vertex as v")
+	record, err := result.GetRowValuesByIndex(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := record.GetValueByColName("v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := value.AsNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	props, err := node.Properties("person")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, _ := props["name"].AsString(); name != "丙" {
+		t.Fatalf("点属性 = %v", props)
+	}
+
+	// 不存在的点没有返回行
+	result = mustExecute(t, d, "fetch prop on person 'x' yield person.name as name")
+	if result.GetRowSize() != 0 {
+		t.Fatalf("不存在的点返回了 %d 行", result.GetRowSize())
+	}
+}
+
+// go 遍历出边与反向边
+func TestGo(t *testing.T) {
+	d := newTestDialer(t)
+	tests := []struct {
+		ngql string
+		want []string
+	}{
+		{"go from 'a' over follow yield dst(edge) as dst", []string{"b", "c"}},
+		{"go 2 steps from 'a' over follow yield dst(edge) as dst", []string{"c"}},
+		{"go from 'c' over follow reversely yield src(edge) as dst", []string{"a", "b"}},
+		{"go from 'a' over follow where follow.degree > 0.6 yield dst(edge) as dst", []string{"c"}},
+		{"go from 'a' over follow yield $$.person.name as dst | order by $-.dst | limit 1", []string{"丙"}},
+	}
+	for _, tt := range tests {
+		var rows []map[string]interface{}
+		if err := mustExecute(t, d, tt.ngql).UnmarshalResultSet(&rows); err != nil {
+			t.Fatal(err)
+		}
+		got := make([]string, 0, len(rows))
+		for _, row := range rows {
+			got = append(got, row["dst"].(string))
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %v, 期望 %v", tt.ngql, got, tt.want)
+		}
+	}
+}
+
+// match 返回点值列表, 按 ToVertexs 解码
+func TestMatch(t *testing.T) {
+	d := newTestDialer(t)
+	result := mustExecute(t, d, "match (v:person)-[:follow]->(u:person) where id(v) == 'a' return collect(u) as us")
+	vertexs, err := result.ToVertexs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, row := range vertexs {
+		for _, props := range row {
+			names = append(names, props["name"].(string))
+		}
+	}
+	sort.Strings(names)
+	if want := []string{"丙", "乙"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("match = %v, 期望 %v", names, want)
+	}
+
+	var got []person
+	err = mustExecute(t, d, "match (v:person) where v.person.age >= 30 return v.person.name as name, v.person.age as age order by age").
+		UnmarshalResultSet(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []person{{"甲", 30}, {"丙", 40}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("match = %v, 期望 %v", got, want)
+	}
+}
+
+// get subgraph with prop 返回各步的点与边
+func TestSubgraph(t *testing.T) {
+	d := newTestDialer(t)
+	result := mustExecute(t, d, "get subgraph with prop 1 steps from 'a' out follow yield vertices as nodes, edges as relationships")
+	if result.GetRowSize() != 2 {
+		t.Fatalf("subgraph 返回 %d 行, 期望 2", result.GetRowSize())
+	}
+	vertexs, err := result.ToVertexs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, row := range vertexs {
+		for _, props := range row {
+			names = append(names, props["name"].(string))
+		}
+	}
+	sort.Strings(names)
+	if want := []string{"丙", "乙", "甲"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("subgraph 点 = %v, 期望 %v", names, want)
+	}
+	record, err := result.GetRowValuesByIndex(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edges, err := record.GetValueByColName("relationships")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := edges.AsList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("第一步的边数量 = %d, 期望 2", len(list))
+	}
+}
+
+// 更新、删除后再次读取
+func TestMutate(t *testing.T) {
+	d := newTestDialer(t)
+	mustExecute(t, d, "update vertex on person 'a' set age = age + 1")
+	mustExecute(t, d, "upsert vertex on person 'd' set name = '丁', age = 10")
+	mustExecute(t, d, "delete vertex 'b' with edge")
+
+	var got []person
+	if err := mustExecute(t, d, "match (v:person) return v.person.name as name, v.person.age as age order by age").
+		UnmarshalResultSet(&got); err != nil {
+		t.Fatal(err)
+	}
+	if want := []person{{"丁", 10}, {"甲", 31}, {"丙", 40}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("修改后 = %v, 期望 %v", got, want)
+	}
+	result := mustExecute(t, d, "go from 'a' over follow yield dst(edge) as dst")
+	if result.GetRowSize() != 1 {
+		t.Fatalf("删除点后仍有 %d 条出边, 期望 1", result.GetRowSize())
+	}
+}
+
+// 错误返回与 graphd 一致的错误码
+func TestErrors(t *testing.T) {
+	d := newTestDialer(t)
+	tests := []struct {
+		name string
+		ngql string
+		code nebula.ErrorCode
+	}{
+		{"语法错误", "fetch prop person 'a'", nebula.ErrorCode_E_SYNTAX_ERROR},
+		{"括号不匹配", "insert vertex person(name values 'x':('猫')", nebula.ErrorCode_E_SYNTAX_ERROR},
+		{"不支持的语句", "select * from person", nebula.ErrorCode_E_SEMANTIC_ERROR},
+		{"空语句", "", nebula.ErrorCode_E_STATEMENT_EMPTY},
+		{"未知标签", "insert vertex animal(name) values 'x':('猫')", nebula.ErrorCode_E_SEMANTIC_ERROR},
+		{"未知属性", "insert vertex person(color) values 'x':('红')", nebula.ErrorCode_E_SEMANTIC_ERROR},
+		{"查询未知标签", "fetch prop on animal 'a' yield vertex as v", nebula.ErrorCode_E_SEMANTIC_ERROR},
+		{"未知边类型", "go from 'a' over like yield dst(edge)", nebula.ErrorCode_E_SEMANTIC_ERROR},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := d.Execute(tt.ngql)
+			var executeErr *dialectors.ExecuteError
+			if !errors.As(err, &executeErr) {
+				t.Fatalf("期望 ExecuteError, 实际为 %v", err)
+			}
+			if executeErr.Code != tt.code {
+				t.Fatalf("错误码 = %d, 期望 %d: %s", executeErr.Code, tt.code, executeErr.Msg)
+			}
+		})
+	}
+
+	// 未使用图空间时不能执行查询
+	empty := MustNew()
+	if _, err := empty.Execute("fetch prop on person 'a' yield vertex as v"); err == nil {
+		t.Fatal("未选择图空间时应返回错误")
+	}
+	// 关闭后返回错误
+	d.Close()
+	if _, err := d.Execute("yield 1"); err == nil {
+		t.Fatal("关闭后执行语句应返回错误")
+	}
+}
+
+// 自动建立结构时写入未定义的标签不报错
+func TestAutoSchema(t *testing.T) {
+	d := MustNew(WithSpace("auto", ""), WithAutoSchema())
+	mustExecute(t, d, "insert vertex animal(name, legs) values 'x':('猫', 4)")
+	var got []map[string]interface{}
+	if err := mustExecute(t, d, "fetch prop on animal 'x' yield animal.name as name, animal.legs as legs").
+		UnmarshalResultSet(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0]["name"] != "猫" || got[0]["legs"] != int64(4) {
+		t.Fatalf("fetch = %v", got)
+	}
+}
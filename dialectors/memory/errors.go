@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"fmt"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebula_type "github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+// 语句执行错误, 携带与graphd一致的错误码, 由 Dialer.Execute 转换为 dialectors.ExecuteError
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type statementError struct {
+	code nebula.ErrorCode
+	msg  string
+}
+
+// Error 实现error接口
+func (e *statementError) Error() string {
+	return e.msg
+}
+
+// 语法错误
+func syntaxError(format string, args ...interface{}) error {
+	return &statementError{code: nebula.ErrorCode_E_SYNTAX_ERROR, msg: "SyntaxError: " + fmt.Sprintf(format, args...)}
+}
+
+// 语义错误
+func semanticError(format string, args ...interface{}) error {
+	return &statementError{code: nebula.ErrorCode_E_SEMANTIC_ERROR, msg: "SemanticError: " + fmt.Sprintf(format, args...)}
+}
+
+// 执行错误
+func executionError(format string, args ...interface{}) error {
+	return &statementError{code: nebula.ErrorCode_E_EXECUTION_ERROR, msg: fmt.Sprintf(format, args...)}
+}
+
+// 存储层错误, 错误码使用storaged的错误码
+func storageError(code nebula_type.ErrorCode, msg string) error {
+	return &statementError{code: nebula.ErrorCode(code), msg: "Storage Error: " + msg}
+}
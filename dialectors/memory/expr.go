@@ -0,0 +1,930 @@
+package memory
+
+import (
+	nebula_type "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 表达式
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type expr interface {
+	eval(sc *scope) (interface{}, error)
+}
+
+// 表达式求值的作用域
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type scope struct {
+	vars map[string]interface{}    // 变量, 例如 MATCH 绑定的点边、GO 的 $^/$$/edge、update 的属性
+	aggs map[*callExpr]interface{} // 聚合函数在当前分组中的计算结果
+}
+
+// 创建作用域
+func newScope(vars map[string]interface{}) *scope {
+	if vars == nil {
+		vars = make(map[string]interface{})
+	}
+	return &scope{vars: vars}
+}
+
+// 复制作用域并追加变量
+func (sc *scope) with(name string, value interface{}) *scope {
+	vars := make(map[string]interface{}, len(sc.vars)+1)
+	for key, val := range sc.vars {
+		vars[key] = val
+	}
+	vars[name] = value
+	return &scope{vars: vars, aggs: sc.aggs}
+}
+
+// 常量
+type literalExpr struct {
+	value interface{}
+}
+
+func (e *literalExpr) eval(*scope) (interface{}, error) {
+	return e.value, nil
+}
+
+// 列表
+type listExpr struct {
+	items []expr
+}
+
+func (e *listExpr) eval(sc *scope) (interface{}, error) {
+	list := make([]interface{}, len(e.items))
+	for i, item := range e.items {
+		value, err := item.eval(sc)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = value
+	}
+	return list, nil
+}
+
+// 映射
+type mapExpr struct {
+	keys  []string
+	items []expr
+}
+
+func (e *mapExpr) eval(sc *scope) (interface{}, error) {
+	m := make(map[string]interface{}, len(e.keys))
+	for i, key := range e.keys {
+		value, err := e.items[i].eval(sc)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = value
+	}
+	return m, nil
+}
+
+// 变量引用, 包括 $$、$^、$-
+type varExpr struct {
+	name string
+}
+
+func (e *varExpr) eval(sc *scope) (interface{}, error) {
+	value, ok := sc.vars[e.name]
+	if !ok {
+		return nil, semanticError("`%s' is not defined", e.name)
+	}
+	return value, nil
+}
+
+// 属性访问: v.tag.prop、e.prop、$$.tag.prop、$-.col
+type propExpr struct {
+	base expr
+	name string
+}
+
+func (e *propExpr) eval(sc *scope) (interface{}, error) {
+	base, err := e.base.eval(sc)
+	if err != nil {
+		return nil, err
+	}
+	switch val := base.(type) {
+	case nil:
+		return nil, nil
+	case *vertex:
+		props, ok := val.tags[e.name]
+		if !ok {
+			return nil, nil
+		}
+		return props, nil
+	case *edge:
+		switch e.name {
+		case "_src":
+			return val.src, nil
+		case "_dst":
+			return val.dst, nil
+		case "_rank":
+			return val.rank, nil
+		case "_type":
+			return val.name, nil
+		}
+		return val.props[e.name], nil
+	case map[string]interface{}:
+		return val[e.name], nil
+	case nebula_type.Date:
+		return dateField(e.name, int64(val.Year), int64(val.Month), int64(val.Day), 0, 0, 0, 0)
+	case nebula_type.Time:
+		return dateField(e.name, 0, 0, 0, int64(val.Hour), int64(val.Minute), int64(val.Sec), int64(val.Microsec))
+	case nebula_type.DateTime:
+		return dateField(e.name, int64(val.Year), int64(val.Month), int64(val.Day),
+			int64(val.Hour), int64(val.Minute), int64(val.Sec), int64(val.Microsec))
+	}
+	return nil, semanticError("`%s' is not a valid property of %s", e.name, typeName(base))
+}
+
+// 日期时间的成员
+func dateField(name string, year, month, day, hour, minute, sec, microsec int64) (interface{}, error) {
+	switch strings.ToLower(name) {
+	case "year":
+		return year, nil
+	case "month":
+		return month, nil
+	case "day":
+		return day, nil
+	case "hour":
+		return hour, nil
+	case "minute":
+		return minute, nil
+	case "second":
+		return sec, nil
+	case "microsecond":
+		return microsec, nil
+	case "millisecond":
+		return microsec / 1000, nil
+	}
+	return nil, semanticError("`%s' is not a valid member of time value", name)
+}
+
+// 下标访问: list[0]、map['key']
+type indexExpr struct {
+	base  expr
+	index expr
+}
+
+func (e *indexExpr) eval(sc *scope) (interface{}, error) {
+	base, err := e.base.eval(sc)
+	if err != nil {
+		return nil, err
+	}
+	index, err := e.index.eval(sc)
+	if err != nil {
+		return nil, err
+	}
+	switch val := base.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		i, ok := index.(int64)
+		if !ok {
+			return nil, semanticError("list index should be INT, but was %s", typeName(index))
+		}
+		if i < 0 {
+			i += int64(len(val))
+		}
+		if i < 0 || i >= int64(len(val)) {
+			return nil, nil
+		}
+		return val[i], nil
+	case map[string]interface{}:
+		key, ok := index.(string)
+		if !ok {
+			return nil, semanticError("map key should be STRING, but was %s", typeName(index))
+		}
+		return val[key], nil
+	}
+	return nil, semanticError("%s is not subscriptable", typeName(base))
+}
+
+// 一元运算: -x、not x
+type unaryExpr struct {
+	op      string
+	operand expr
+}
+
+func (e *unaryExpr) eval(sc *scope) (interface{}, error) {
+	value, err := e.operand.eval(sc)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	switch e.op {
+	case "-":
+		switch val := value.(type) {
+		case int64:
+			return -val, nil
+		case float64:
+			return -val, nil
+		}
+	case "not":
+		if b, ok := value.(bool); ok {
+			return !b, nil
+		}
+	}
+	return nil, semanticError("`%s' is not a valid operand of `%s'", typeName(value), e.op)
+}
+
+// 二元运算
+type binaryExpr struct {
+	op          string
+	left, right expr
+}
+
+func (e *binaryExpr) eval(sc *scope) (interface{}, error) {
+	left, err := e.left.eval(sc)
+	if err != nil {
+		return nil, err
+	}
+
+	// 逻辑运算使用三值逻辑并支持短路
+	switch e.op {
+	case "and", "or", "xor":
+		return e.logic(sc, left)
+	}
+
+	right, err := e.right.eval(sc)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "==":
+		return valueEqual(left, right), nil
+	case "!=":
+		eq := valueEqual(left, right)
+		if eq == nil {
+			return nil, nil
+		}
+		return !eq.(bool), nil
+	case "<", ">", "<=", ">=":
+		if left == nil || right == nil {
+			return nil, nil
+		}
+		c, ok := compareValues(left, right)
+		if !ok {
+			return nil, semanticError("`%s' is not comparable with `%s'", typeName(left), typeName(right))
+		}
+		switch e.op {
+		case "<":
+			return c < 0, nil
+		case ">":
+			return c > 0, nil
+		case "<=":
+			return c <= 0, nil
+		}
+		return c >= 0, nil
+	case "in", "not in":
+		if left == nil || right == nil {
+			return nil, nil
+		}
+		list, ok := right.([]interface{})
+		if !ok {
+			return nil, semanticError("`%s' should be a LIST, but was %s", e.op, typeName(right))
+		}
+		found := false
+		for _, item := range list {
+			if item != nil && equalValues(left, item) {
+				found = true
+				break
+			}
+		}
+		return found == (e.op == "in"), nil
+	case "contains", "starts with", "ends with", "=~":
+		if left == nil || right == nil {
+			return nil, nil
+		}
+		l, lok := left.(string)
+		r, rok := right.(string)
+		if !lok || !rok {
+			return nil, semanticError("`%s' requires STRING operands", e.op)
+		}
+		switch e.op {
+		case "contains":
+			return strings.Contains(l, r), nil
+		case "starts with":
+			return strings.HasPrefix(l, r), nil
+		case "ends with":
+			return strings.HasSuffix(l, r), nil
+		}
+		re, err := regexp.Compile("^(?:" + r + ")$")
+		if err != nil {
+			return nil, semanticError("invalid regular expression `%s'", r)
+		}
+		return re.MatchString(l), nil
+	}
+	return arithmetic(e.op, left, right)
+}
+
+// 三值逻辑运算
+func (e *binaryExpr) logic(sc *scope, left interface{}) (interface{}, error) {
+	if left != nil {
+		if _, ok := left.(bool); !ok {
+			return nil, semanticError("`%s' requires BOOL operands, but was %s", e.op, typeName(left))
+		}
+	}
+	if e.op == "and" && left == false {
+		return false, nil
+	}
+	if e.op == "or" && left == true {
+		return true, nil
+	}
+	right, err := e.right.eval(sc)
+	if err != nil {
+		return nil, err
+	}
+	if right != nil {
+		if _, ok := right.(bool); !ok {
+			return nil, semanticError("`%s' requires BOOL operands, but was %s", e.op, typeName(right))
+		}
+	}
+	switch e.op {
+	case "and":
+		if right == false {
+			return false, nil
+		}
+		if left == nil || right == nil {
+			return nil, nil
+		}
+		return true, nil
+	case "or":
+		if right == true {
+			return true, nil
+		}
+		if left == nil || right == nil {
+			return nil, nil
+		}
+		return false, nil
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	return left.(bool) != right.(bool), nil
+}
+
+// 算术运算, 字符串与列表支持 + 拼接
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func arithmetic(op string, left, right interface{}) (interface{}, error) {
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	if op == "+" {
+		if l, ok := left.([]interface{}); ok {
+			if r, ok := right.([]interface{}); ok {
+				return append(append([]interface{}{}, l...), r...), nil
+			}
+			return append(append([]interface{}{}, l...), right), nil
+		}
+		if r, ok := right.([]interface{}); ok {
+			return append([]interface{}{left}, r...), nil
+		}
+		_, ls := left.(string)
+		_, rs := right.(string)
+		if ls || rs {
+			return valueString(left) + valueString(right), nil
+		}
+	}
+
+	li, lInt := left.(int64)
+	ri, rInt := right.(int64)
+	if lInt && rInt {
+		switch op {
+		case "+":
+			return li + ri, nil
+		case "-":
+			return li - ri, nil
+		case "*":
+			return li * ri, nil
+		case "/", "%":
+			if ri == 0 {
+				return nil, semanticError("Division by zero")
+			}
+			if op == "/" {
+				return li / ri, nil
+			}
+			return li % ri, nil
+		}
+	}
+	lf, lok := toFloat(left)
+	rf, rok := toFloat(right)
+	if lok && rok {
+		switch op {
+		case "+":
+			return lf + rf, nil
+		case "-":
+			return lf - rf, nil
+		case "*":
+			return lf * rf, nil
+		case "/":
+			if rf == 0 {
+				return nil, semanticError("Division by zero")
+			}
+			return lf / rf, nil
+		case "%":
+			if rf == 0 {
+				return nil, semanticError("Division by zero")
+			}
+			return lf - rf*float64(int64(lf/rf)), nil
+		}
+	}
+	return nil, semanticError("`%s' is not a valid operand of `%s' with `%s'", typeName(left), op, typeName(right))
+}
+
+// 判空: x is null、x is not null
+type isNullExpr struct {
+	operand expr
+	not     bool
+}
+
+func (e *isNullExpr) eval(sc *scope) (interface{}, error) {
+	value, err := e.operand.eval(sc)
+	if err != nil {
+		return nil, err
+	}
+	return (value == nil) != e.not, nil
+}
+
+// 条件表达式: case [x] when ... then ... [else ...] end
+type caseExpr struct {
+	subject expr
+	whens   []expr
+	thens   []expr
+	orElse  expr
+}
+
+func (e *caseExpr) eval(sc *scope) (interface{}, error) {
+	var subject interface{}
+	if e.subject != nil {
+		var err error
+		if subject, err = e.subject.eval(sc); err != nil {
+			return nil, err
+		}
+	}
+	for i, when := range e.whens {
+		value, err := when.eval(sc)
+		if err != nil {
+			return nil, err
+		}
+		matched := value == true
+		if e.subject != nil {
+			matched = subject != nil && value != nil && equalValues(subject, value)
+		}
+		if matched {
+			return e.thens[i].eval(sc)
+		}
+	}
+	if e.orElse != nil {
+		return e.orElse.eval(sc)
+	}
+	return nil, nil
+}
+
+// 函数调用
+type callExpr struct {
+	name     string // 小写的函数名
+	args     []expr
+	star     bool // count(*)
+	distinct bool // count(distinct x)
+}
+
+func (e *callExpr) eval(sc *scope) (interface{}, error) {
+	if isAggregate(e.name) {
+		value, ok := sc.aggs[e]
+		if !ok {
+			return nil, semanticError("Invalid use of aggregating function `%s'", e.name)
+		}
+		return value, nil
+	}
+	args := make([]interface{}, len(e.args))
+	for i, arg := range e.args {
+		value, err := arg.eval(sc)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	return callFunction(sc, e.name, args)
+}
+
+// 求值结果是否为true, null视为false
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func evalBool(e expr, sc *scope) (bool, error) {
+	if e == nil {
+		return true, nil
+	}
+	value, err := e.eval(sc)
+	if err != nil {
+		return false, err
+	}
+	switch val := value.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return val, nil
+	}
+	return false, semanticError("`%s' is not a valid filter, the type should be BOOL", typeName(value))
+}
+
+// 遍历表达式树
+func walkExpr(e expr, fn func(expr)) {
+	if e == nil {
+		return
+	}
+	fn(e)
+	switch val := e.(type) {
+	case *listExpr:
+		for _, item := range val.items {
+			walkExpr(item, fn)
+		}
+	case *mapExpr:
+		for _, item := range val.items {
+			walkExpr(item, fn)
+		}
+	case *propExpr:
+		walkExpr(val.base, fn)
+	case *indexExpr:
+		walkExpr(val.base, fn)
+		walkExpr(val.index, fn)
+	case *unaryExpr:
+		walkExpr(val.operand, fn)
+	case *binaryExpr:
+		walkExpr(val.left, fn)
+		walkExpr(val.right, fn)
+	case *isNullExpr:
+		walkExpr(val.operand, fn)
+	case *caseExpr:
+		walkExpr(val.subject, fn)
+		for i := range val.whens {
+			walkExpr(val.whens[i], fn)
+			walkExpr(val.thens[i], fn)
+		}
+		walkExpr(val.orElse, fn)
+	case *callExpr:
+		for _, arg := range val.args {
+			walkExpr(arg, fn)
+		}
+	}
+}
+
+// 收集表达式中的聚合函数调用
+func aggregatesOf(e expr) []*callExpr {
+	var calls []*callExpr
+	walkExpr(e, func(node expr) {
+		if call, ok := node.(*callExpr); ok && isAggregate(call.name) {
+			calls = append(calls, call)
+		}
+	})
+	return calls
+}
+
+// ---------------------------------------------------------------- 表达式语法分析
+
+// 解析表达式, 运算符优先级从低到高: or、xor、and、not、比较、加减、乘除、一元、后缀
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func (p *parser) parseExpr() (expr, error) {
+	return p.parseBinary(0)
+}
+
+// 各优先级的二元逻辑运算符
+var logicLevels = []string{"or", "xor", "and"}
+
+// 解析逻辑运算
+func (p *parser) parseBinary(level int) (expr, error) {
+	if level == len(logicLevels) {
+		return p.parseNot()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(logicLevels[level]) {
+		p.next()
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: logicLevels[level], left: left, right: right}
+	}
+	return left, nil
+}
+
+// 解析 not
+func (p *parser) parseNot() (expr, error) {
+	if p.peek().is("not") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "not", operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+// 解析比较运算
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		op := ""
+		switch {
+		case tok.isSymbol("==") || tok.isSymbol("!=") || tok.isSymbol("<") || tok.isSymbol(">") ||
+			tok.isSymbol("<=") || tok.isSymbol(">=") || tok.isSymbol("=~"):
+			op = tok.text
+			p.next()
+		case tok.isSymbol("<>"):
+			op = "!="
+			p.next()
+		case tok.is("in") || tok.is("contains"):
+			op = strings.ToLower(tok.text)
+			p.next()
+		case tok.is("not") && p.peekAt(1).is("in"):
+			op = "not in"
+			p.pos += 2
+		case (tok.is("starts") || tok.is("ends")) && p.peekAt(1).is("with"):
+			op = strings.ToLower(tok.text) + " with"
+			p.pos += 2
+		case tok.is("is"):
+			p.next()
+			not := p.accept("not")
+			if err := p.expect("null"); err != nil {
+				return nil, err
+			}
+			left = &isNullExpr{operand: left, not: not}
+			continue
+		default:
+			return left, nil
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: op, left: left, right: right}
+	}
+}
+
+// 解析加减
+func (p *parser) parseAdditive() (expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isSymbol("+") || p.peek().isSymbol("-") {
+		op := p.next().text
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// 解析乘除取余
+func (p *parser) parseMultiplicative() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isSymbol("*") || p.peek().isSymbol("/") || p.peek().isSymbol("%") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// 解析一元运算
+func (p *parser) parseUnary() (expr, error) {
+	switch {
+	case p.peek().isSymbol("-"):
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		// 负数常量直接折叠, 保证 -9223372036854775808 这类边界值可用
+		if literal, ok := operand.(*literalExpr); ok {
+			switch val := literal.value.(type) {
+			case int64:
+				return &literalExpr{value: -val}, nil
+			case float64:
+				return &literalExpr{value: -val}, nil
+			}
+		}
+		return &unaryExpr{op: "-", operand: operand}, nil
+	case p.peek().isSymbol("+"):
+		p.next()
+		return p.parseUnary()
+	case p.peek().isSymbol("!"):
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "not", operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+// 解析属性访问与下标访问
+func (p *parser) parsePostfix() (expr, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.peek().isSymbol("."):
+			p.next()
+			tok := p.next()
+			if tok.kind != tokenIdent {
+				return nil, p.syntaxError(tok)
+			}
+			base = &propExpr{base: base, name: tok.text}
+		case p.peek().isSymbol("["):
+			p.next()
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectSymbol("]"); err != nil {
+				return nil, err
+			}
+			base = &indexExpr{base: base, index: index}
+		default:
+			return base, nil
+		}
+	}
+}
+
+// 解析基本表达式
+func (p *parser) parsePrimary() (expr, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenString:
+		return &literalExpr{value: tok.text}, nil
+
+	case tokenInt:
+		value, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			// 超出int64范围的整数, 例如 -9223372036854775808 的绝对值
+			if tok.text == "9223372036854775808" {
+				return &literalExpr{value: int64(-9223372036854775808)}, nil
+			}
+			return nil, syntaxError("Out of range: near `%s'", tok.text)
+		}
+		return &literalExpr{value: value}, nil
+
+	case tokenFloat:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.syntaxError(tok)
+		}
+		return &literalExpr{value: value}, nil
+
+	case tokenSymbol:
+		switch tok.text {
+		case "$$", "$^", "$-":
+			return &varExpr{name: tok.text}, nil
+		case "(":
+			inner, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expectSymbol(")")
+		case "[":
+			list := &listExpr{}
+			for !p.peek().isSymbol("]") {
+				item, err := p.parseExpr()
+				if err != nil {
+					return nil, err
+				}
+				list.items = append(list.items, item)
+				if !p.acceptSymbol(",") {
+					break
+				}
+			}
+			return list, p.expectSymbol("]")
+		case "{":
+			return p.parseMap()
+		}
+
+	case tokenIdent:
+		if !tok.quote {
+			switch strings.ToLower(tok.text) {
+			case "true":
+				return &literalExpr{value: true}, nil
+			case "false":
+				return &literalExpr{value: false}, nil
+			case "null":
+				return &literalExpr{value: nil}, nil
+			case "case":
+				return p.parseCase()
+			}
+		}
+		if !tok.quote && p.peek().isSymbol("(") {
+			return p.parseCall(strings.ToLower(tok.text))
+		}
+		return &varExpr{name: tok.text}, nil
+	}
+	return nil, p.syntaxError(tok)
+}
+
+// 解析映射常量 {k: v, ...}
+func (p *parser) parseMap() (expr, error) {
+	m := &mapExpr{}
+	for !p.peek().isSymbol("}") {
+		key := p.next()
+		if key.kind != tokenIdent && key.kind != tokenString {
+			return nil, p.syntaxError(key)
+		}
+		if err := p.expectSymbol(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		m.keys = append(m.keys, key.text)
+		m.items = append(m.items, value)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return m, p.expectSymbol("}")
+}
+
+// 解析函数调用
+func (p *parser) parseCall(name string) (expr, error) {
+	call := &callExpr{name: name}
+	p.next() // (
+	if p.acceptSymbol("*") {
+		call.star = true
+		return call, p.expectSymbol(")")
+	}
+	call.distinct = p.accept("distinct")
+	for !p.peek().isSymbol(")") {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return call, p.expectSymbol(")")
+}
+
+// 解析 case 表达式
+func (p *parser) parseCase() (expr, error) {
+	c := &caseExpr{}
+	var err error
+	if !p.peek().is("when") {
+		if c.subject, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	for p.accept("when") {
+		when, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("then"); err != nil {
+			return nil, err
+		}
+		then, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		c.whens = append(c.whens, when)
+		c.thens = append(c.thens, then)
+	}
+	if p.accept("else") {
+		if c.orElse, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return c, p.expect("end")
+}
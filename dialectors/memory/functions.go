@@ -0,0 +1,587 @@
+package memory
+
+import (
+	"encoding/binary"
+	nebula_type "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 聚合函数
+var aggregates = map[string]bool{"count": true, "sum": true, "avg": true, "min": true, "max": true, "collect": true}
+
+// 是否为聚合函数
+func isAggregate(name string) bool {
+	return aggregates[name]
+}
+
+// 调用内置函数, 参数已求值
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func callFunction(sc *scope, name string, args []interface{}) (interface{}, error) {
+	switch name {
+	case "now", "timestamp", "datetime", "date", "time":
+		return timeFunction(name, args)
+	case "coalesce":
+		for _, arg := range args {
+			if arg != nil {
+				return arg, nil
+			}
+		}
+		return nil, nil
+	case "concat":
+		builder := new(strings.Builder)
+		for _, arg := range args {
+			if arg == nil {
+				return nil, nil
+			}
+			builder.WriteString(valueString(arg))
+		}
+		return builder.String(), nil
+	case "range":
+		return rangeFunction(args)
+	}
+
+	if len(args) != 1 {
+		if len(args) == 0 {
+			return nil, semanticError("Unknown function `%s' or wrong number of arguments", name)
+		}
+		return stringFunction(name, args)
+	}
+	arg := args[0]
+	switch name {
+	case "id":
+		if v, ok := arg.(*vertex); ok {
+			return v.vid, nil
+		}
+	case "src", "dst", "rank", "type":
+		if e, ok := arg.(*edge); ok {
+			switch name {
+			case "src":
+				return e.src, nil
+			case "dst":
+				return e.dst, nil
+			case "rank":
+				return e.rank, nil
+			}
+			return e.name, nil
+		}
+	case "properties":
+		switch val := arg.(type) {
+		case *vertex:
+			return val.allProps(), nil
+		case *edge:
+			return copyProps(val.props), nil
+		case map[string]interface{}:
+			return val, nil
+		}
+	case "tags", "labels":
+		if v, ok := arg.(*vertex); ok {
+			names := make([]interface{}, 0, len(v.tags))
+			for _, tag := range v.tagNames() {
+				names = append(names, tag)
+			}
+			return names, nil
+		}
+	case "keys":
+		var props map[string]interface{}
+		switch val := arg.(type) {
+		case *vertex:
+			props = val.allProps()
+		case *edge:
+			props = val.props
+		case map[string]interface{}:
+			props = val
+		}
+		if props != nil {
+			keys := make([]interface{}, 0, len(props))
+			for key := range props {
+				keys = append(keys, key)
+			}
+			sortValues(keys)
+			return keys, nil
+		}
+	case "size":
+		switch val := arg.(type) {
+		case string:
+			return int64(len([]rune(val))), nil
+		case []interface{}:
+			return int64(len(val)), nil
+		case map[string]interface{}:
+			return int64(len(val)), nil
+		}
+	case "length":
+		switch val := arg.(type) {
+		case *path:
+			return int64(len(val.edges)), nil
+		case string:
+			return int64(len([]rune(val))), nil
+		}
+	case "nodes", "relationships":
+		if p, ok := arg.(*path); ok {
+			var items []interface{}
+			if name == "nodes" {
+				for _, node := range p.nodes {
+					items = append(items, node)
+				}
+			} else {
+				for _, e := range p.edges {
+					items = append(items, e)
+				}
+			}
+			return items, nil
+		}
+	case "startnode", "endnode":
+		if p, ok := arg.(*path); ok {
+			if name == "startnode" {
+				return p.nodes[0], nil
+			}
+			return p.nodes[len(p.nodes)-1], nil
+		}
+	case "head", "last":
+		if list, ok := arg.([]interface{}); ok {
+			if len(list) == 0 {
+				return nil, nil
+			}
+			if name == "head" {
+				return list[0], nil
+			}
+			return list[len(list)-1], nil
+		}
+	case "hash":
+		return hashFunction(arg)
+	case "tostring":
+		return valueString(arg), nil
+	case "tointeger", "toint":
+		return toInteger(arg)
+	case "tofloat":
+		if f, ok := toFloat(arg); ok {
+			return f, nil
+		}
+	case "toboolean":
+		if s, ok := arg.(string); ok {
+			switch strings.ToLower(s) {
+			case "true":
+				return true, nil
+			case "false":
+				return false, nil
+			}
+			return nil, nil
+		}
+		if b, ok := arg.(bool); ok {
+			return b, nil
+		}
+	case "abs", "floor", "ceil", "round", "sqrt":
+		return mathFunction(name, arg)
+	case "lower", "tolower", "upper", "toupper", "trim", "ltrim", "rtrim", "reverse":
+		return stringFunction(name, args)
+	default:
+		if isAggregate(name) {
+			return nil, semanticError("Invalid use of aggregating function `%s'", name)
+		}
+		return nil, semanticError("Unknown function `%s'", name)
+	}
+	if arg == nil {
+		return nil, nil
+	}
+	return nil, semanticError("`%s' is not a valid argument of function `%s'", typeName(arg), name)
+}
+
+// 字符串函数
+func stringFunction(name string, args []interface{}) (interface{}, error) {
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+	str, ok := args[0].(string)
+	if !ok {
+		if name == "reverse" {
+			if list, ok := args[0].([]interface{}); ok {
+				reversed := make([]interface{}, len(list))
+				for i, item := range list {
+					reversed[len(list)-1-i] = item
+				}
+				return reversed, nil
+			}
+		}
+		return nil, semanticError("`%s' is not a valid argument of function `%s'", typeName(args[0]), name)
+	}
+	switch {
+	case (name == "lower" || name == "tolower") && len(args) == 1:
+		return strings.ToLower(str), nil
+	case (name == "upper" || name == "toupper") && len(args) == 1:
+		return strings.ToUpper(str), nil
+	case name == "trim" && len(args) == 1:
+		return strings.TrimSpace(str), nil
+	case name == "ltrim" && len(args) == 1:
+		return strings.TrimLeft(str, " \t\r\n"), nil
+	case name == "rtrim" && len(args) == 1:
+		return strings.TrimRight(str, " \t\r\n"), nil
+	case name == "reverse" && len(args) == 1:
+		runes := []rune(str)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes), nil
+	case name == "split" && len(args) == 2:
+		sep, ok := args[1].(string)
+		if ok {
+			var items []interface{}
+			for _, item := range strings.Split(str, sep) {
+				items = append(items, item)
+			}
+			return items, nil
+		}
+	case name == "replace" && len(args) == 3:
+		old, ok1 := args[1].(string)
+		replacement, ok2 := args[2].(string)
+		if ok1 && ok2 {
+			return strings.ReplaceAll(str, old, replacement), nil
+		}
+	case (name == "substr" || name == "substring") && (len(args) == 2 || len(args) == 3):
+		runes := []rune(str)
+		start, ok := args[1].(int64)
+		if !ok || start < 0 {
+			break
+		}
+		if start > int64(len(runes)) {
+			return "", nil
+		}
+		end := int64(len(runes))
+		if len(args) == 3 {
+			size, ok := args[2].(int64)
+			if !ok || size < 0 {
+				break
+			}
+			if start+size < end {
+				end = start + size
+			}
+		}
+		return string(runes[start:end]), nil
+	default:
+		return nil, semanticError("Unknown function `%s' or wrong number of arguments", name)
+	}
+	return nil, semanticError("invalid arguments of function `%s'", name)
+}
+
+// 数学函数
+func mathFunction(name string, arg interface{}) (interface{}, error) {
+	if i, ok := arg.(int64); ok && name != "sqrt" {
+		if name == "abs" && i < 0 {
+			return -i, nil
+		}
+		return i, nil
+	}
+	f, ok := toFloat(arg)
+	if !ok {
+		if arg == nil {
+			return nil, nil
+		}
+		return nil, semanticError("`%s' is not a valid argument of function `%s'", typeName(arg), name)
+	}
+	switch name {
+	case "abs":
+		return math.Abs(f), nil
+	case "floor":
+		return math.Floor(f), nil
+	case "ceil":
+		return math.Ceil(f), nil
+	case "round":
+		return math.Round(f), nil
+	}
+	return math.Sqrt(f), nil
+}
+
+// range(start, end[, step]), 包含 end
+func rangeFunction(args []interface{}) (interface{}, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, semanticError("Unknown function `range' or wrong number of arguments")
+	}
+	ints := make([]int64, len(args))
+	for i, arg := range args {
+		value, ok := arg.(int64)
+		if !ok {
+			return nil, semanticError("`%s' is not a valid argument of function `range'", typeName(arg))
+		}
+		ints[i] = value
+	}
+	step := int64(1)
+	if len(ints) == 3 {
+		step = ints[2]
+	}
+	if step == 0 {
+		return nil, semanticError("The step of range should not be 0")
+	}
+	var items []interface{}
+	for i := ints[0]; (step > 0 && i <= ints[1]) || (step < 0 && i >= ints[1]); i += step {
+		items = append(items, i)
+	}
+	return items, nil
+}
+
+// 转换为整数
+func toInteger(arg interface{}) (interface{}, error) {
+	switch val := arg.(type) {
+	case nil:
+		return nil, nil
+	case int64:
+		return val, nil
+	case float64:
+		return int64(val), nil
+	case string:
+		if i, err := strconv.ParseInt(strings.TrimSpace(val), 10, 64); err == nil {
+			return i, nil
+		}
+		if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+			return int64(f), nil
+		}
+		return nil, nil
+	case bool:
+		if val {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	}
+	return nil, semanticError("`%s' is not a valid argument of function `toInteger'", typeName(arg))
+}
+
+// hash 函数, 与graphd一致: 字符串使用 MurmurHash2(64位, 种子 0xc70f6907), 整数返回其本身
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func hashFunction(arg interface{}) (interface{}, error) {
+	switch val := arg.(type) {
+	case nil:
+		return nil, nil
+	case int64:
+		return val, nil
+	case bool:
+		if val {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case string:
+		return int64(murmurHash64A([]byte(val), 0xc70f6907)), nil
+	}
+	return nil, semanticError("`%s' is not a valid argument of function `hash'", typeName(arg))
+}
+
+// MurmurHash64A
+func murmurHash64A(data []byte, seed uint64) uint64 {
+	const m = uint64(0xc6a4a7935bd1e995)
+	const r = 47
+	h := seed ^ (uint64(len(data)) * m)
+	for len(data) >= 8 {
+		k := binary.LittleEndian.Uint64(data)
+		k *= m
+		k ^= k >> r
+		k *= m
+		h ^= k
+		h *= m
+		data = data[8:]
+	}
+	switch len(data) {
+	case 7:
+		h ^= uint64(data[6]) << 48
+		fallthrough
+	case 6:
+		h ^= uint64(data[5]) << 40
+		fallthrough
+	case 5:
+		h ^= uint64(data[4]) << 32
+		fallthrough
+	case 4:
+		h ^= uint64(data[3]) << 24
+		fallthrough
+	case 3:
+		h ^= uint64(data[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint64(data[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint64(data[0])
+		h *= m
+	}
+	h ^= h >> r
+	h *= m
+	h ^= h >> r
+	return h
+}
+
+// 时间函数: now()、timestamp()、datetime()、date()、time(), 均使用UTC时间
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func timeFunction(name string, args []interface{}) (interface{}, error) {
+	if len(args) > 1 {
+		return nil, semanticError("Unknown function `%s' or wrong number of arguments", name)
+	}
+	t := time.Now().UTC()
+	if len(args) == 1 {
+		if name == "now" {
+			return nil, semanticError("Unknown function `now' or wrong number of arguments")
+		}
+		switch val := args[0].(type) {
+		case nil:
+			return nil, nil
+		case int64:
+			t = time.Unix(val, 0).UTC()
+		case string:
+			parsed, err := parseTime(name, val)
+			if err != nil {
+				return nil, err
+			}
+			t = parsed
+		case nebula_type.DateTime:
+			t = time.Date(int(val.Year), time.Month(val.Month), int(val.Day), int(val.Hour), int(val.Minute),
+				int(val.Sec), int(val.Microsec)*1000, time.UTC)
+		default:
+			return nil, semanticError("`%s' is not a valid argument of function `%s'", typeName(args[0]), name)
+		}
+	}
+
+	switch name {
+	case "now", "timestamp":
+		return t.Unix(), nil
+	case "date":
+		return nebula_type.Date{Year: int16(t.Year()), Month: int8(t.Month()), Day: int8(t.Day())}, nil
+	case "time":
+		return nebula_type.Time{Hour: int8(t.Hour()), Minute: int8(t.Minute()), Sec: int8(t.Second()),
+			Microsec: int32(t.Nanosecond() / 1000)}, nil
+	}
+	return toDateTime(t), nil
+}
+
+// 转换为nebula的datetime
+func toDateTime(t time.Time) nebula_type.DateTime {
+	return nebula_type.DateTime{
+		Year:     int16(t.Year()),
+		Month:    int8(t.Month()),
+		Day:      int8(t.Day()),
+		Hour:     int8(t.Hour()),
+		Minute:   int8(t.Minute()),
+		Sec:      int8(t.Second()),
+		Microsec: int32(t.Nanosecond() / 1000),
+	}
+}
+
+// 解析时间字符串
+func parseTime(name, value string) (time.Time, error) {
+	var layouts []string
+	switch name {
+	case "date":
+		layouts = []string{"2006-01-02", "2006-01", "2006"}
+	case "time":
+		layouts = []string{"15:04:05.999999", "15:04:05", "15:04"}
+	default:
+		layouts = []string{"2006-01-02T15:04:05.999999", "2006-01-02 15:04:05.999999", "2006-01-02T15:04:05",
+			"2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02"}
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, semanticError("Invalid %s value `%s'", name, value)
+}
+
+// 计算聚合函数在一组行上的结果
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func computeAggregate(call *callExpr, rows []*scope) (interface{}, error) {
+	var values []interface{}
+	for _, row := range rows {
+		if call.star {
+			values = append(values, int64(1))
+			continue
+		}
+		if len(call.args) != 1 {
+			return nil, semanticError("Unknown function `%s' or wrong number of arguments", call.name)
+		}
+		value, err := call.args[0].eval(row)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+		if call.distinct && containsValue(values, value) {
+			continue
+		}
+		values = append(values, value)
+	}
+
+	switch call.name {
+	case "count":
+		return int64(len(values)), nil
+	case "collect":
+		if values == nil {
+			values = []interface{}{}
+		}
+		return values, nil
+	case "min", "max":
+		var result interface{}
+		for _, value := range values {
+			if result == nil {
+				result = value
+				continue
+			}
+			c := orderValues(value, result)
+			if (call.name == "min" && c < 0) || (call.name == "max" && c > 0) {
+				result = value
+			}
+		}
+		return result, nil
+	}
+
+	// sum、avg
+	var sumInt int64
+	var sumFloat float64
+	isFloat := false
+	for _, value := range values {
+		switch val := value.(type) {
+		case int64:
+			sumInt += val
+		case float64:
+			sumFloat += val
+			isFloat = true
+		default:
+			return nil, semanticError("`%s' is not a valid argument of function `%s'", typeName(value), call.name)
+		}
+	}
+	if call.name == "avg" {
+		if len(values) == 0 {
+			return nil, nil
+		}
+		return (float64(sumInt) + sumFloat) / float64(len(values)), nil
+	}
+	if isFloat {
+		return float64(sumInt) + sumFloat, nil
+	}
+	return sumInt, nil
+}
+
+// 列表中是否包含指定值
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, item := range values {
+		if item != nil && equalValues(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// 排序值列表
+func sortValues(values []interface{}) {
+	for i := 1; i < len(values); i++ {
+		for j := i; j > 0 && orderValues(values[j], values[j-1]) < 0; j-- {
+			values[j], values[j-1] = values[j-1], values[j]
+		}
+	}
+}
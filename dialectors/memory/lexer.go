@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 词法单元类型
+type tokenKind int
+
+const (
+	tokenEOF    tokenKind = iota // 结束
+	tokenIdent                   // 标识符或关键字, 关键字统一按小写比较
+	tokenString                  // 字符串字面量(已去除引号并处理转义)
+	tokenInt                     // 整数字面量
+	tokenFloat                   // 浮点数字面量
+	tokenSymbol                  // 运算符与分隔符
+)
+
+// 词法单元
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type token struct {
+	kind  tokenKind
+	text  string // 原始文本, 字符串为去除引号后的内容
+	pos   int    // 在语句中的起始位置, 用于生成错误信息与列名
+	end   int    // 在语句中的结束位置
+	quote bool   // 标识符是否使用反引号包裹
+}
+
+// 是否为指定的关键字(忽略大小写)
+func (t token) is(keyword string) bool {
+	return t.kind == tokenIdent && !t.quote && strings.EqualFold(t.text, keyword)
+}
+
+// 是否为指定的符号
+func (t token) isSymbol(symbol string) bool {
+	return t.kind == tokenSymbol && t.text == symbol
+}
+
+// 多字符符号, 按长度从长到短匹配
+var symbols = []string{"->", "<-", "==", "!=", "<>", ">=", "<=", "..", "$$", "$^", "$-", "=~",
+	"(", ")", "[", "]", "{", "}", ",", ":", ";", ".", "=", "<", ">", "+", "-", "*", "/", "%", "@", "|", "!"}
+
+// 将nGQL切分为词法单元
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func tokenize(ngql string) ([]token, error) {
+	var tokens []token
+	runes := []rune(ngql)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '#' || (r == '/' && i+1 < len(runes) && runes[i+1] == '/'):
+			// 行注释
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '\'' || r == '"':
+			builder := new(strings.Builder)
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+					switch runes[j] {
+					case 'n':
+						builder.WriteRune('\n')
+					case 't':
+						builder.WriteRune('\t')
+					default:
+						builder.WriteRune(runes[j])
+					}
+					continue
+				}
+				builder.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, syntaxError("字符串未闭合 near `%s'", string(runes[i:]))
+			}
+			tokens = append(tokens, token{kind: tokenString, text: builder.String(), pos: i, end: j + 1})
+			i = j + 1
+
+		case r == '`':
+			j := i + 1
+			for j < len(runes) && runes[j] != '`' {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, syntaxError("标识符未闭合 near `%s'", string(runes[i:]))
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[i+1 : j]), pos: i, end: j + 1, quote: true})
+			i = j + 1
+
+		case unicode.IsDigit(r):
+			j := i
+			kind := tokenInt
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			// 小数部分, 注意区分范围符号 1..2
+			if j+1 < len(runes) && runes[j] == '.' && unicode.IsDigit(runes[j+1]) {
+				kind = tokenFloat
+				j++
+				for j < len(runes) && unicode.IsDigit(runes[j]) {
+					j++
+				}
+			}
+			if j < len(runes) && (runes[j] == 'e' || runes[j] == 'E') {
+				k := j + 1
+				if k < len(runes) && (runes[k] == '+' || runes[k] == '-') {
+					k++
+				}
+				if k < len(runes) && unicode.IsDigit(runes[k]) {
+					kind = tokenFloat
+					for j = k; j < len(runes) && unicode.IsDigit(runes[j]); j++ {
+					}
+				}
+			}
+			tokens = append(tokens, token{kind: kind, text: string(runes[i:j]), pos: i, end: j})
+			i = j
+
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[i:j]), pos: i, end: j})
+			i = j
+
+		default:
+			matched := false
+			for _, symbol := range symbols {
+				size := len([]rune(symbol))
+				if i+size <= len(runes) && string(runes[i:i+size]) == symbol {
+					tokens = append(tokens, token{kind: tokenSymbol, text: symbol, pos: i, end: i + size})
+					i += size
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, syntaxError("无法识别的字符 `%c'", r)
+			}
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes), end: len(runes)})
+	return tokens, nil
+}
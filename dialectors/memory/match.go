@@ -0,0 +1,623 @@
+package memory
+
+// ---------------------------------------------------------------- 模式
+
+// 点模式 (v:tag{prop: value})
+type nodePattern struct {
+	variable string
+	labels   []string
+	props    *mapExpr
+}
+
+// 边模式 -[e:type1|type2*1..3{prop: value}]->
+type relPattern struct {
+	variable  string
+	types     []string
+	direction string // 从左侧的点看的方向
+	varLength bool
+	minHops   int64
+	maxHops   int64 // 小于0表示不限制
+	props     *mapExpr
+}
+
+// 路径模式 p = (a)-[e]->(b)...
+type pathPattern struct {
+	variable string
+	nodes    []nodePattern
+	rels     []relPattern
+}
+
+// match 子句
+type matchClause struct {
+	patterns []pathPattern
+	where    expr
+}
+
+// match pattern, ... [where ...] [match ...] return [distinct] ... [order by ...] [skip n] [limit n]
+type matchStmt struct {
+	clauses  []matchClause
+	items    []yieldItem
+	distinct bool
+	orderBy  []orderItem
+	skip     int64
+	limit    int64 // 小于0表示不限制
+}
+
+func (p *parser) parseMatch() (statement, error) {
+	stmt := &matchStmt{limit: -1}
+	for {
+		var clause matchClause
+		for {
+			pattern, err := p.parsePathPattern()
+			if err != nil {
+				return nil, err
+			}
+			clause.patterns = append(clause.patterns, pattern)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if p.accept("where") {
+			var err error
+			if clause.where, err = p.parseExpr(); err != nil {
+				return nil, err
+			}
+		}
+		stmt.clauses = append(stmt.clauses, clause)
+		if !p.accept("match") {
+			break
+		}
+	}
+
+	if err := p.expect("return"); err != nil {
+		return nil, err
+	}
+	var err error
+	if stmt.items, stmt.distinct, err = p.parseYieldItems(); err != nil {
+		return nil, err
+	}
+	if p.peek().is("order") && p.peekAt(1).is("by") {
+		p.pos += 2
+		if stmt.orderBy, err = p.parseOrderBy(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("skip") {
+		if stmt.skip, err = p.integer(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("limit") {
+		if stmt.limit, err = p.integer(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// 解析路径模式
+func (p *parser) parsePathPattern() (pathPattern, error) {
+	var pattern pathPattern
+	if p.peek().kind == tokenIdent && p.peekAt(1).isSymbol("=") {
+		pattern.variable = p.next().text
+		p.next()
+	}
+	node, err := p.parseNodePattern()
+	if err != nil {
+		return pattern, err
+	}
+	pattern.nodes = append(pattern.nodes, node)
+	for p.peek().isSymbol("-") || p.peek().isSymbol("<-") {
+		rel, err := p.parseRelPattern()
+		if err != nil {
+			return pattern, err
+		}
+		node, err := p.parseNodePattern()
+		if err != nil {
+			return pattern, err
+		}
+		pattern.rels = append(pattern.rels, rel)
+		pattern.nodes = append(pattern.nodes, node)
+	}
+	return pattern, nil
+}
+
+// 解析点模式
+func (p *parser) parseNodePattern() (nodePattern, error) {
+	var node nodePattern
+	if err := p.expectSymbol("("); err != nil {
+		return node, err
+	}
+	if p.peek().kind == tokenIdent {
+		node.variable = p.next().text
+	}
+	for p.acceptSymbol(":") {
+		label, err := p.ident()
+		if err != nil {
+			return node, err
+		}
+		node.labels = append(node.labels, label)
+	}
+	if p.acceptSymbol("{") {
+		props, err := p.parseMap()
+		if err != nil {
+			return node, err
+		}
+		node.props = props.(*mapExpr)
+	}
+	return node, p.expectSymbol(")")
+}
+
+// 解析边模式
+func (p *parser) parseRelPattern() (relPattern, error) {
+	rel := relPattern{minHops: 1, maxHops: 1}
+	left := p.acceptSymbol("<-")
+	if !left {
+		if err := p.expectSymbol("-"); err != nil {
+			return rel, err
+		}
+	}
+	if p.acceptSymbol("[") {
+		if p.peek().kind == tokenIdent {
+			rel.variable = p.next().text
+		}
+		if p.acceptSymbol(":") {
+			for {
+				name, err := p.ident()
+				if err != nil {
+					return rel, err
+				}
+				rel.types = append(rel.types, name)
+				if !p.acceptSymbol("|") {
+					break
+				}
+				p.acceptSymbol(":")
+			}
+		}
+		if p.acceptSymbol("*") {
+			if err := p.parseHops(&rel); err != nil {
+				return rel, err
+			}
+		}
+		if p.acceptSymbol("{") {
+			props, err := p.parseMap()
+			if err != nil {
+				return rel, err
+			}
+			rel.props = props.(*mapExpr)
+		}
+		if err := p.expectSymbol("]"); err != nil {
+			return rel, err
+		}
+	}
+	right := p.acceptSymbol("->")
+	if !right {
+		if err := p.expectSymbol("-"); err != nil {
+			return rel, err
+		}
+	}
+	switch {
+	case right && !left:
+		rel.direction = directionOut
+	case left && !right:
+		rel.direction = directionIn
+	default:
+		rel.direction = directionBoth
+	}
+	return rel, nil
+}
+
+// 解析变长边的跳数: *、*n、*m..n、*..n、*m..
+func (p *parser) parseHops(rel *relPattern) error {
+	rel.varLength = true
+	rel.minHops, rel.maxHops = 1, -1
+	var err error
+	if p.peek().kind == tokenInt {
+		if rel.minHops, err = p.integer(); err != nil {
+			return err
+		}
+		rel.maxHops = rel.minHops
+	}
+	if p.acceptSymbol("..") {
+		rel.maxHops = -1
+		if p.peek().kind == tokenInt {
+			if rel.maxHops, err = p.integer(); err != nil {
+				return err
+			}
+		}
+	}
+	if rel.maxHops >= 0 && rel.minHops > rel.maxHops {
+		return semanticError("Max hop must be greater equal than min hop: %d vs. %d", rel.maxHops, rel.minHops)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------- 执行
+
+func (stmt *matchStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	rows := []*scope{newScope(nil)}
+	for _, clause := range stmt.clauses {
+		m := &matcher{s: s, anchors: idAnchors(clause.where)}
+		for _, pattern := range clause.patterns {
+			var next []*scope
+			for _, row := range rows {
+				err := m.match(pattern, row, func(sc *scope) error {
+					next = append(next, sc)
+					return nil
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+			rows = next
+		}
+		var filtered []*scope
+		for _, row := range rows {
+			ok, err := evalBool(clause.where, row)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				filtered = append(filtered, row)
+			}
+		}
+		rows = filtered
+	}
+
+	res, scopes, err := projectRows(stmt.items, stmt.distinct, rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(stmt.orderBy) > 0 {
+		if err := sortResult(res, scopes, stmt.orderBy); err != nil {
+			return nil, err
+		}
+	}
+	sliceResult(res, stmt.skip, stmt.limit)
+	return res, nil
+}
+
+// 从过滤条件中提取 id(v) == x、id(v) in [...] 形式的条件, 作为匹配的起点, 避免遍历全部的点
+func idAnchors(where expr) map[string][]interface{} {
+	anchors := make(map[string][]interface{})
+	var visit func(e expr)
+	visit = func(e expr) {
+		bin, ok := e.(*binaryExpr)
+		if !ok {
+			return
+		}
+		switch bin.op {
+		case "and":
+			visit(bin.left)
+			visit(bin.right)
+		case "==", "in":
+			variable, value := idCondition(bin.left, bin.right)
+			if variable == "" && bin.op == "==" {
+				variable, value = idCondition(bin.right, bin.left)
+			}
+			if variable == "" {
+				return
+			}
+			vids := []interface{}{value}
+			if bin.op == "in" {
+				list, ok := value.([]interface{})
+				if !ok {
+					return
+				}
+				vids = list
+			}
+			anchors[variable] = vids
+		}
+	}
+	visit(where)
+	return anchors
+}
+
+// 判断是否为 id(v) 与常量的比较, 返回变量名与常量值
+func idCondition(left, right expr) (string, interface{}) {
+	call, ok := left.(*callExpr)
+	if !ok || call.name != "id" || len(call.args) != 1 {
+		return "", nil
+	}
+	variable, ok := call.args[0].(*varExpr)
+	if !ok {
+		return "", nil
+	}
+	// 只使用不依赖变量的常量表达式, 例如 'a'、hash('a')、['a', 'b']
+	value, err := right.eval(newScope(nil))
+	if err != nil {
+		return "", nil
+	}
+	return variable.name, value
+}
+
+// 模式匹配器
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type matcher struct {
+	s       *space
+	anchors map[string][]interface{}
+	adj     *adjacency
+}
+
+// 匹配一个路径模式, 每找到一个匹配就以新的作用域回调
+func (m *matcher) match(pattern pathPattern, row *scope, emit func(*scope) error) error {
+	if m.adj == nil {
+		m.adj = m.s.adjacency(nil)
+	}
+	for _, rel := range pattern.rels {
+		if _, err := m.s.edgeNames(rel.types); err != nil {
+			return err
+		}
+	}
+
+	// 右端的点有起点条件而左端没有时, 反转模式从右向左匹配
+	nodes, rels := pattern.nodes, pattern.rels
+	reversed := !m.hasAnchor(nodes[0], row) && m.hasAnchor(nodes[len(nodes)-1], row)
+	if reversed {
+		nodes, rels = reversePattern(nodes, rels)
+	}
+
+	w := &walk{m: m, nodes: nodes, rels: rels, row: row, used: make(map[string]bool), emit: func(w *walk) error {
+		vars := make(map[string]interface{}, len(nodes)+len(rels)+1)
+		pathNodes := append([]*vertex(nil), w.pathNodes...)
+		var pathEdges []*edge
+		for i, rel := range rels {
+			if rel.variable == "" {
+				pathEdges = append(pathEdges, w.relEdges[i]...)
+				continue
+			}
+			if rel.varLength {
+				list := make([]interface{}, len(w.relEdges[i]))
+				for j, e := range w.relEdges[i] {
+					list[j] = e
+				}
+				if reversed {
+					reverseValues(list)
+				}
+				vars[rel.variable] = list
+			} else {
+				vars[rel.variable] = w.relEdges[i][0]
+			}
+			pathEdges = append(pathEdges, w.relEdges[i]...)
+		}
+		for i, node := range nodes {
+			if node.variable != "" {
+				vars[node.variable] = w.boundNodes[i]
+			}
+		}
+		if pattern.variable != "" {
+			if reversed {
+				reverseVertices(pathNodes)
+				reverseEdges(pathEdges)
+			}
+			vars[pattern.variable] = &path{nodes: pathNodes, edges: pathEdges}
+		}
+		sc := row
+		for name, value := range vars {
+			sc = sc.with(name, value)
+		}
+		return emit(sc)
+	}}
+
+	for _, v := range m.candidates(nodes[0], row) {
+		ok, err := w.nodeMatches(0, v)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		w.pathNodes = []*vertex{v}
+		w.boundNodes = []*vertex{v}
+		w.relEdges = nil
+		if err := w.step(0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 点模式是否有确定的起点(已绑定的变量或 id 条件)
+func (m *matcher) hasAnchor(node nodePattern, row *scope) bool {
+	if node.variable == "" {
+		return false
+	}
+	if _, ok := row.vars[node.variable]; ok {
+		return true
+	}
+	_, ok := m.anchors[node.variable]
+	return ok
+}
+
+// 起点的候选点
+func (m *matcher) candidates(node nodePattern, row *scope) []*vertex {
+	if node.variable != "" {
+		if bound, ok := row.vars[node.variable]; ok {
+			if v, ok := bound.(*vertex); ok {
+				return []*vertex{v}
+			}
+			return nil
+		}
+		if vids, ok := m.anchors[node.variable]; ok {
+			var vertices []*vertex
+			for _, vid := range vids {
+				if v := m.s.vertex(vid); v != nil {
+					vertices = append(vertices, v)
+				}
+			}
+			return vertices
+		}
+	}
+	return m.s.allVertices()
+}
+
+// 反转路径模式
+func reversePattern(nodes []nodePattern, rels []relPattern) ([]nodePattern, []relPattern) {
+	reversedNodes := make([]nodePattern, len(nodes))
+	for i, node := range nodes {
+		reversedNodes[len(nodes)-1-i] = node
+	}
+	reversedRels := make([]relPattern, len(rels))
+	for i, rel := range rels {
+		switch rel.direction {
+		case directionOut:
+			rel.direction = directionIn
+		case directionIn:
+			rel.direction = directionOut
+		}
+		reversedRels[len(rels)-1-i] = rel
+	}
+	return reversedNodes, reversedRels
+}
+
+func reverseValues(values []interface{}) {
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+}
+
+func reverseVertices(values []*vertex) {
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+}
+
+func reverseEdges(values []*edge) {
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+}
+
+// 一次深度优先的匹配过程, 同一路径中的边不重复(trail语义)
+type walk struct {
+	m          *matcher
+	nodes      []nodePattern
+	rels       []relPattern
+	row        *scope
+	used       map[string]bool
+	pathNodes  []*vertex // 路径上依次经过的点
+	boundNodes []*vertex // 每个点模式绑定的点
+	relEdges   [][]*edge // 每个边模式经过的边
+	emit       func(w *walk) error
+}
+
+// 匹配第 i 个边模式
+func (w *walk) step(i int) error {
+	if i == len(w.rels) {
+		return w.emit(w)
+	}
+	w.relEdges = append(w.relEdges, nil)
+	err := w.expand(i, w.boundNodes[i], 0)
+	w.relEdges = w.relEdges[:i]
+	return err
+}
+
+// 沿第 i 个边模式从点 v 继续扩展, hops 为已经过的跳数
+func (w *walk) expand(i int, v *vertex, hops int64) error {
+	rel := w.rels[i]
+	if hops >= rel.minHops {
+		ok, err := w.nodeMatches(i+1, v)
+		if err != nil {
+			return err
+		}
+		if ok {
+			w.boundNodes = append(w.boundNodes, v)
+			err := w.step(i + 1)
+			w.boundNodes = w.boundNodes[:i+1]
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if rel.maxHops >= 0 && hops >= rel.maxHops {
+		return nil
+	}
+
+	for _, h := range w.m.adj.hops(v.vid, rel.direction) {
+		key := edgeKey(h.edge.name, h.edge.src, h.edge.dst, h.edge.rank)
+		if w.used[key] || !containsName(rel.types, h.edge.name) {
+			continue
+		}
+		ok, err := propsMatch(rel.props, h.edge.props, w.row)
+		if err != nil {
+			return err
+		}
+		// 忽略悬挂边
+		next := w.m.s.vertex(h.neighbor)
+		if !ok || next == nil {
+			continue
+		}
+		w.used[key] = true
+		w.relEdges[i] = append(w.relEdges[i], h.edge)
+		w.pathNodes = append(w.pathNodes, next)
+		err = w.expand(i, next, hops+1)
+		w.pathNodes = w.pathNodes[:len(w.pathNodes)-1]
+		w.relEdges[i] = w.relEdges[i][:len(w.relEdges[i])-1]
+		delete(w.used, key)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 第 i 个点模式是否匹配点 v
+func (w *walk) nodeMatches(i int, v *vertex) (bool, error) {
+	node := w.nodes[i]
+	for _, label := range node.labels {
+		if _, ok := v.tags[label]; !ok {
+			return false, nil
+		}
+	}
+	if node.variable != "" {
+		// 同名变量必须绑定同一个点
+		if bound, ok := w.row.vars[node.variable]; ok && !equalValues(bound, v) {
+			return false, nil
+		}
+		for j, other := range w.nodes[:i] {
+			if other.variable == node.variable && j < len(w.boundNodes) && !equalValues(w.boundNodes[j], v) {
+				return false, nil
+			}
+		}
+		if vids, ok := w.m.anchors[node.variable]; ok && !containsValue(vids, v.vid) {
+			return false, nil
+		}
+	}
+	return propsMatch(node.props, v.allProps(), w.row)
+}
+
+// 属性是否匹配模式中的属性条件
+func propsMatch(props *mapExpr, actual map[string]interface{}, sc *scope) (bool, error) {
+	if props == nil {
+		return true, nil
+	}
+	for i, key := range props.keys {
+		value, err := props.items[i].eval(sc)
+		if err != nil {
+			return false, err
+		}
+		if valueEqual(actual[key], value) != true {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// 名称是否在列表中, 列表为空表示全部
+func containsName(names []string, name string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
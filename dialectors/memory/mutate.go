@@ -0,0 +1,622 @@
+package memory
+
+import nebula_type "github.com/vesoft-inc/nebula-go/v3/nebula"
+
+// ---------------------------------------------------------------- 插入
+
+// insert vertex [if not exists] tag(props), ... values vid:(values), ...
+type insertVertexStmt struct {
+	ifNotExists bool
+	tags        []string
+	props       [][]string // 每个标签的属性名
+	vids        []expr
+	values      [][]expr // 每个点的属性值, 按标签顺序依次排列
+}
+
+func (p *parser) parseInsertVertex() (statement, error) {
+	stmt := &insertVertexStmt{}
+	stmt.ifNotExists = p.parseIfNotExists()
+	for {
+		tag, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		props, err := p.parsePropNames()
+		if err != nil {
+			return nil, err
+		}
+		stmt.tags = append(stmt.tags, tag)
+		stmt.props = append(stmt.props, props)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	if err := p.expect("values"); err != nil {
+		return nil, err
+	}
+	for {
+		vid, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(":"); err != nil {
+			return nil, err
+		}
+		values, err := p.parseValues()
+		if err != nil {
+			return nil, err
+		}
+		stmt.vids = append(stmt.vids, vid)
+		stmt.values = append(stmt.values, values)
+		if !p.acceptSymbol(",") {
+			return stmt, nil
+		}
+	}
+}
+
+// 解析属性名列表 (a, b, c)
+func (p *parser) parsePropNames() ([]string, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	var names []string
+	if !p.peek().isSymbol(")") {
+		var err error
+		if names, err = p.identList(); err != nil {
+			return nil, err
+		}
+	}
+	return names, p.expectSymbol(")")
+}
+
+// 解析属性值列表 (x, y, z)
+func (p *parser) parseValues() ([]expr, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	var values []expr
+	for !p.peek().isSymbol(")") {
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return values, p.expectSymbol(")")
+}
+
+// 对表达式列表求值
+func evalAll(exprs []expr, sc *scope) ([]interface{}, error) {
+	values := make([]interface{}, len(exprs))
+	for i, e := range exprs {
+		value, err := e.eval(sc)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func (stmt *insertVertexStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	total := 0
+	for _, props := range stmt.props {
+		total += len(props)
+	}
+
+	// 先完成全部校验, 再统一写入, 避免部分写入
+	type write struct {
+		vid   interface{}
+		tag   string
+		props map[string]interface{}
+	}
+	var writes []write
+	for i, vidExpr := range stmt.vids {
+		vid, err := vidExpr.eval(newScope(nil))
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkVid(vid); err != nil {
+			return nil, err
+		}
+		values, err := evalAll(stmt.values[i], newScope(nil))
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != total {
+			return nil, semanticError("Column count doesn't match value count.")
+		}
+		offset := 0
+		for j, tag := range stmt.tags {
+			names := stmt.props[j]
+			tagValues := values[offset : offset+len(names)]
+			offset += len(names)
+			sc, err := d.writeSchema(s, kindTag, tag, names, tagValues)
+			if err != nil {
+				return nil, err
+			}
+			props, err := buildProps(sc, names, tagValues)
+			if err != nil {
+				return nil, err
+			}
+			writes = append(writes, write{vid: vid, tag: tag, props: props})
+		}
+	}
+	for _, w := range writes {
+		if stmt.ifNotExists {
+			if v := s.vertex(w.vid); v != nil && v.tags[w.tag] != nil {
+				continue
+			}
+		}
+		s.putVertex(w.vid, w.tag, w.props)
+	}
+	return nil, nil
+}
+
+// insert edge [if not exists] name(props) values src->dst[@rank]:(values), ...
+type insertEdgeStmt struct {
+	ifNotExists bool
+	name        string
+	props       []string
+	keys        []edgeKeyExpr
+	values      [][]expr
+}
+
+// 边的键: src->dst@rank
+type edgeKeyExpr struct {
+	src, dst expr
+	rank     int64
+}
+
+func (p *parser) parseInsertEdge() (statement, error) {
+	stmt := &insertEdgeStmt{}
+	stmt.ifNotExists = p.parseIfNotExists()
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	stmt.name = name
+	if stmt.props, err = p.parsePropNames(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("values"); err != nil {
+		return nil, err
+	}
+	for {
+		key, err := p.parseEdgeKey()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(":"); err != nil {
+			return nil, err
+		}
+		values, err := p.parseValues()
+		if err != nil {
+			return nil, err
+		}
+		stmt.keys = append(stmt.keys, key)
+		stmt.values = append(stmt.values, values)
+		if !p.acceptSymbol(",") {
+			return stmt, nil
+		}
+	}
+}
+
+// 解析边的键 src -> dst [@rank]
+func (p *parser) parseEdgeKey() (edgeKeyExpr, error) {
+	src, err := p.parseAdditive()
+	if err != nil {
+		return edgeKeyExpr{}, err
+	}
+	return p.parseEdgeKeyFrom(src)
+}
+
+// 已解析起点时继续解析 -> dst [@rank]
+func (p *parser) parseEdgeKeyFrom(src expr) (edgeKeyExpr, error) {
+	key := edgeKeyExpr{src: src}
+	var err error
+	if err = p.expectSymbol("->"); err != nil {
+		return key, err
+	}
+	if key.dst, err = p.parseAdditive(); err != nil {
+		return key, err
+	}
+	if p.acceptSymbol("@") {
+		negative := p.acceptSymbol("-")
+		if key.rank, err = p.integer(); err != nil {
+			return key, err
+		}
+		if negative {
+			key.rank = -key.rank
+		}
+	}
+	return key, nil
+}
+
+// 对边的键求值并校验VID
+func (key edgeKeyExpr) eval(s *space) (interface{}, interface{}, error) {
+	src, err := key.src.eval(newScope(nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	dst, err := key.dst.eval(newScope(nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkVid(src); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkVid(dst); err != nil {
+		return nil, nil, err
+	}
+	return src, dst, nil
+}
+
+func (stmt *insertEdgeStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	var edges []*edge
+	for i, key := range stmt.keys {
+		src, dst, err := key.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		values, err := evalAll(stmt.values[i], newScope(nil))
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != len(stmt.props) {
+			return nil, semanticError("Column count doesn't match value count.")
+		}
+		sc, err := d.writeSchema(s, kindEdge, stmt.name, stmt.props, values)
+		if err != nil {
+			return nil, err
+		}
+		props, err := buildProps(sc, stmt.props, values)
+		if err != nil {
+			return nil, err
+		}
+		edges = append(edges, &edge{name: stmt.name, src: src, dst: dst, rank: key.rank, props: props})
+	}
+	for _, e := range edges {
+		if stmt.ifNotExists && s.edge(e.name, e.src, e.dst, e.rank) != nil {
+			continue
+		}
+		s.putEdge(e)
+	}
+	return nil, nil
+}
+
+// ---------------------------------------------------------------- 删除
+
+// delete vertex vid, ... [with edge]
+type deleteVertexStmt struct {
+	vids     []expr
+	withEdge bool
+}
+
+func (p *parser) parseDeleteVertex() (statement, error) {
+	stmt := &deleteVertexStmt{}
+	var err error
+	if stmt.vids, err = p.parseVidList(); err != nil {
+		return nil, err
+	}
+	if p.accept("with") {
+		if err := p.expect("edge"); err != nil {
+			return nil, err
+		}
+		stmt.withEdge = true
+	}
+	return stmt, nil
+}
+
+func (stmt *deleteVertexStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	vids, err := evalAll(stmt.vids, newScope(nil))
+	if err != nil {
+		return nil, err
+	}
+	for _, vid := range vids {
+		if err := s.checkVid(vid); err != nil {
+			return nil, err
+		}
+	}
+	for _, vid := range vids {
+		s.deleteVertex(vid)
+		if stmt.withEdge {
+			s.deleteEdgesOf(vid)
+		}
+	}
+	return nil, nil
+}
+
+// delete tag tag, ...|* from vid, ...
+type deleteTagStmt struct {
+	tags []string // 为空表示全部标签
+	vids []expr
+}
+
+func (p *parser) parseDeleteTag() (statement, error) {
+	stmt := &deleteTagStmt{}
+	if !p.acceptSymbol("*") {
+		var err error
+		if stmt.tags, err = p.identList(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect("from"); err != nil {
+		return nil, err
+	}
+	var err error
+	stmt.vids, err = p.parseVidList()
+	return stmt, err
+}
+
+func (stmt *deleteTagStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range stmt.tags {
+		if _, err := s.schema(kindTag, tag); err != nil {
+			return nil, err
+		}
+	}
+	vids, err := evalAll(stmt.vids, newScope(nil))
+	if err != nil {
+		return nil, err
+	}
+	for _, vid := range vids {
+		v := s.vertex(vid)
+		if v == nil {
+			continue
+		}
+		if len(stmt.tags) == 0 {
+			v.tags = make(map[string]map[string]interface{})
+			continue
+		}
+		for _, tag := range stmt.tags {
+			delete(v.tags, tag)
+		}
+	}
+	return nil, nil
+}
+
+// delete edge name src->dst[@rank], ...
+type deleteEdgeStmt struct {
+	name string
+	keys []edgeKeyExpr
+}
+
+func (p *parser) parseDeleteEdge() (statement, error) {
+	stmt := &deleteEdgeStmt{}
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	stmt.name = name
+	for {
+		key, err := p.parseEdgeKey()
+		if err != nil {
+			return nil, err
+		}
+		stmt.keys = append(stmt.keys, key)
+		if !p.acceptSymbol(",") {
+			return stmt, nil
+		}
+	}
+}
+
+func (stmt *deleteEdgeStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.schema(kindEdge, stmt.name); err != nil {
+		return nil, err
+	}
+	for _, key := range stmt.keys {
+		src, dst, err := key.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		s.deleteEdge(stmt.name, src, dst, key.rank)
+	}
+	return nil, nil
+}
+
+// ---------------------------------------------------------------- 更新
+
+// 赋值 prop = expr
+type assignment struct {
+	prop  string
+	value expr
+}
+
+// update|upsert vertex on tag vid set ... [when ...] [yield ...]
+// update|upsert edge on name src->dst[@rank] set ... [when ...] [yield ...]
+type updateStmt struct {
+	upsert bool
+	kind   string // tag 或 edge
+	name   string
+	vid    expr
+	key    edgeKeyExpr
+	sets   []assignment
+	when   expr
+	yields []yieldItem
+}
+
+func (p *parser) parseUpdate(upsert bool, kind string) (statement, error) {
+	stmt := &updateStmt{upsert: upsert, kind: kind}
+	if err := p.expect("on"); err != nil {
+		return nil, err
+	}
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	stmt.name = name
+	if kind == kindEdge {
+		if stmt.key, err = p.parseEdgeKey(); err != nil {
+			return nil, err
+		}
+	} else if stmt.vid, err = p.parseAdditive(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("set"); err != nil {
+		return nil, err
+	}
+	for {
+		prop, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol("="); err != nil {
+			return nil, err
+		}
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		stmt.sets = append(stmt.sets, assignment{prop: prop, value: value})
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	if p.accept("when") {
+		if stmt.when, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("yield") {
+		if stmt.yields, _, err = p.parseYieldItems(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// 更新语句的作用域: 属性名可直接引用, 也可以通过 标签名.属性名、边类型名.属性名、$^.标签名.属性名 引用
+func (stmt *updateStmt) scope(props map[string]interface{}, target interface{}) *scope {
+	vars := copyProps(props)
+	vars["$^"] = target
+	if stmt.kind == kindEdge {
+		vars[stmt.name] = target
+	} else {
+		vars[stmt.name] = props
+	}
+	return newScope(vars)
+}
+
+func (stmt *updateStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(stmt.sets))
+	for i, set := range stmt.sets {
+		names[i] = set.prop
+	}
+	sc, err := d.writeSchema(s, stmt.kind, stmt.name, names, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// 查找要更新的点或边, upsert 时不存在则按默认值新建
+	var vid, src, dst interface{}
+	var props map[string]interface{}
+	var target interface{}
+	exists := true
+	if stmt.kind == kindEdge {
+		if src, dst, err = stmt.key.eval(s); err != nil {
+			return nil, err
+		}
+		if e := s.edge(stmt.name, src, dst, stmt.key.rank); e != nil {
+			props = copyProps(e.props)
+		} else {
+			exists = false
+		}
+	} else {
+		if vid, err = stmt.vid.eval(newScope(nil)); err != nil {
+			return nil, err
+		}
+		if err := s.checkVid(vid); err != nil {
+			return nil, err
+		}
+		if v := s.vertex(vid); v != nil && v.tags[stmt.name] != nil {
+			props = copyProps(v.tags[stmt.name])
+		} else {
+			exists = false
+		}
+	}
+	if !exists {
+		if !stmt.upsert {
+			return nil, storageError(nebula_type.ErrorCode_E_KEY_NOT_FOUND, "Vertex or edge not found.")
+		}
+		props = make(map[string]interface{}, len(sc.props))
+		for _, prop := range sc.props {
+			if prop.defaultExpr != nil || prop.nullable {
+				if props[prop.name], err = defaultValue(prop); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	target = stmt.target(vid, src, dst, props)
+
+	// when 条件不满足时不做修改, 返回当前的值(upsert 新建时不检查条件)
+	apply := true
+	if exists && stmt.when != nil {
+		if apply, err = evalBool(stmt.when, stmt.scope(props, target)); err != nil {
+			return nil, err
+		}
+	}
+	if apply {
+		// set 按顺序求值, 后面的表达式可以使用前面更新后的值
+		for _, set := range stmt.sets {
+			value, err := set.value.eval(stmt.scope(props, target))
+			if err != nil {
+				return nil, err
+			}
+			if props[set.prop], err = checkValue(sc.prop(set.prop), value); err != nil {
+				return nil, err
+			}
+			target = stmt.target(vid, src, dst, props)
+		}
+		for _, prop := range sc.props {
+			if _, ok := props[prop.name]; !ok {
+				return nil, storageError(nebula_type.ErrorCode_E_FIELD_UNSET, "The not null field doesn't have a default value.")
+			}
+		}
+		if stmt.kind == kindEdge {
+			s.putEdge(&edge{name: stmt.name, src: src, dst: dst, rank: stmt.key.rank, props: props})
+		} else {
+			s.putVertex(vid, stmt.name, props)
+		}
+	}
+
+	if len(stmt.yields) == 0 {
+		return nil, nil
+	}
+	return project(stmt.yields, false, []*scope{stmt.scope(props, target)})
+}
+
+// 构造更新目标的点或边, 用于 yield 中的 $^、标签名.属性名
+func (stmt *updateStmt) target(vid, src, dst interface{}, props map[string]interface{}) interface{} {
+	if stmt.kind == kindEdge {
+		return &edge{name: stmt.name, src: src, dst: dst, rank: stmt.key.rank, props: props}
+	}
+	return &vertex{vid: vid, tags: map[string]map[string]interface{}{stmt.name: props}}
+}
@@ -0,0 +1,353 @@
+package memory
+
+import (
+	"strconv"
+	"strings"
+)
+
+// 语法分析器, 将nGQL的词法单元解析为语句
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type parser struct {
+	src    []rune
+	tokens []token
+	pos    int
+}
+
+// 创建语法分析器
+func newParser(ngql string) (*parser, error) {
+	tokens, err := tokenize(ngql)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{src: []rune(ngql), tokens: tokens}, nil
+}
+
+// 查看当前词法单元
+func (p *parser) peek() token {
+	return p.peekAt(0)
+}
+
+// 查看之后第n个词法单元
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+// 读取当前词法单元
+func (p *parser) next() token {
+	tok := p.peek()
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+	return tok
+}
+
+// 上一个已读取的词法单元
+func (p *parser) prev() token {
+	if p.pos == 0 {
+		return p.tokens[0]
+	}
+	return p.tokens[p.pos-1]
+}
+
+// 当前为指定关键字时读取并返回true
+func (p *parser) accept(keyword string) bool {
+	if p.peek().is(keyword) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// 读取指定关键字, 不匹配时返回语法错误
+func (p *parser) expect(keyword string) error {
+	if !p.accept(keyword) {
+		return p.syntaxError(p.peek())
+	}
+	return nil
+}
+
+// 当前为指定符号时读取并返回true
+func (p *parser) acceptSymbol(symbol string) bool {
+	if p.peek().isSymbol(symbol) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// 读取指定符号, 不匹配时返回语法错误
+func (p *parser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.syntaxError(p.peek())
+	}
+	return nil
+}
+
+// 读取标识符
+func (p *parser) ident() (string, error) {
+	tok := p.next()
+	if tok.kind != tokenIdent {
+		return "", p.syntaxError(tok)
+	}
+	return tok.text, nil
+}
+
+// 读取以逗号分隔的标识符列表
+func (p *parser) identList() ([]string, error) {
+	var names []string
+	for {
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.acceptSymbol(",") {
+			return names, nil
+		}
+	}
+}
+
+// 读取非负整数
+func (p *parser) integer() (int64, error) {
+	tok := p.next()
+	if tok.kind != tokenInt {
+		return 0, p.syntaxError(tok)
+	}
+	value, err := strconv.ParseInt(tok.text, 10, 64)
+	if err != nil {
+		return 0, p.syntaxError(tok)
+	}
+	return value, nil
+}
+
+// 当前语句是否已结束
+func (p *parser) atEnd() bool {
+	tok := p.peek()
+	return tok.kind == tokenEOF || tok.isSymbol(";") || tok.isSymbol("|")
+}
+
+// 生成语法错误, 格式与graphd一致: syntax error near `xxx'
+func (p *parser) syntaxError(tok token) error {
+	if tok.kind == tokenEOF {
+		return syntaxError("syntax error near end of statement")
+	}
+	return syntaxError("syntax error near `%s'", p.text(tok.pos, tok.end))
+}
+
+// 截取原始语句文本
+func (p *parser) text(start, end int) string {
+	return strings.TrimSpace(string(p.src[start:end]))
+}
+
+// 解析一个带原始文本的表达式, 原始文本用作默认列名
+func (p *parser) parseNamedExpr() (expr, string, error) {
+	start := p.peek().pos
+	e, err := p.parseExpr()
+	if err != nil {
+		return nil, "", err
+	}
+	return e, p.text(start, p.prev().end), nil
+}
+
+// 解析以分号分隔的多条语句
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func parseStatements(ngql string) ([]statement, error) {
+	p, err := newParser(ngql)
+	if err != nil {
+		return nil, err
+	}
+	var statements []statement
+	for {
+		for p.acceptSymbol(";") {
+		}
+		if p.peek().kind == tokenEOF {
+			return statements, nil
+		}
+		stmt, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		for p.acceptSymbol("|") {
+			if stmt, err = p.parsePipe(stmt); err != nil {
+				return nil, err
+			}
+		}
+		if !p.peek().isSymbol(";") && p.peek().kind != tokenEOF {
+			return nil, p.syntaxError(p.peek())
+		}
+		statements = append(statements, stmt)
+	}
+}
+
+// 解析一条语句
+func (p *parser) parseStatement() (statement, error) {
+	tok := p.next()
+	switch {
+	case tok.is("explain") || tok.is("profile"):
+		return nil, semanticError("内存图引擎不支持 %s", strings.ToLower(tok.text))
+	case tok.is("use"):
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		return &useStmt{name: name}, nil
+	case tok.is("create"):
+		return p.parseCreate()
+	case tok.is("drop"):
+		return p.parseDrop()
+	case tok.is("alter"):
+		switch {
+		case p.accept("tag"):
+			return p.parseAlterSchema(kindTag)
+		case p.accept("edge"):
+			return p.parseAlterSchema(kindEdge)
+		}
+	case tok.is("show"):
+		return p.parseShow()
+	case tok.is("describe") || tok.is("desc"):
+		return p.parseDescribe()
+	case tok.is("rebuild"):
+		for !p.atEnd() {
+			p.next()
+		}
+		return &rebuildIndexStmt{}, nil
+	case tok.is("insert"):
+		switch {
+		case p.accept("vertex"):
+			return p.parseInsertVertex()
+		case p.accept("edge"):
+			return p.parseInsertEdge()
+		}
+	case tok.is("delete"):
+		switch {
+		case p.accept("vertex"):
+			return p.parseDeleteVertex()
+		case p.accept("edge"):
+			return p.parseDeleteEdge()
+		case p.accept("tag"):
+			return p.parseDeleteTag()
+		}
+	case tok.is("update") || tok.is("upsert"):
+		upsert := tok.is("upsert")
+		switch {
+		case p.accept("vertex"):
+			return p.parseUpdate(upsert, kindTag)
+		case p.accept("edge"):
+			return p.parseUpdate(upsert, kindEdge)
+		}
+	case tok.is("fetch"):
+		return p.parseFetch()
+	case tok.is("go"):
+		return p.parseGo()
+	case tok.is("lookup"):
+		return p.parseLookup()
+	case tok.is("match"):
+		return p.parseMatch()
+	case tok.is("get"):
+		if err := p.expect("subgraph"); err != nil {
+			return nil, err
+		}
+		return p.parseSubgraph()
+	case tok.is("yield"):
+		return p.parseYield()
+	default:
+		if tok.kind == tokenIdent {
+			return nil, semanticError("内存图引擎不支持的语句 `%s'", tok.text)
+		}
+	}
+	return nil, p.syntaxError(p.peek())
+}
+
+// create space|tag|edge|tag index|edge index
+func (p *parser) parseCreate() (statement, error) {
+	switch {
+	case p.accept("space"):
+		return p.parseCreateSpace()
+	case p.peek().is("tag") && p.peekAt(1).is("index"):
+		p.pos += 2
+		return p.parseCreateIndex(kindTagIndex)
+	case p.peek().is("edge") && p.peekAt(1).is("index"):
+		p.pos += 2
+		return p.parseCreateIndex(kindEdgeIndex)
+	case p.accept("tag"):
+		return p.parseCreateSchema(kindTag)
+	case p.accept("edge"):
+		return p.parseCreateSchema(kindEdge)
+	}
+	return nil, p.syntaxError(p.peek())
+}
+
+// drop space|tag|edge|tag index|edge index [if exists] name
+func (p *parser) parseDrop() (statement, error) {
+	stmt := &dropStmt{}
+	switch {
+	case p.accept("space"):
+		stmt.kind = "space"
+	case p.peek().is("tag") && p.peekAt(1).is("index"):
+		p.pos += 2
+		stmt.kind = kindTagIndex
+	case p.peek().is("edge") && p.peekAt(1).is("index"):
+		p.pos += 2
+		stmt.kind = kindEdgeIndex
+	case p.accept("tag"):
+		stmt.kind = kindTag
+	case p.accept("edge"):
+		stmt.kind = kindEdge
+	default:
+		return nil, p.syntaxError(p.peek())
+	}
+	stmt.ifExists = p.parseIfExists()
+	var err error
+	stmt.name, err = p.ident()
+	return stmt, err
+}
+
+// show spaces|tags|edges|tag indexes|edge indexes
+func (p *parser) parseShow() (statement, error) {
+	switch {
+	case p.accept("spaces"):
+		return &showStmt{kind: "space"}, nil
+	case p.accept("tags"):
+		return &showStmt{kind: kindTag}, nil
+	case p.accept("edges"):
+		return &showStmt{kind: kindEdge}, nil
+	case p.peek().is("tag") && p.peekAt(1).is("indexes"):
+		p.pos += 2
+		return &showStmt{kind: kindTagIndex}, nil
+	case p.peek().is("edge") && p.peekAt(1).is("indexes"):
+		p.pos += 2
+		return &showStmt{kind: kindEdgeIndex}, nil
+	}
+	return nil, p.syntaxError(p.peek())
+}
+
+// describe space|tag|edge|tag index|edge index name
+func (p *parser) parseDescribe() (statement, error) {
+	stmt := &describeStmt{}
+	switch {
+	case p.accept("space"):
+		stmt.kind = "space"
+	case p.peek().is("tag") && p.peekAt(1).is("index"):
+		p.pos += 2
+		stmt.kind = kindTagIndex
+	case p.peek().is("edge") && p.peekAt(1).is("index"):
+		p.pos += 2
+		stmt.kind = kindEdgeIndex
+	case p.accept("tag"):
+		stmt.kind = kindTag
+	case p.accept("edge"):
+		stmt.kind = kindEdge
+	default:
+		return nil, p.syntaxError(p.peek())
+	}
+	var err error
+	stmt.name, err = p.ident()
+	return stmt, err
+}
@@ -0,0 +1,1083 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------------- 投影
+
+// yield/return 的一列: 表达式 [as 别名]
+type yieldItem struct {
+	e     expr
+	alias string
+	text  string // 表达式原文, 没有别名时作为列名
+}
+
+// 列名
+func (item yieldItem) column() string {
+	if item.alias != "" {
+		return item.alias
+	}
+	return item.text
+}
+
+// 解析 [distinct] expr [as alias], ...
+func (p *parser) parseYieldItems() ([]yieldItem, bool, error) {
+	distinct := p.accept("distinct")
+	var items []yieldItem
+	for {
+		e, text, err := p.parseNamedExpr()
+		if err != nil {
+			return nil, false, err
+		}
+		item := yieldItem{e: e, text: text}
+		if p.accept("as") {
+			if item.alias, err = p.ident(); err != nil {
+				return nil, false, err
+			}
+		}
+		items = append(items, item)
+		if !p.acceptSymbol(",") {
+			return items, distinct, nil
+		}
+	}
+}
+
+// 对每一行求值投影列, 包含聚合函数时按非聚合列分组
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func project(items []yieldItem, distinct bool, scopes []*scope) (*result, error) {
+	res, _, err := projectRows(items, distinct, scopes)
+	return res, err
+}
+
+// 对每一行求值投影列, 同时返回每个输出行的作用域(原作用域 + 以列名命名的变量), 用于 order by
+func projectRows(items []yieldItem, distinct bool, scopes []*scope) (*result, []*scope, error) {
+	res := &result{columns: make([]string, len(items))}
+	for i, item := range items {
+		res.columns[i] = item.column()
+	}
+
+	var aggs []*callExpr
+	for _, item := range items {
+		aggs = append(aggs, aggregatesOf(item.e)...)
+	}
+
+	var rowScopes []*scope
+	emit := func(sc *scope) error {
+		row := make([]interface{}, len(items))
+		for i, item := range items {
+			value, err := item.e.eval(sc)
+			if err != nil {
+				return err
+			}
+			row[i] = value
+		}
+		for i, column := range res.columns {
+			sc = sc.with(column, row[i])
+		}
+		if distinct {
+			for _, other := range res.rows {
+				if equalValues(row, other) {
+					return nil
+				}
+			}
+		}
+		res.rows = append(res.rows, row)
+		rowScopes = append(rowScopes, sc)
+		return nil
+	}
+
+	if len(aggs) == 0 {
+		for _, sc := range scopes {
+			if err := emit(sc); err != nil {
+				return nil, nil, err
+			}
+		}
+		return res, rowScopes, nil
+	}
+
+	// 按非聚合列的值分组, 分组保持首次出现的顺序
+	type group struct {
+		keys []interface{}
+		rows []*scope
+	}
+	var groups []*group
+	for _, sc := range scopes {
+		var keys []interface{}
+		for _, item := range items {
+			if len(aggregatesOf(item.e)) > 0 {
+				continue
+			}
+			value, err := item.e.eval(sc)
+			if err != nil {
+				return nil, nil, err
+			}
+			keys = append(keys, value)
+		}
+		var found *group
+		for _, g := range groups {
+			if equalValues(interface{}([]interface{}(g.keys)), interface{}([]interface{}(keys))) {
+				found = g
+				break
+			}
+		}
+		if found == nil {
+			found = &group{keys: keys}
+			groups = append(groups, found)
+		}
+		found.rows = append(found.rows, sc)
+	}
+	// 没有任何输入且只有聚合列时, 输出一行, 例如 count(*) 为 0
+	if len(groups) == 0 && len(aggs) > 0 && allAggregates(items) {
+		groups = append(groups, &group{})
+	}
+
+	for _, g := range groups {
+		sc := newScope(nil)
+		if len(g.rows) > 0 {
+			sc = g.rows[0]
+		}
+		values := make(map[*callExpr]interface{}, len(aggs))
+		for _, call := range aggs {
+			value, err := computeAggregate(call, g.rows)
+			if err != nil {
+				return nil, nil, err
+			}
+			values[call] = value
+		}
+		if err := emit(&scope{vars: sc.vars, aggs: values}); err != nil {
+			return nil, nil, err
+		}
+	}
+	return res, rowScopes, nil
+}
+
+// 是否所有列都包含聚合函数
+func allAggregates(items []yieldItem) bool {
+	for _, item := range items {
+		if len(aggregatesOf(item.e)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// 排序项
+type orderItem struct {
+	e    expr
+	desc bool
+}
+
+// 解析 order by expr [asc|desc], ...
+func (p *parser) parseOrderBy() ([]orderItem, error) {
+	var items []orderItem
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		item := orderItem{e: e}
+		if p.accept("desc") || p.accept("descending") {
+			item.desc = true
+		} else if !p.accept("asc") {
+			p.accept("ascending")
+		}
+		items = append(items, item)
+		if !p.acceptSymbol(",") {
+			return items, nil
+		}
+	}
+}
+
+// 按排序项对结果排序, 排序是稳定的
+func sortResult(res *result, scopes []*scope, items []orderItem) error {
+	keys := make([][]interface{}, len(res.rows))
+	for i, sc := range scopes {
+		for _, item := range items {
+			value, err := item.e.eval(sc)
+			if err != nil {
+				return err
+			}
+			keys[i] = append(keys[i], value)
+		}
+	}
+	index := make([]int, len(res.rows))
+	for i := range index {
+		index[i] = i
+	}
+	sort.SliceStable(index, func(a, b int) bool {
+		for k, item := range items {
+			c := orderValues(keys[index[a]][k], keys[index[b]][k])
+			if c == 0 {
+				continue
+			}
+			if item.desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	rows := make([][]interface{}, len(res.rows))
+	for i, j := range index {
+		rows[i] = res.rows[j]
+	}
+	res.rows = rows
+	return nil
+}
+
+// 截取结果 [offset, offset+count), count 小于0表示不限制
+func sliceResult(res *result, offset, count int64) {
+	if offset > int64(len(res.rows)) {
+		offset = int64(len(res.rows))
+	}
+	res.rows = res.rows[offset:]
+	if count >= 0 && count < int64(len(res.rows)) {
+		res.rows = res.rows[:count]
+	}
+}
+
+// ---------------------------------------------------------------- 读取的辅助方法
+
+// 解析以逗号分隔的表达式
+func (p *parser) parseExprList() ([]expr, error) {
+	return p.parseList(p.parseExpr)
+}
+
+// 解析以逗号分隔的VID, VID中不会出现比较与逻辑运算, 避免把 from 'a' in edge 中的 in 当作运算符
+func (p *parser) parseVidList() ([]expr, error) {
+	return p.parseList(p.parseAdditive)
+}
+
+// 解析以逗号分隔的列表
+func (p *parser) parseList(parse func() (expr, error)) ([]expr, error) {
+	var exprs []expr
+	for {
+		e, err := parse()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+		if !p.acceptSymbol(",") {
+			return exprs, nil
+		}
+	}
+}
+
+// 对VID表达式求值, 校验类型并去重
+func evalVids(s *space, exprs []expr) ([]interface{}, error) {
+	values, err := evalAll(exprs, newScope(nil))
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(values))
+	var vids []interface{}
+	for _, value := range values {
+		// 支持 from ['a', 'b'] 形式的列表
+		items := []interface{}{value}
+		if list, ok := value.([]interface{}); ok {
+			items = list
+		}
+		for _, vid := range items {
+			if err := s.checkVid(vid); err != nil {
+				return nil, err
+			}
+			if key := vidKey(vid); !seen[key] {
+				seen[key] = true
+				vids = append(vids, vid)
+			}
+		}
+	}
+	return vids, nil
+}
+
+// 查找点, 不存在时返回只有VID的点(悬挂边的端点)
+func (s *space) vertexOrEmpty(vid interface{}) *vertex {
+	if v := s.vertex(vid); v != nil {
+		return v
+	}
+	return &vertex{vid: vid, tags: map[string]map[string]interface{}{}}
+}
+
+// 边的邻接表
+type adjacency struct {
+	out map[string][]*edge
+	in  map[string][]*edge
+}
+
+// 构建邻接表, 只包含指定的边类型(为空表示全部)
+func (s *space) adjacency(names []string) *adjacency {
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		allowed[name] = true
+	}
+	adj := &adjacency{out: make(map[string][]*edge), in: make(map[string][]*edge)}
+	for _, e := range s.allEdges() {
+		if len(names) > 0 && !allowed[e.name] {
+			continue
+		}
+		adj.out[vidKey(e.src)] = append(adj.out[vidKey(e.src)], e)
+		adj.in[vidKey(e.dst)] = append(adj.in[vidKey(e.dst)], e)
+	}
+	return adj
+}
+
+// 一次扩展: 经过的边与到达的点
+type hop struct {
+	edge     *edge
+	neighbor interface{}
+}
+
+// 按方向返回点的邻边
+func (adj *adjacency) hops(vid interface{}, direction string) []hop {
+	var hops []hop
+	key := vidKey(vid)
+	if direction != directionIn {
+		for _, e := range adj.out[key] {
+			hops = append(hops, hop{edge: e, neighbor: e.dst})
+		}
+	}
+	if direction != directionOut {
+		for _, e := range adj.in[key] {
+			// 双向遍历时自环只出现一次
+			if direction == directionBoth && vidKey(e.src) == key {
+				continue
+			}
+			hops = append(hops, hop{edge: e, neighbor: e.src})
+		}
+	}
+	return hops
+}
+
+// 遍历方向
+const (
+	directionOut  = "out"
+	directionIn   = "in"
+	directionBoth = "both"
+)
+
+// 校验边类型并返回全部边类型名(names 为空时)
+func (s *space) edgeNames(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return append([]string(nil), s.schemaOrders[kindEdge]...), nil
+	}
+	for _, name := range names {
+		if _, err := s.schema(kindEdge, name); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// ---------------------------------------------------------------- fetch
+
+// fetch prop on tag, ...|* vid, ... [yield ...]
+// fetch prop on edge src->dst[@rank], ... [yield ...]
+type fetchStmt struct {
+	names    []string // 为空表示 *
+	vids     []expr
+	keys     []edgeKeyExpr
+	yields   []yieldItem
+	distinct bool
+}
+
+func (p *parser) parseFetch() (statement, error) {
+	stmt := &fetchStmt{}
+	if err := p.expect("prop"); err != nil {
+		return nil, err
+	}
+	if err := p.expect("on"); err != nil {
+		return nil, err
+	}
+	if !p.acceptSymbol("*") {
+		var err error
+		if stmt.names, err = p.identList(); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		first, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().isSymbol("->") {
+			key, err := p.parseEdgeKeyFrom(first)
+			if err != nil {
+				return nil, err
+			}
+			stmt.keys = append(stmt.keys, key)
+		} else {
+			stmt.vids = append(stmt.vids, first)
+		}
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	if len(stmt.keys) > 0 && len(stmt.vids) > 0 {
+		return nil, syntaxError("点与边不能在同一个fetch语句中查询")
+	}
+	if p.accept("yield") {
+		var err error
+		if stmt.yields, stmt.distinct, err = p.parseYieldItems(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+func (stmt *fetchStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	if len(stmt.keys) > 0 {
+		return stmt.fetchEdges(s)
+	}
+
+	names := stmt.names
+	if len(names) == 0 {
+		names = s.schemaOrders[kindTag]
+	}
+	for _, name := range names {
+		if _, err := s.schema(kindTag, name); err != nil {
+			return nil, err
+		}
+	}
+	vids, err := evalVids(s, stmt.vids)
+	if err != nil {
+		return nil, err
+	}
+	var scopes []*scope
+	for _, vid := range vids {
+		v := s.vertex(vid)
+		if v == nil {
+			continue
+		}
+		// 只返回查询的标签
+		fetched := &vertex{vid: v.vid, tags: make(map[string]map[string]interface{})}
+		vars := map[string]interface{}{}
+		for _, name := range names {
+			vars[name] = nil
+			if props, ok := v.tags[name]; ok {
+				fetched.tags[name] = props
+				vars[name] = props
+			}
+		}
+		if len(fetched.tags) == 0 {
+			continue
+		}
+		vars["vertex"] = fetched
+		scopes = append(scopes, newScope(vars))
+	}
+	items := stmt.yields
+	if len(items) == 0 {
+		items = []yieldItem{{e: &varExpr{name: "vertex"}, alias: "vertices_"}}
+	}
+	return project(items, stmt.distinct, scopes)
+}
+
+// 查询边的属性
+func (stmt *fetchStmt) fetchEdges(s *space) (*result, error) {
+	if len(stmt.names) != 1 {
+		return nil, semanticError("Only one edge type is allowed in fetch edge")
+	}
+	name := stmt.names[0]
+	if _, err := s.schema(kindEdge, name); err != nil {
+		return nil, err
+	}
+	var scopes []*scope
+	for _, key := range stmt.keys {
+		src, dst, err := key.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		e := s.edge(name, src, dst, key.rank)
+		if e == nil {
+			continue
+		}
+		scopes = append(scopes, newScope(map[string]interface{}{"edge": e, name: e}))
+	}
+	items := stmt.yields
+	if len(items) == 0 {
+		items = []yieldItem{{e: &varExpr{name: "edge"}, alias: "edges_"}}
+	}
+	return project(items, stmt.distinct, scopes)
+}
+
+// ---------------------------------------------------------------- go
+
+// go [m to n] [step|steps] from vid, ... over edge, ...|* [reversely|bidirect] [where ...] [yield ...]
+type goStmt struct {
+	minSteps, maxSteps int64
+	from               []expr
+	over               []string // 为空表示 *
+	direction          string
+	where              expr
+	yields             []yieldItem
+	distinct           bool
+}
+
+func (p *parser) parseGo() (statement, error) {
+	stmt := &goStmt{minSteps: 1, maxSteps: 1, direction: directionOut}
+	var err error
+	if p.peek().kind == tokenInt {
+		if stmt.minSteps, err = p.integer(); err != nil {
+			return nil, err
+		}
+		stmt.maxSteps = stmt.minSteps
+		if p.accept("to") {
+			if stmt.maxSteps, err = p.integer(); err != nil {
+				return nil, err
+			}
+		}
+		if !p.accept("steps") {
+			p.accept("step")
+		}
+	}
+	if err := p.expect("from"); err != nil {
+		return nil, err
+	}
+	if stmt.from, err = p.parseVidList(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("over"); err != nil {
+		return nil, err
+	}
+	if !p.acceptSymbol("*") {
+		if stmt.over, err = p.identList(); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case p.accept("reversely"):
+		stmt.direction = directionIn
+	case p.accept("bidirect"):
+		stmt.direction = directionBoth
+	}
+	if p.accept("where") {
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("yield") {
+		if stmt.yields, stmt.distinct, err = p.parseYieldItems(); err != nil {
+			return nil, err
+		}
+	}
+	if stmt.minSteps > stmt.maxSteps {
+		return nil, semanticError("`%d' should be less than or equal to `%d'", stmt.minSteps, stmt.maxSteps)
+	}
+	return stmt, nil
+}
+
+func (stmt *goStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.edgeNames(stmt.over)
+	if err != nil {
+		return nil, err
+	}
+	frontier, err := evalVids(s, stmt.from)
+	if err != nil {
+		return nil, err
+	}
+	adj := s.adjacency(names)
+
+	var scopes []*scope
+	for step := int64(1); step <= stmt.maxSteps && len(frontier) > 0; step++ {
+		var next []interface{}
+		seen := make(map[string]bool)
+		for _, vid := range frontier {
+			for _, h := range adj.hops(vid, stmt.direction) {
+				if key := vidKey(h.neighbor); !seen[key] {
+					seen[key] = true
+					next = append(next, h.neighbor)
+				}
+				if step < stmt.minSteps {
+					continue
+				}
+				vars := map[string]interface{}{
+					"$^":   s.vertexOrEmpty(vid),
+					"$$":   s.vertexOrEmpty(h.neighbor),
+					"edge": h.edge,
+				}
+				for _, name := range names {
+					vars[name] = nil
+				}
+				vars[h.edge.name] = h.edge
+				sc := newScope(vars)
+				ok, err := evalBool(stmt.where, sc)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					scopes = append(scopes, sc.with(neighborVar, h.neighbor))
+				}
+			}
+		}
+		frontier = next
+	}
+
+	items := stmt.yields
+	if len(items) == 0 {
+		// 默认返回每种边类型的终点
+		for _, name := range names {
+			items = append(items, yieldItem{e: &goDstExpr{name: name}, alias: name + "._dst"})
+		}
+	}
+	return project(items, stmt.distinct, scopes)
+}
+
+// go 语句中保存当前到达的点VID的内部变量, 不会与用户的变量重名
+const neighborVar = "$neighbor"
+
+// go 语句默认返回的终点列, 当前边不是该类型时为null
+type goDstExpr struct {
+	name string
+}
+
+func (e *goDstExpr) eval(sc *scope) (interface{}, error) {
+	if sc.vars[e.name] == nil {
+		return nil, nil
+	}
+	return sc.vars[neighborVar], nil
+}
+
+// ---------------------------------------------------------------- lookup
+
+// lookup on tag|edge [where ...] [yield ...], 与graphd一致, 需要先创建索引
+type lookupStmt struct {
+	name     string
+	where    expr
+	yields   []yieldItem
+	distinct bool
+}
+
+func (p *parser) parseLookup() (statement, error) {
+	stmt := &lookupStmt{}
+	if err := p.expect("on"); err != nil {
+		return nil, err
+	}
+	var err error
+	if stmt.name, err = p.ident(); err != nil {
+		return nil, err
+	}
+	if p.accept("where") {
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("yield") {
+		if stmt.yields, stmt.distinct, err = p.parseYieldItems(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+func (stmt *lookupStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	kind, indexKind := kindTag, kindTagIndex
+	if _, ok := s.edgeTypes[stmt.name]; ok {
+		kind, indexKind = kindEdge, kindEdgeIndex
+	}
+	if _, err := s.schema(kind, stmt.name); err != nil {
+		return nil, err
+	}
+	if err := stmt.checkIndex(s, indexKind); err != nil {
+		return nil, err
+	}
+
+	var scopes []*scope
+	if kind == kindTag {
+		for _, v := range s.allVertices() {
+			props, ok := v.tags[stmt.name]
+			if !ok {
+				continue
+			}
+			scopes = append(scopes, newScope(map[string]interface{}{"vertex": v, stmt.name: props}))
+		}
+	} else {
+		for _, e := range s.allEdges() {
+			if e.name == stmt.name {
+				scopes = append(scopes, newScope(map[string]interface{}{"edge": e, stmt.name: e}))
+			}
+		}
+	}
+	filtered := scopes[:0]
+	for _, sc := range scopes {
+		ok, err := evalBool(stmt.where, sc)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			filtered = append(filtered, sc)
+		}
+	}
+
+	items := stmt.yields
+	if len(items) == 0 {
+		if kind == kindTag {
+			items = []yieldItem{{e: &callExpr{name: "id", args: []expr{&varExpr{name: "vertex"}}}, alias: "VertexID"}}
+		} else {
+			edgeVar := &varExpr{name: "edge"}
+			items = []yieldItem{
+				{e: &callExpr{name: "src", args: []expr{edgeVar}}, alias: "SrcVID"},
+				{e: &callExpr{name: "dst", args: []expr{edgeVar}}, alias: "DstVID"},
+				{e: &callExpr{name: "rank", args: []expr{edgeVar}}, alias: "Ranking"},
+			}
+		}
+	}
+	return project(items, stmt.distinct, filtered)
+}
+
+// 校验索引: 结构定义上必须有索引, 过滤条件中使用的属性必须被某个索引包含
+func (stmt *lookupStmt) checkIndex(s *space, indexKind string) error {
+	indexed := make(map[string]bool)
+	found := false
+	for _, idx := range s.indexes(indexKind) {
+		if idx.schema != stmt.name {
+			continue
+		}
+		found = true
+		for _, column := range idx.columns {
+			indexed[column] = true
+		}
+	}
+	if !found {
+		return semanticError("There is no index to use at runtime")
+	}
+	var missing string
+	walkExpr(stmt.where, func(node expr) {
+		prop, ok := node.(*propExpr)
+		if !ok {
+			return
+		}
+		if base, ok := prop.base.(*varExpr); ok && base.name == stmt.name && !indexed[prop.name] && missing == "" {
+			missing = prop.name
+		}
+	})
+	if missing != "" {
+		return semanticError("`%s.%s' is not indexed, there is no index to use at runtime", stmt.name, missing)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------- yield
+
+// yield [distinct] expr [as alias], ... [where ...]
+type yieldStmt struct {
+	items    []yieldItem
+	distinct bool
+	where    expr
+}
+
+func (p *parser) parseYield() (*yieldStmt, error) {
+	stmt := &yieldStmt{}
+	var err error
+	if stmt.items, stmt.distinct, err = p.parseYieldItems(); err != nil {
+		return nil, err
+	}
+	if p.accept("where") {
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+func (stmt *yieldStmt) execute(*Dialer) (*result, error) {
+	return stmt.apply([]*scope{newScope(nil)})
+}
+
+// 对输入行求值
+func (stmt *yieldStmt) apply(scopes []*scope) (*result, error) {
+	var filtered []*scope
+	for _, sc := range scopes {
+		ok, err := evalBool(stmt.where, sc)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			filtered = append(filtered, sc)
+		}
+	}
+	return project(stmt.items, stmt.distinct, filtered)
+}
+
+// ---------------------------------------------------------------- 管道
+
+// 管道: 前一条语句的结果作为 $- 输入后续子句, 支持 yield、group by ... yield、order by、limit
+type pipeStmt struct {
+	input   statement
+	yield   *yieldStmt
+	orderBy []orderItem
+	offset  int64
+	limit   int64 // 小于0表示不限制
+}
+
+func (p *parser) parsePipe(input statement) (statement, error) {
+	stmt := &pipeStmt{input: input, limit: -1}
+	var err error
+	switch {
+	case p.accept("yield"):
+		if stmt.yield, err = p.parseYield(); err != nil {
+			return nil, err
+		}
+	case p.peek().is("group") && p.peekAt(1).is("by"):
+		// 按 yield 中的非聚合列分组, 与 group by 的列一致
+		p.pos += 2
+		if _, err = p.parseExprList(); err != nil {
+			return nil, err
+		}
+		if err := p.expect("yield"); err != nil {
+			return nil, err
+		}
+		if stmt.yield, err = p.parseYield(); err != nil {
+			return nil, err
+		}
+	case p.peek().is("order") && p.peekAt(1).is("by"):
+		p.pos += 2
+		if stmt.orderBy, err = p.parseOrderBy(); err != nil {
+			return nil, err
+		}
+	case p.accept("limit"):
+		if stmt.limit, err = p.integer(); err != nil {
+			return nil, err
+		}
+		if p.acceptSymbol(",") {
+			stmt.offset = stmt.limit
+			if stmt.limit, err = p.integer(); err != nil {
+				return nil, err
+			}
+		}
+	case p.accept("offset"):
+		if stmt.offset, err = p.integer(); err != nil {
+			return nil, err
+		}
+		if p.accept("limit") {
+			if stmt.limit, err = p.integer(); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, p.syntaxError(p.peek())
+	}
+	return stmt, nil
+}
+
+func (stmt *pipeStmt) execute(d *Dialer) (*result, error) {
+	input, err := stmt.input.execute(d)
+	if err != nil {
+		return nil, err
+	}
+	if input == nil {
+		input = &result{}
+	}
+	scopes := make([]*scope, len(input.rows))
+	for i, row := range input.rows {
+		values := make(map[string]interface{}, len(row))
+		for j, column := range input.columns {
+			values[column] = row[j]
+		}
+		scopes[i] = newScope(map[string]interface{}{"$-": values})
+	}
+
+	switch {
+	case stmt.yield != nil:
+		return stmt.yield.apply(scopes)
+	case len(stmt.orderBy) > 0:
+		return input, sortResult(input, scopes, stmt.orderBy)
+	}
+	sliceResult(input, stmt.offset, stmt.limit)
+	return input, nil
+}
+
+// ---------------------------------------------------------------- get subgraph
+
+// get subgraph [with prop] n steps from vid, ... [in|out|both edge, ...] [where ...] yield vertices as x, edges as y
+type subgraphStmt struct {
+	withProp  bool
+	steps     int64
+	from      []expr
+	direction string
+	over      []string
+	where     expr
+	vertices  string // 点列名, 为空表示不返回
+	edges     string // 边列名, 为空表示不返回
+	order     []string
+}
+
+func (p *parser) parseSubgraph() (statement, error) {
+	stmt := &subgraphStmt{steps: 1, direction: directionBoth}
+	var err error
+	if p.accept("with") {
+		if err := p.expect("prop"); err != nil {
+			return nil, err
+		}
+		stmt.withProp = true
+	}
+	if p.peek().kind == tokenInt {
+		if stmt.steps, err = p.integer(); err != nil {
+			return nil, err
+		}
+		if !p.accept("steps") {
+			if err := p.expect("step"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := p.expect("from"); err != nil {
+		return nil, err
+	}
+	if stmt.from, err = p.parseVidList(); err != nil {
+		return nil, err
+	}
+	for _, direction := range []string{directionIn, directionOut, directionBoth} {
+		if p.accept(direction) {
+			stmt.direction = direction
+			if !p.acceptSymbol("*") {
+				if stmt.over, err = p.identList(); err != nil {
+					return nil, err
+				}
+			}
+			break
+		}
+	}
+	if p.accept("where") {
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect("yield"); err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.next()
+		kind := strings.ToLower(tok.text)
+		if tok.kind != tokenIdent || (kind != "vertices" && kind != "edges") {
+			return nil, p.syntaxError(tok)
+		}
+		column := strings.ToUpper(kind)
+		if p.accept("as") {
+			if column, err = p.ident(); err != nil {
+				return nil, err
+			}
+		}
+		if kind == "vertices" {
+			stmt.vertices = column
+		} else {
+			stmt.edges = column
+		}
+		stmt.order = append(stmt.order, kind)
+		if !p.acceptSymbol(",") {
+			return stmt, nil
+		}
+	}
+}
+
+func (stmt *subgraphStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.edgeNames(stmt.over)
+	if err != nil {
+		return nil, err
+	}
+	frontier, err := evalVids(s, stmt.from)
+	if err != nil {
+		return nil, err
+	}
+	adj := s.adjacency(names)
+
+	visited := make(map[string]bool)
+	for _, vid := range frontier {
+		visited[vidKey(vid)] = true
+	}
+	seenEdges := make(map[string]bool)
+	res := &result{}
+	for _, kind := range stmt.order {
+		if kind == "vertices" {
+			res.columns = append(res.columns, stmt.vertices)
+		} else {
+			res.columns = append(res.columns, stmt.edges)
+		}
+	}
+
+	for step := int64(0); step <= stmt.steps && len(frontier) > 0; step++ {
+		vertices := []interface{}{}
+		// 与nebula一致, 不存在的起点(或悬挂边的端点)以只有VID的点返回
+		for _, vid := range frontier {
+			vertices = append(vertices, stmt.strip(s.vertexOrEmpty(vid)))
+		}
+		edges := []interface{}{}
+		var next []interface{}
+		for _, vid := range frontier {
+			for _, h := range adj.hops(vid, stmt.direction) {
+				key := edgeKey(h.edge.name, h.edge.src, h.edge.dst, h.edge.rank)
+				neighborKey := vidKey(h.neighbor)
+				// 最后一步只返回已访问点之间的边
+				if seenEdges[key] || (step == stmt.steps && !visited[neighborKey]) {
+					continue
+				}
+				ok, err := evalBool(stmt.where, newScope(map[string]interface{}{
+					"$^": s.vertexOrEmpty(vid), "$$": s.vertexOrEmpty(h.neighbor), "edge": h.edge,
+					h.edge.name: h.edge,
+				}))
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+				seenEdges[key] = true
+				edges = append(edges, stmt.strip(h.edge))
+				if !visited[neighborKey] {
+					visited[neighborKey] = true
+					next = append(next, h.neighbor)
+				}
+			}
+		}
+		if len(vertices) == 0 && len(edges) == 0 {
+			break
+		}
+		row := make([]interface{}, 0, 2)
+		for _, kind := range stmt.order {
+			if kind == "vertices" {
+				row = append(row, vertices)
+			} else {
+				row = append(row, edges)
+			}
+		}
+		res.rows = append(res.rows, row)
+		frontier = next
+	}
+	return res, nil
+}
+
+// 没有 with prop 时去掉点边的属性
+func (stmt *subgraphStmt) strip(v interface{}) interface{} {
+	if stmt.withProp {
+		return v
+	}
+	switch val := v.(type) {
+	case *vertex:
+		stripped := &vertex{vid: val.vid, tags: make(map[string]map[string]interface{}, len(val.tags))}
+		for name := range val.tags {
+			stripped.tags[name] = map[string]interface{}{}
+		}
+		return stripped
+	case *edge:
+		return &edge{name: val.name, src: val.src, dst: val.dst, rank: val.rank, props: map[string]interface{}{}}
+	}
+	return v
+}
@@ -0,0 +1,862 @@
+package memory
+
+import (
+	"fmt"
+	nebula_type "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 结构定义的种类
+const (
+	kindTag       = "tag"
+	kindEdge      = "edge"
+	kindTagIndex  = "tag_index"
+	kindEdgeIndex = "edge_index"
+)
+
+// 支持的属性类型
+var propTypes = map[string]bool{
+	"bool": true, "int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"float": true, "double": true, "string": true, "fixed_string": true,
+	"timestamp": true, "date": true, "time": true, "datetime": true,
+}
+
+// ---------------------------------------------------------------- 图空间
+
+// create space [if not exists] name (vid_type = ..., ...)
+type createSpaceStmt struct {
+	name        string
+	ifNotExists bool
+	vidType     string
+}
+
+func (p *parser) parseCreateSpace() (statement, error) {
+	stmt := &createSpaceStmt{vidType: "FIXED_STRING(8)"}
+	stmt.ifNotExists = p.parseIfNotExists()
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	stmt.name = name
+	if p.acceptSymbol("(") {
+		for !p.peek().isSymbol(")") {
+			key, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectSymbol("="); err != nil {
+				return nil, err
+			}
+			value, err := p.parseOptionValue()
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(key, "vid_type") {
+				switch upper := strings.ToUpper(value); {
+				case upper == "INT64" || upper == "INT":
+					stmt.vidType = "INT64"
+				case strings.HasPrefix(upper, "FIXED_STRING("):
+					stmt.vidType = upper
+				default:
+					return nil, semanticError("Only support FIXED_STRING or INT64 vid type, but was `%s'", value)
+				}
+			}
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+	}
+	// comment = '...'
+	for !p.atEnd() {
+		p.next()
+	}
+	return stmt, nil
+}
+
+// 解析选项值: 标识符(可带长度)、整数或字符串
+func (p *parser) parseOptionValue() (string, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenIdent:
+		if p.acceptSymbol("(") {
+			size, err := p.integer()
+			if err != nil {
+				return "", err
+			}
+			if err := p.expectSymbol(")"); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s(%d)", tok.text, size), nil
+		}
+		return tok.text, nil
+	case tokenInt, tokenString:
+		return tok.text, nil
+	}
+	return "", p.syntaxError(tok)
+}
+
+func (stmt *createSpaceStmt) execute(d *Dialer) (*result, error) {
+	if _, ok := d.spaces[stmt.name]; ok {
+		if stmt.ifNotExists {
+			return nil, nil
+		}
+		return nil, executionError("Existed!")
+	}
+	d.spaces[stmt.name] = newSpace(stmt.name, stmt.vidType)
+	d.spaceOrder = append(d.spaceOrder, stmt.name)
+	return nil, nil
+}
+
+// use name
+type useStmt struct {
+	name string
+}
+
+func (stmt *useStmt) execute(d *Dialer) (*result, error) {
+	if _, ok := d.spaces[stmt.name]; !ok {
+		return nil, executionError("SpaceNotFound: SpaceName `%s`", stmt.name)
+	}
+	d.current = stmt.name
+	return nil, nil
+}
+
+// ---------------------------------------------------------------- 标签与边类型
+
+// create tag|edge [if not exists] name (prop type [null|not null] [default x] [comment 'x'], ...) [comment = 'x']
+type createSchemaStmt struct {
+	kind        string
+	name        string
+	ifNotExists bool
+	props       []*propDef
+	comment     string
+}
+
+func (p *parser) parseCreateSchema(kind string) (statement, error) {
+	stmt := &createSchemaStmt{kind: kind}
+	stmt.ifNotExists = p.parseIfNotExists()
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	stmt.name = name
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	for !p.peek().isSymbol(")") {
+		prop, err := p.parsePropDef()
+		if err != nil {
+			return nil, err
+		}
+		stmt.props = append(stmt.props, prop)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	// ttl_duration = 0, ttl_col = "", comment = ""
+	for !p.atEnd() {
+		key := p.next()
+		if key.is("comment") && p.acceptSymbol("=") {
+			stmt.comment = p.next().text
+		}
+	}
+	return stmt, nil
+}
+
+// 解析属性定义
+func (p *parser) parsePropDef() (*propDef, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	prop := &propDef{name: name, nullable: true}
+	if prop.typ, err = p.parsePropType(); err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("null"):
+			prop.nullable = true
+		case p.peek().is("not") && p.peekAt(1).is("null"):
+			p.pos += 2
+			prop.nullable = false
+		case p.accept("default"):
+			if prop.defaultExpr, prop.defaultText, err = p.parseNamedExpr(); err != nil {
+				return nil, err
+			}
+		case p.accept("comment"):
+			tok := p.next()
+			if tok.kind != tokenString {
+				return nil, p.syntaxError(tok)
+			}
+			prop.comment = tok.text
+		default:
+			return prop, nil
+		}
+	}
+}
+
+// 解析属性类型, 统一为小写, int 视为 int64
+func (p *parser) parsePropType() (string, error) {
+	tok := p.next()
+	if tok.kind != tokenIdent {
+		return "", p.syntaxError(tok)
+	}
+	typ := strings.ToLower(tok.text)
+	if !propTypes[typ] {
+		return "", semanticError("Unsupported property type `%s'", tok.text)
+	}
+	if typ == "int" {
+		typ = "int64"
+	}
+	if typ == "fixed_string" {
+		if err := p.expectSymbol("("); err != nil {
+			return "", err
+		}
+		size, err := p.integer()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return "", err
+		}
+		typ = fmt.Sprintf("fixed_string(%d)", size)
+	}
+	return typ, nil
+}
+
+func (stmt *createSchemaStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	if s.tags[stmt.name] != nil || s.edgeTypes[stmt.name] != nil {
+		if stmt.ifNotExists {
+			return nil, nil
+		}
+		return nil, executionError("Existed!")
+	}
+	for i, prop := range stmt.props {
+		for _, other := range stmt.props[:i] {
+			if other.name == prop.name {
+				return nil, semanticError("Duplicate column name `%s'", prop.name)
+			}
+		}
+		if prop.defaultExpr != nil {
+			value, err := prop.defaultExpr.eval(newScope(nil))
+			if err != nil {
+				return nil, err
+			}
+			if _, err := checkValue(prop, value); err != nil {
+				return nil, semanticError("Invalid default value for `%s'", prop.name)
+			}
+		}
+	}
+	schemas := s.schemas(stmt.kind)
+	schemas[stmt.name] = &schema{name: stmt.name, props: stmt.props, comment: stmt.comment}
+	s.addOrder(stmt.kind, stmt.name)
+	return nil, nil
+}
+
+// alter tag|edge name add (...) | drop (...) | change (...)
+type alterSchemaStmt struct {
+	kind    string
+	name    string
+	adds    []*propDef
+	changes []*propDef
+	drops   []string
+}
+
+func (p *parser) parseAlterSchema(kind string) (statement, error) {
+	stmt := &alterSchemaStmt{kind: kind}
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	stmt.name = name
+	for !p.atEnd() {
+		action := strings.ToLower(p.next().text)
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		for !p.peek().isSymbol(")") {
+			switch action {
+			case "add", "change":
+				prop, err := p.parsePropDef()
+				if err != nil {
+					return nil, err
+				}
+				if action == "add" {
+					stmt.adds = append(stmt.adds, prop)
+				} else {
+					stmt.changes = append(stmt.changes, prop)
+				}
+			case "drop":
+				prop, err := p.ident()
+				if err != nil {
+					return nil, err
+				}
+				stmt.drops = append(stmt.drops, prop)
+			default:
+				return nil, p.syntaxError(p.prev())
+			}
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		p.acceptSymbol(",")
+	}
+	return stmt, nil
+}
+
+func (stmt *alterSchemaStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	sc, err := s.schema(stmt.kind, stmt.name)
+	if err != nil {
+		return nil, err
+	}
+	for _, prop := range stmt.adds {
+		if sc.prop(prop.name) != nil {
+			return nil, executionError("Existed!")
+		}
+		sc.props = append(sc.props, prop)
+	}
+	for _, prop := range stmt.changes {
+		for i, old := range sc.props {
+			if old.name == prop.name {
+				sc.props[i] = prop
+			}
+		}
+	}
+	for _, name := range stmt.drops {
+		for i, old := range sc.props {
+			if old.name == name {
+				sc.props = append(sc.props[:i], sc.props[i+1:]...)
+				break
+			}
+		}
+	}
+	return nil, nil
+}
+
+// ---------------------------------------------------------------- 索引
+
+// create tag|edge index [if not exists] name on schema(col[(len)], ...)
+type createIndexStmt struct {
+	kind        string
+	name        string
+	ifNotExists bool
+	schema      string
+	columns     []string
+}
+
+func (p *parser) parseCreateIndex(kind string) (statement, error) {
+	stmt := &createIndexStmt{kind: kind}
+	stmt.ifNotExists = p.parseIfNotExists()
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	stmt.name = name
+	if err := p.expect("on"); err != nil {
+		return nil, err
+	}
+	if stmt.schema, err = p.ident(); err != nil {
+		return nil, err
+	}
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	for !p.peek().isSymbol(")") {
+		column, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		// 字符串索引的长度
+		if p.acceptSymbol("(") {
+			if _, err := p.integer(); err != nil {
+				return nil, err
+			}
+			if err := p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+		}
+		stmt.columns = append(stmt.columns, column)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	for !p.atEnd() {
+		p.next()
+	}
+	return stmt, nil
+}
+
+func (stmt *createIndexStmt) execute(d *Dialer) (*result, error) {
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	schemaKind := kindTag
+	if stmt.kind == kindEdgeIndex {
+		schemaKind = kindEdge
+	}
+	sc, err := s.schema(schemaKind, stmt.schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range stmt.columns {
+		if sc.prop(column) == nil {
+			return nil, executionError("Key not existed!")
+		}
+	}
+	indexes := s.indexes(stmt.kind)
+	if _, ok := indexes[stmt.name]; ok {
+		if stmt.ifNotExists {
+			return nil, nil
+		}
+		return nil, executionError("Existed!")
+	}
+	indexes[stmt.name] = &index{name: stmt.name, schema: stmt.schema, columns: stmt.columns}
+	s.addOrder(stmt.kind, stmt.name)
+	return nil, nil
+}
+
+// rebuild tag|edge index [name, ...], 内存中的索引始终可用, 仅返回任务编号
+type rebuildIndexStmt struct{}
+
+func (stmt *rebuildIndexStmt) execute(d *Dialer) (*result, error) {
+	if _, err := d.space(); err != nil {
+		return nil, err
+	}
+	return &result{columns: []string{"New Job Id"}, rows: [][]interface{}{{int64(1)}}}, nil
+}
+
+// ---------------------------------------------------------------- 删除结构定义
+
+// drop space|tag|edge|tag index|edge index [if exists] name
+type dropStmt struct {
+	kind     string // space 或结构定义的种类
+	name     string
+	ifExists bool
+}
+
+func (stmt *dropStmt) execute(d *Dialer) (*result, error) {
+	if stmt.kind == "space" {
+		if _, ok := d.spaces[stmt.name]; !ok {
+			if stmt.ifExists {
+				return nil, nil
+			}
+			return nil, executionError("SpaceNotFound: SpaceName `%s`", stmt.name)
+		}
+		delete(d.spaces, stmt.name)
+		for i, name := range d.spaceOrder {
+			if name == stmt.name {
+				d.spaceOrder = append(d.spaceOrder[:i], d.spaceOrder[i+1:]...)
+				break
+			}
+		}
+		if d.current == stmt.name {
+			d.current = ""
+		}
+		return nil, nil
+	}
+
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	var exists bool
+	switch stmt.kind {
+	case kindTag, kindEdge:
+		_, exists = s.schemas(stmt.kind)[stmt.name]
+	default:
+		_, exists = s.indexes(stmt.kind)[stmt.name]
+	}
+	if !exists {
+		if stmt.ifExists {
+			return nil, nil
+		}
+		return nil, executionError("%s not existed!", strings.Replace(stmt.kind, "_", " ", 1))
+	}
+
+	switch stmt.kind {
+	case kindTag:
+		if s.hasIndex(kindTagIndex, stmt.name) {
+			return nil, executionError("Conflict!")
+		}
+		delete(s.tags, stmt.name)
+		for _, v := range s.allVertices() {
+			delete(v.tags, stmt.name)
+		}
+	case kindEdge:
+		if s.hasIndex(kindEdgeIndex, stmt.name) {
+			return nil, executionError("Conflict!")
+		}
+		delete(s.edgeTypes, stmt.name)
+		for _, e := range s.allEdges() {
+			if e.name == stmt.name {
+				s.deleteEdge(e.name, e.src, e.dst, e.rank)
+			}
+		}
+	default:
+		delete(s.indexes(stmt.kind), stmt.name)
+	}
+	s.removeOrder(stmt.kind, stmt.name)
+	return nil, nil
+}
+
+// ---------------------------------------------------------------- 查看结构定义
+
+// show spaces|tags|edges|tag indexes|edge indexes
+type showStmt struct {
+	kind string
+}
+
+func (stmt *showStmt) execute(d *Dialer) (*result, error) {
+	if stmt.kind == "space" {
+		res := &result{columns: []string{"Name"}}
+		for _, name := range d.spaceOrder {
+			res.rows = append(res.rows, []interface{}{name})
+		}
+		return res, nil
+	}
+
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	switch stmt.kind {
+	case kindTag, kindEdge:
+		res := &result{columns: []string{"Name"}}
+		for _, name := range s.schemaOrders[stmt.kind] {
+			res.rows = append(res.rows, []interface{}{name})
+		}
+		return res, nil
+	}
+
+	by := "By Tag"
+	if stmt.kind == kindEdgeIndex {
+		by = "By Edge"
+	}
+	res := &result{columns: []string{"Index Name", by, "Columns"}}
+	indexes := s.indexes(stmt.kind)
+	for _, name := range s.schemaOrders[stmt.kind] {
+		idx := indexes[name]
+		columns := make([]interface{}, len(idx.columns))
+		for i, column := range idx.columns {
+			columns[i] = column
+		}
+		res.rows = append(res.rows, []interface{}{idx.name, idx.schema, columns})
+	}
+	return res, nil
+}
+
+// describe space|tag|edge|tag index|edge index name
+type describeStmt struct {
+	kind string
+	name string
+}
+
+func (stmt *describeStmt) execute(d *Dialer) (*result, error) {
+	if stmt.kind == "space" {
+		s, ok := d.spaces[stmt.name]
+		if !ok {
+			return nil, executionError("SpaceNotFound: SpaceName `%s`", stmt.name)
+		}
+		id := int64(0)
+		for i, name := range d.spaceOrder {
+			if name == stmt.name {
+				id = int64(i + 1)
+			}
+		}
+		return &result{
+			columns: []string{"ID", "Name", "Partition Number", "Replica Factor", "Charset", "Collate",
+				"Vid Type", "Atomic Edge", "Group", "Comment"},
+			rows: [][]interface{}{{id, s.name, int64(1), int64(1), "utf8", "utf8_bin",
+				s.vidType, false, "default", nil}},
+		}, nil
+	}
+
+	s, err := d.space()
+	if err != nil {
+		return nil, err
+	}
+	switch stmt.kind {
+	case kindTag, kindEdge:
+		sc, err := s.schema(stmt.kind, stmt.name)
+		if err != nil {
+			return nil, err
+		}
+		res := &result{columns: []string{"Field", "Type", "Null", "Default", "Comment"}}
+		for _, prop := range sc.props {
+			null := "YES"
+			if !prop.nullable {
+				null = "NO"
+			}
+			var defaultValue, comment interface{}
+			if prop.defaultExpr != nil {
+				if defaultValue, err = prop.defaultExpr.eval(newScope(nil)); err != nil {
+					return nil, err
+				}
+			}
+			if prop.comment != "" {
+				comment = prop.comment
+			}
+			res.rows = append(res.rows, []interface{}{prop.name, prop.typ, null, defaultValue, comment})
+		}
+		return res, nil
+	}
+
+	idx, ok := s.indexes(stmt.kind)[stmt.name]
+	if !ok {
+		return nil, executionError("Index not found")
+	}
+	schemaKind := kindTag
+	if stmt.kind == kindEdgeIndex {
+		schemaKind = kindEdge
+	}
+	sc, err := s.schema(schemaKind, idx.schema)
+	if err != nil {
+		return nil, err
+	}
+	res := &result{columns: []string{"Field", "Type"}}
+	for _, column := range idx.columns {
+		typ := ""
+		if prop := sc.prop(column); prop != nil {
+			typ = prop.typ
+		}
+		res.rows = append(res.rows, []interface{}{column, typ})
+	}
+	return res, nil
+}
+
+// ---------------------------------------------------------------- 结构定义的查找与校验
+
+// 指定种类的结构定义
+func (s *space) schemas(kind string) map[string]*schema {
+	if kind == kindEdge {
+		return s.edgeTypes
+	}
+	return s.tags
+}
+
+// 指定种类的索引
+func (s *space) indexes(kind string) map[string]*index {
+	if kind == kindEdgeIndex {
+		return s.edgeIndexes
+	}
+	return s.tagIndexes
+}
+
+// 查找结构定义, 不存在时返回与graphd一致的错误
+func (s *space) schema(kind, name string) (*schema, error) {
+	sc, ok := s.schemas(kind)[name]
+	if !ok {
+		return nil, semanticError("No schema found for `%s'", name)
+	}
+	return sc, nil
+}
+
+// 结构定义上是否存在索引
+func (s *space) hasIndex(kind, schemaName string) bool {
+	for _, idx := range s.indexes(kind) {
+		if idx.schema == schemaName {
+			return true
+		}
+	}
+	return false
+}
+
+// 查找写入使用的结构定义, 开启自动建表时不存在的结构定义与属性会按写入的值自动创建
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func (d *Dialer) writeSchema(s *space, kind, name string, props []string, values []interface{}) (*schema, error) {
+	sc, ok := s.schemas(kind)[name]
+	if !ok {
+		if !d.autoSchema {
+			return nil, semanticError("No schema found for `%s'", name)
+		}
+		sc = &schema{name: name}
+		s.schemas(kind)[name] = sc
+		s.addOrder(kind, name)
+	}
+	for i, prop := range props {
+		if sc.prop(prop) != nil {
+			continue
+		}
+		if !d.autoSchema {
+			return nil, semanticError("No schema found for `%s.%s'", name, prop)
+		}
+		var value interface{}
+		if i < len(values) {
+			value = values[i]
+		}
+		sc.props = append(sc.props, &propDef{name: prop, typ: inferType(value), nullable: true})
+	}
+	return sc, nil
+}
+
+// 根据值推断属性类型
+func inferType(value interface{}) string {
+	switch value.(type) {
+	case bool:
+		return "bool"
+	case int64:
+		return "int64"
+	case float64:
+		return "double"
+	case nebula_type.Date:
+		return "date"
+	case nebula_type.Time:
+		return "time"
+	case nebula_type.DateTime:
+		return "datetime"
+	}
+	return "string"
+}
+
+// 按结构定义组装写入的属性: 未指定的属性使用默认值, 没有默认值时必须可为空
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func buildProps(sc *schema, names []string, values []interface{}) (map[string]interface{}, error) {
+	props := make(map[string]interface{}, len(sc.props))
+	for i, name := range names {
+		prop := sc.prop(name)
+		value, err := checkValue(prop, values[i])
+		if err != nil {
+			return nil, err
+		}
+		props[name] = value
+	}
+	for _, prop := range sc.props {
+		if _, ok := props[prop.name]; ok {
+			continue
+		}
+		value, err := defaultValue(prop)
+		if err != nil {
+			return nil, err
+		}
+		props[prop.name] = value
+	}
+	return props, nil
+}
+
+// 属性的默认值
+func defaultValue(prop *propDef) (interface{}, error) {
+	if prop.defaultExpr == nil {
+		if !prop.nullable {
+			return nil, storageError(nebula_type.ErrorCode_E_FIELD_UNSET, "The not null field doesn't have a default value.")
+		}
+		return nil, nil
+	}
+	value, err := prop.defaultExpr.eval(newScope(nil))
+	if err != nil {
+		return nil, err
+	}
+	return checkValue(prop, value)
+}
+
+// 按属性类型校验并转换写入的值
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func checkValue(prop *propDef, value interface{}) (interface{}, error) {
+	if value == nil {
+		if !prop.nullable {
+			return nil, storageError(nebula_type.ErrorCode_E_NOT_NULLABLE, "The not null field cannot be null.")
+		}
+		return nil, nil
+	}
+
+	mismatch := storageError(nebula_type.ErrorCode_E_DATA_TYPE_MISMATCH,
+		"The data type does not meet the requirements. Use the correct type of data.")
+	switch typ := prop.typ; {
+	case typ == "bool":
+		if _, ok := value.(bool); ok {
+			return value, nil
+		}
+	case typ == "int64" || typ == "int32" || typ == "int16" || typ == "int8" || typ == "timestamp":
+		i, ok := value.(int64)
+		if !ok {
+			break
+		}
+		if bits, _ := strconv.Atoi(strings.TrimPrefix(typ, "int")); bits > 0 && bits < 64 {
+			limit := int64(1) << (bits - 1)
+			if i < -limit || i >= limit {
+				return nil, storageError(nebula_type.ErrorCode_E_OUT_OF_RANGE, "Out of range value.")
+			}
+		}
+		return i, nil
+	case typ == "float" || typ == "double":
+		if f, ok := toFloat(value); ok {
+			return f, nil
+		}
+	case typ == "string":
+		if _, ok := value.(string); ok {
+			return value, nil
+		}
+	case strings.HasPrefix(typ, "fixed_string("):
+		str, ok := value.(string)
+		if !ok {
+			break
+		}
+		var size int
+		fmt.Sscanf(typ, "fixed_string(%d)", &size)
+		// 与graphd一致, 超出长度的部分被截断, 这里保证不截断半个字符
+		for len(str) > size {
+			_, width := utf8.DecodeLastRuneInString(str)
+			str = str[:len(str)-width]
+		}
+		return str, nil
+	case typ == "date":
+		if _, ok := value.(nebula_type.Date); ok {
+			return value, nil
+		}
+	case typ == "time":
+		if _, ok := value.(nebula_type.Time); ok {
+			return value, nil
+		}
+	case typ == "datetime":
+		if _, ok := value.(nebula_type.DateTime); ok {
+			return value, nil
+		}
+	}
+	return nil, mismatch
+}
+
+// 解析 if not exists
+func (p *parser) parseIfNotExists() bool {
+	if p.peek().is("if") && p.peekAt(1).is("not") && p.peekAt(2).is("exists") {
+		p.pos += 3
+		return true
+	}
+	return false
+}
+
+// 解析 if exists
+func (p *parser) parseIfExists() bool {
+	if p.peek().is("if") && p.peekAt(1).is("exists") {
+		p.pos += 2
+		return true
+	}
+	return false
+}
@@ -0,0 +1,278 @@
+package memory
+
+import (
+	"fmt"
+	nebula_type "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"sort"
+	"strings"
+)
+
+// 属性定义
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type propDef struct {
+	name        string
+	typ         string // 小写的属性类型, 例如 string、int64、fixed_string(32)
+	nullable    bool   // 是否允许为空
+	defaultExpr expr   // 默认值表达式, 未设置时为nil, 每次写入时求值(例如 default now())
+	defaultText string // 默认值的原始文本, 用于 describe
+	comment     string
+}
+
+// 标签或边类型的结构定义
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type schema struct {
+	name    string
+	props   []*propDef
+	comment string
+}
+
+// 根据属性名查找属性定义
+func (s *schema) prop(name string) *propDef {
+	for _, prop := range s.props {
+		if prop.name == name {
+			return prop
+		}
+	}
+	return nil
+}
+
+// 索引定义
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type index struct {
+	name    string
+	schema  string   // 标签名或边类型名
+	columns []string // 索引的属性
+}
+
+// 点: 一个VID可以拥有多个标签
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type vertex struct {
+	vid  interface{}
+	tags map[string]map[string]interface{} // 标签名 -> 属性
+}
+
+// 按名称排序的标签名, 保证输出稳定
+func (v *vertex) tagNames() []string {
+	names := make([]string, 0, len(v.tags))
+	for name := range v.tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// 合并所有标签的属性, 对应 properties(vertex)
+func (v *vertex) allProps() map[string]interface{} {
+	props := make(map[string]interface{})
+	for _, name := range v.tagNames() {
+		for key, value := range v.tags[name] {
+			props[key] = value
+		}
+	}
+	return props
+}
+
+// 边: 边类型 + 起点 + 终点 + rank 唯一确定一条边
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type edge struct {
+	name  string
+	src   interface{}
+	dst   interface{}
+	rank  int64
+	props map[string]interface{}
+}
+
+// 图空间, 保存结构定义与数据, 点与边按插入顺序保存以保证查询结果稳定
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type space struct {
+	name         string
+	vidType      string // FIXED_STRING(n) 或 INT64
+	tags         map[string]*schema
+	edgeTypes    map[string]*schema
+	tagIndexes   map[string]*index
+	edgeIndexes  map[string]*index
+	vertices     map[string]*vertex
+	vertexOrder  []string
+	edges        map[string]*edge
+	edgeOrder    []string
+	schemaOrders map[string][]string // tag/edge/tag_index/edge_index 的创建顺序
+}
+
+// 创建图空间
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func newSpace(name, vidType string) *space {
+	return &space{
+		name:         name,
+		vidType:      vidType,
+		tags:         make(map[string]*schema),
+		edgeTypes:    make(map[string]*schema),
+		tagIndexes:   make(map[string]*index),
+		edgeIndexes:  make(map[string]*index),
+		vertices:     make(map[string]*vertex),
+		edges:        make(map[string]*edge),
+		schemaOrders: make(map[string][]string),
+	}
+}
+
+// VID的唯一键, 区分字符串与整型
+func vidKey(vid interface{}) string {
+	return fmt.Sprintf("%T:%v", vid, vid)
+}
+
+// 边的唯一键
+func edgeKey(name string, src, dst interface{}, rank int64) string {
+	return strings.Join([]string{name, vidKey(src), vidKey(dst), fmt.Sprint(rank)}, "\x00")
+}
+
+// 校验VID的类型与长度是否符合图空间的VID类型
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func (s *space) checkVid(vid interface{}) error {
+	if s.vidType == "INT64" {
+		if _, ok := vid.(int64); !ok {
+			return semanticError("`%s', the vid should be type of INT64, but was`%s'", valueString(vid), typeName(vid))
+		}
+		return nil
+	}
+	str, ok := vid.(string)
+	if !ok {
+		return semanticError("`%s', the vid should be type of FIXED_STRING, but was`%s'", valueString(vid), typeName(vid))
+	}
+	var size int
+	if _, err := fmt.Sscanf(s.vidType, "FIXED_STRING(%d)", &size); err == nil && len(str) > size {
+		return storageError(nebula_type.ErrorCode_E_INVALID_VID,
+			"The VID must be a 64-bit integer or a string fitting space vertex id length limit.")
+	}
+	return nil
+}
+
+// 查找点
+func (s *space) vertex(vid interface{}) *vertex {
+	return s.vertices[vidKey(vid)]
+}
+
+// 按插入顺序返回所有点
+func (s *space) allVertices() []*vertex {
+	vertices := make([]*vertex, 0, len(s.vertexOrder))
+	for _, key := range s.vertexOrder {
+		if v, ok := s.vertices[key]; ok {
+			vertices = append(vertices, v)
+		}
+	}
+	return vertices
+}
+
+// 写入点的一个标签, 点不存在时创建
+func (s *space) putVertex(vid interface{}, tag string, props map[string]interface{}) {
+	key := vidKey(vid)
+	v, ok := s.vertices[key]
+	if !ok {
+		v = &vertex{vid: vid, tags: make(map[string]map[string]interface{})}
+		s.vertices[key] = v
+		s.vertexOrder = append(s.vertexOrder, key)
+	}
+	if tag != "" {
+		v.tags[tag] = props
+	}
+}
+
+// 删除点
+func (s *space) deleteVertex(vid interface{}) {
+	key := vidKey(vid)
+	if _, ok := s.vertices[key]; !ok {
+		return
+	}
+	delete(s.vertices, key)
+	for i, k := range s.vertexOrder {
+		if k == key {
+			s.vertexOrder = append(s.vertexOrder[:i], s.vertexOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// 查找边
+func (s *space) edge(name string, src, dst interface{}, rank int64) *edge {
+	return s.edges[edgeKey(name, src, dst, rank)]
+}
+
+// 按插入顺序返回所有边
+func (s *space) allEdges() []*edge {
+	edges := make([]*edge, 0, len(s.edgeOrder))
+	for _, key := range s.edgeOrder {
+		if e, ok := s.edges[key]; ok {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// 写入边, 已存在时覆盖属性
+func (s *space) putEdge(e *edge) {
+	key := edgeKey(e.name, e.src, e.dst, e.rank)
+	if _, ok := s.edges[key]; !ok {
+		s.edgeOrder = append(s.edgeOrder, key)
+	}
+	s.edges[key] = e
+}
+
+// 删除边
+func (s *space) deleteEdge(name string, src, dst interface{}, rank int64) {
+	key := edgeKey(name, src, dst, rank)
+	if _, ok := s.edges[key]; !ok {
+		return
+	}
+	delete(s.edges, key)
+	for i, k := range s.edgeOrder {
+		if k == key {
+			s.edgeOrder = append(s.edgeOrder[:i], s.edgeOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// 删除与点关联的所有边
+func (s *space) deleteEdgesOf(vid interface{}) {
+	key := vidKey(vid)
+	for _, e := range s.allEdges() {
+		if vidKey(e.src) == key || vidKey(e.dst) == key {
+			s.deleteEdge(e.name, e.src, e.dst, e.rank)
+		}
+	}
+}
+
+// 记录结构定义的创建顺序
+func (s *space) addOrder(kind, name string) {
+	for _, n := range s.schemaOrders[kind] {
+		if n == name {
+			return
+		}
+	}
+	s.schemaOrders[kind] = append(s.schemaOrders[kind], name)
+}
+
+// 删除结构定义的创建顺序
+func (s *space) removeOrder(kind, name string) {
+	names := s.schemaOrders[kind]
+	for i, n := range names {
+		if n == name {
+			s.schemaOrders[kind] = append(names[:i], names[i+1:]...)
+			return
+		}
+	}
+}
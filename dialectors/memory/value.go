@@ -0,0 +1,433 @@
+package memory
+
+import (
+	"fmt"
+	nebula_type "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"math"
+	"nebula-orm-go/utils"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// 引擎内部使用的值类型:
+// nil、bool、int64、float64、string、nebula_type.Date、nebula_type.Time、nebula_type.DateTime、
+// []interface{}(列表)、map[string]interface{}(映射)、*vertex、*edge、*path
+
+// 路径: 起点 + 若干步, 每一步记录经过的边与到达的点
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type path struct {
+	nodes []*vertex
+	edges []*edge
+}
+
+// 将内部值转换为nebula值, 点、边、路径转换为nebula的结构, 其余值交给 utils.InterfaceToNValue
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func toNValue(v interface{}, s *space) (*nebula_type.Value, error) {
+	value := nebula_type.NewValue()
+	switch val := v.(type) {
+	case *vertex:
+		vertex, err := toNVertex(val)
+		if err != nil {
+			return nil, err
+		}
+		value.VVal = vertex
+	case *edge:
+		edge, err := toNEdge(val, s)
+		if err != nil {
+			return nil, err
+		}
+		value.EVal = edge
+	case *path:
+		p, err := toNPath(val, s)
+		if err != nil {
+			return nil, err
+		}
+		value.PVal = p
+	case []interface{}:
+		list := make([]*nebula_type.Value, len(val))
+		for i, item := range val {
+			nValue, err := toNValue(item, s)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = nValue
+		}
+		value.LVal = &nebula_type.NList{Values: list}
+	case map[string]interface{}:
+		kvs := make(map[string]*nebula_type.Value, len(val))
+		for key, item := range val {
+			nValue, err := toNValue(item, s)
+			if err != nil {
+				return nil, err
+			}
+			kvs[key] = nValue
+		}
+		value.MVal = &nebula_type.NMap{Kvs: kvs}
+	default:
+		return utils.InterfaceToNValue(v)
+	}
+	return value, nil
+}
+
+// 转换属性map
+func toNProps(props map[string]interface{}) (map[string]*nebula_type.Value, error) {
+	values := make(map[string]*nebula_type.Value, len(props))
+	for key, prop := range props {
+		value, err := toNValue(prop, nil)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// 转换点
+func toNVertex(v *vertex) (*nebula_type.Vertex, error) {
+	vid, err := utils.InterfaceToNValue(v.vid)
+	if err != nil {
+		return nil, err
+	}
+	nVertex := &nebula_type.Vertex{Vid: vid, Tags: make([]*nebula_type.Tag, 0, len(v.tags))}
+	for _, name := range v.tagNames() {
+		props, err := toNProps(v.tags[name])
+		if err != nil {
+			return nil, err
+		}
+		nVertex.Tags = append(nVertex.Tags, &nebula_type.Tag{Name: []byte(name), Props: props})
+	}
+	return nVertex, nil
+}
+
+// 转换边, 边类型编号按边类型的创建顺序从1开始
+func toNEdge(e *edge, s *space) (*nebula_type.Edge, error) {
+	src, err := utils.InterfaceToNValue(e.src)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := utils.InterfaceToNValue(e.dst)
+	if err != nil {
+		return nil, err
+	}
+	props, err := toNProps(e.props)
+	if err != nil {
+		return nil, err
+	}
+	return &nebula_type.Edge{
+		Src:     src,
+		Dst:     dst,
+		Type:    edgeTypeID(s, e.name),
+		Name:    []byte(e.name),
+		Ranking: e.rank,
+		Props:   props,
+	}, nil
+}
+
+// 转换路径, 逆着边的方向经过时边类型编号为负数
+func toNPath(p *path, s *space) (*nebula_type.Path, error) {
+	start, err := toNVertex(p.nodes[0])
+	if err != nil {
+		return nil, err
+	}
+	nPath := &nebula_type.Path{Src: start}
+	for i, e := range p.edges {
+		dst, err := toNVertex(p.nodes[i+1])
+		if err != nil {
+			return nil, err
+		}
+		props, err := toNProps(e.props)
+		if err != nil {
+			return nil, err
+		}
+		typ := edgeTypeID(s, e.name)
+		if vidKey(e.src) != vidKey(p.nodes[i].vid) {
+			typ = -typ
+		}
+		nPath.Steps = append(nPath.Steps, &nebula_type.Step{
+			Dst:     dst,
+			Type:    typ,
+			Name:    []byte(e.name),
+			Ranking: e.rank,
+			Props:   props,
+		})
+	}
+	return nPath, nil
+}
+
+// 边类型编号
+func edgeTypeID(s *space, name string) int32 {
+	if s != nil {
+		for i, n := range s.schemaOrders[kindEdge] {
+			if n == name {
+				return int32(i + 1)
+			}
+		}
+	}
+	return 1
+}
+
+// 值的类型名, 用于错误信息
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		return "BOOL"
+	case int64:
+		return "INT"
+	case float64:
+		return "FLOAT"
+	case string:
+		return "STRING"
+	case nebula_type.Date:
+		return "DATE"
+	case nebula_type.Time:
+		return "TIME"
+	case nebula_type.DateTime:
+		return "DATETIME"
+	case []interface{}:
+		return "LIST"
+	case map[string]interface{}:
+		return "MAP"
+	case *vertex:
+		return "VERTEX"
+	case *edge:
+		return "EDGE"
+	case *path:
+		return "PATH"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// 转换为浮点数
+func toFloat(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case int64:
+		return float64(val), true
+	case float64:
+		return val, true
+	}
+	return 0, false
+}
+
+// 判断两个值是否相等, 任一为null时返回null
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func valueEqual(a, b interface{}) interface{} {
+	if a == nil || b == nil {
+		return nil
+	}
+	return equalValues(a, b)
+}
+
+// 判断两个非null值是否相等
+func equalValues(a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			return fa == fb
+		}
+		return false
+	}
+	switch va := a.(type) {
+	case *vertex:
+		vb, ok := b.(*vertex)
+		return ok && vidKey(va.vid) == vidKey(vb.vid)
+	case *edge:
+		vb, ok := b.(*edge)
+		return ok && edgeKey(va.name, va.src, va.dst, va.rank) == edgeKey(vb.name, vb.src, vb.dst, vb.rank)
+	case *path:
+		vb, ok := b.(*path)
+		if !ok || len(va.edges) != len(vb.edges) || !equalValues(va.nodes[0], vb.nodes[0]) {
+			return false
+		}
+		for i := range va.edges {
+			if !equalValues(va.edges[i], vb.edges[i]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		vb, ok := b.([]interface{})
+		if !ok || len(va) != len(vb) {
+			return false
+		}
+		for i := range va {
+			if va[i] == nil || vb[i] == nil {
+				if va[i] != vb[i] {
+					return false
+				}
+				continue
+			}
+			if !equalValues(va[i], vb[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		vb, ok := b.(map[string]interface{})
+		if !ok || len(va) != len(vb) {
+			return false
+		}
+		for key, item := range va {
+			other, ok := vb[key]
+			if !ok || (item == nil) != (other == nil) || (item != nil && !equalValues(item, other)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+// 比较两个值的大小, 类型不可比较时 ok 返回false
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func compareValues(a, b interface{}) (int, bool) {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+	switch va := a.(type) {
+	case string:
+		vb, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(va, vb), true
+	case bool:
+		vb, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case va == vb:
+			return 0, true
+		case !va:
+			return -1, true
+		}
+		return 1, true
+	case nebula_type.Date:
+		vb, ok := b.(nebula_type.Date)
+		if !ok {
+			return 0, false
+		}
+		return compareInts([]int64{int64(va.Year), int64(va.Month), int64(va.Day)},
+			[]int64{int64(vb.Year), int64(vb.Month), int64(vb.Day)}), true
+	case nebula_type.Time:
+		vb, ok := b.(nebula_type.Time)
+		if !ok {
+			return 0, false
+		}
+		return compareInts([]int64{int64(va.Hour), int64(va.Minute), int64(va.Sec), int64(va.Microsec)},
+			[]int64{int64(vb.Hour), int64(vb.Minute), int64(vb.Sec), int64(vb.Microsec)}), true
+	case nebula_type.DateTime:
+		vb, ok := b.(nebula_type.DateTime)
+		if !ok {
+			return 0, false
+		}
+		return compareInts(
+			[]int64{int64(va.Year), int64(va.Month), int64(va.Day), int64(va.Hour), int64(va.Minute), int64(va.Sec), int64(va.Microsec)},
+			[]int64{int64(vb.Year), int64(vb.Month), int64(vb.Day), int64(vb.Hour), int64(vb.Minute), int64(vb.Sec), int64(vb.Microsec)}), true
+	}
+	return 0, false
+}
+
+// 逐项比较整数序列
+func compareInts(a, b []int64) int {
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// 排序用的比较, null排在最后, 不可比较的值按类型名排序
+func orderValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	if c, ok := compareValues(a, b); ok {
+		return c
+	}
+	return strings.Compare(typeName(a), typeName(b))
+}
+
+// 值的文本表示, 用于 toString 与字符串拼接
+func valueString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "__NULL__"
+	case string:
+		return val
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1e15 {
+			return fmt.Sprintf("%.1f", val)
+		}
+		return fmt.Sprint(val)
+	case nebula_type.Date:
+		return fmt.Sprintf("%04d-%02d-%02d", val.Year, val.Month, val.Day)
+	case nebula_type.Time:
+		return fmt.Sprintf("%02d:%02d:%02d.%06d", val.Hour, val.Minute, val.Sec, val.Microsec)
+	case nebula_type.DateTime:
+		return fmt.Sprintf("%04d-%02d-%02dT%02d:%02d:%02d.%06d", val.Year, val.Month, val.Day, val.Hour, val.Minute, val.Sec, val.Microsec)
+	case []interface{}:
+		items := make([]string, len(val))
+		for i, item := range val {
+			items[i] = valueString(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, key := range keys {
+			items[i] = key + ": " + valueString(val[key])
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	case *vertex:
+		return fmt.Sprintf("(%v)", val.vid)
+	case *edge:
+		return fmt.Sprintf("(%v)-[:%s@%d]->(%v)", val.src, val.name, val.rank, val.dst)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// 复制属性map
+func copyProps(props map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(props))
+	for key, value := range props {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,260 @@
+// Package mock 提供用于单元测试的拨号器实现:
+// 1. Dialer: 断言期望执行的nGQL(精确匹配或正则匹配), 并返回预设的结果集或错误
+// 2. Recorder/Replayer: 录制真实拨号器的请求与响应并保存到文件, 之后离线回放
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+package mock
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"nebula-orm-go/dialectors"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var _ dialectors.IDialer = new(Dialer)
+
+// Expectation 一条期望执行的nGQL及其预设返回
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+type Expectation struct {
+	ngql    string         // 精确匹配的nGQL
+	pattern *regexp.Regexp // 正则匹配的nGQL
+	times   int            // 期望执行次数
+	called  int            // 实际执行次数
+
+	result *dialectors.ResultSet // 预设结果集
+	err    error                 // 预设错误
+}
+
+// WillReturnRows 使用列名与 Go 语言的行数据作为预设结果集, 值的转换规则见 utils.InterfaceToNValue
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (e *Expectation) WillReturnRows(columns []string, rows ...[]interface{}) *Expectation {
+	result, err := dialectors.NewResultSet(columns, rows...)
+	if err != nil {
+		panic(errors.Wrap(err, "构造模拟结果集失败"))
+	}
+	e.result = result
+	return e
+}
+
+// WillReturnResultSet 使用给定的结果集作为预设返回
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (e *Expectation) WillReturnResultSet(result *dialectors.ResultSet) *Expectation {
+	e.result = result
+	return e
+}
+
+// WillReturnError 执行时返回给定的错误
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (e *Expectation) WillReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// WillReturnErrorCode 执行时返回 graphd 的执行错误(dialectors.ExecuteError)
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (e *Expectation) WillReturnErrorCode(code nebula.ErrorCode, msg string) *Expectation {
+	e.err = &dialectors.ExecuteError{Code: code, Msg: msg}
+	return e
+}
+
+// Times 设置期望执行的次数, 默认为1次
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// 判断nGQL是否符合期望, 精确匹配时忽略首尾空白
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (e *Expectation) match(ngql string) bool {
+	if e.pattern != nil {
+		return e.pattern.MatchString(ngql)
+	}
+	return strings.TrimSpace(e.ngql) == strings.TrimSpace(ngql)
+}
+
+// String 返回期望的描述
+func (e *Expectation) String() string {
+	if e.pattern != nil {
+		return fmt.Sprintf("正则 %q (期望 %d 次, 实际 %d 次)", e.pattern.String(), e.times, e.called)
+	}
+	return fmt.Sprintf("%q (期望 %d 次, 实际 %d 次)", e.ngql, e.times, e.called)
+}
+
+// Dialer 模拟拨号器, 按添加顺序(或任意顺序)匹配执行的nGQL并返回预设结果。
+// 未匹配到任何期望的nGQL会返回错误, 测试结束时通过 ExpectationsWereMet 检查所有期望都已执行。
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+type Dialer struct {
+	mu           sync.Mutex
+	expectations []*Expectation
+	unordered    bool     // 是否允许任意顺序匹配
+	executed     []string // 已执行的nGQL
+	unexpected   []string // 未匹配到期望的nGQL
+	closed       bool
+}
+
+// New 创建模拟拨号器, 默认要求按期望添加的顺序执行
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func New() *Dialer {
+	return &Dialer{}
+}
+
+// MatchInOrder 设置是否要求按期望添加的顺序执行, 默认为true
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (d *Dialer) MatchInOrder(inOrder bool) *Dialer {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.unordered = !inOrder
+	return d
+}
+
+// Expect 添加一条精确匹配的期望(忽略首尾空白), 未设置返回值时返回空结果集
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (d *Dialer) Expect(ngql string) *Expectation {
+	return d.add(&Expectation{ngql: ngql})
+}
+
+// ExpectRegexp 添加一条正则匹配的期望, 正则无效时panic
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (d *Dialer) ExpectRegexp(pattern string) *Expectation {
+	return d.add(&Expectation{pattern: regexp.MustCompile(pattern)})
+}
+
+// 添加期望
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (d *Dialer) add(e *Expectation) *Expectation {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e.times = 1
+	d.expectations = append(d.expectations, e)
+	return e
+}
+
+// Execute 匹配期望并返回预设结果, 实现 dialectors.IDialer 接口
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (d *Dialer) Execute(sql string) (*dialectors.ResultSet, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return &dialectors.ResultSet{}, errors.New("模拟拨号器已关闭")
+	}
+	d.executed = append(d.executed, sql)
+
+	for _, e := range d.expectations {
+		if e.called >= e.times {
+			continue
+		}
+		if !e.match(sql) {
+			// 按顺序匹配时, 只能匹配第一个未完成的期望
+			if !d.unordered {
+				break
+			}
+			continue
+		}
+
+		e.called++
+		if e.err != nil {
+			return &dialectors.ResultSet{}, e.err
+		}
+		if e.result == nil {
+			return dialectors.NewResultSet(nil)
+		}
+		return e.result, nil
+	}
+
+	d.unexpected = append(d.unexpected, sql)
+	if next := d.next(); next != nil {
+		return &dialectors.ResultSet{}, errors.Errorf("nGQL与期望不符:\n  实际: %q\n  期望: %s", sql, next)
+	}
+	return &dialectors.ResultSet{}, errors.Errorf("未设置期望的nGQL: %q", sql)
+}
+
+// 返回下一个未完成的期望
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (d *Dialer) next() *Expectation {
+	for _, e := range d.expectations {
+		if e.called < e.times {
+			return e
+		}
+	}
+	return nil
+}
+
+// Close 关闭模拟拨号器, 之后的执行都会返回错误
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (d *Dialer) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+}
+
+// Executed 返回已执行的全部nGQL
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (d *Dialer) Executed() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.executed...)
+}
+
+// ExpectationsWereMet 检查所有期望都已按次数执行, 且没有执行过未期望的nGQL
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (d *Dialer) ExpectationsWereMet() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var problems []string
+	for _, e := range d.expectations {
+		if e.called < e.times {
+			problems = append(problems, "未执行: "+e.String())
+		}
+	}
+	for _, sql := range d.unexpected {
+		problems = append(problems, fmt.Sprintf("未期望: %q", sql))
+	}
+	if len(problems) > 0 {
+		return errors.New("模拟拨号器的期望未满足:\n  " + strings.Join(problems, "\n  "))
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package mock
+
+import (
+	"errors"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"nebula-orm-go/dialectors"
+	"strings"
+	"testing"
+)
+
+// 按顺序匹配期望并返回预设结果
+func TestExpectInOrder(t *testing.T) {
+	d := New()
+	d.Expect("insert vertex person(name) values 'a':('甲')")
+	d.ExpectRegexp(`^fetch prop on person`).
+		WillReturnRows([]string{"name", "age"}, []interface{}{"甲", 30}).Times(2)
+
+	if _, err := d.Execute("  insert vertex person(name) values 'a':('甲')  "); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		result, err := d.Execute("fetch prop on person 'a' yield person.name as name, person.age as age")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rows []map[string]interface{}
+		if err = result.UnmarshalResultSet(&rows); err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 1 || rows[0]["name"] != "甲" || rows[0]["age"] != int64(30) {
+			t.Fatalf("结果集 = %v", rows)
+		}
+	}
+	if err := d.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(d.Executed()); got != 3 {
+		t.Fatalf("已执行 %d 条, 期望 3", got)
+	}
+}
+
+// 顺序不符、未期望、未执行的nGQL都会报告
+func TestExpectMismatch(t *testing.T) {
+	d := New()
+	d.Expect("a")
+	d.Expect("b")
+	if _, err := d.Execute("b"); err == nil || !strings.Contains(err.Error(), "与期望不符") {
+		t.Fatalf("顺序不符时应返回错误, 实际为 %v", err)
+	}
+	err := d.ExpectationsWereMet()
+	if err == nil || !strings.Contains(err.Error(), `未期望: "b"`) || !strings.Contains(err.Error(), "未执行") {
+		t.Fatalf("期望检查结果错误: %v", err)
+	}
+
+	// 任意顺序匹配
+	d = New().MatchInOrder(false)
+	d.Expect("a")
+	d.Expect("b")
+	for _, ngql := range []string{"b", "a"} {
+		if _, err := d.Execute(ngql); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Execute("c"); err == nil || !strings.Contains(err.Error(), "未设置期望") {
+		t.Fatalf("没有剩余期望时应返回错误, 实际为 %v", err)
+	}
+}
+
+// 预设错误与关闭后的执行
+func TestExpectError(t *testing.T) {
+	d := New()
+	d.Expect("a").WillReturnErrorCode(nebula.ErrorCode_E_SEMANTIC_ERROR, "SemanticError: 未知标签")
+	boom := errors.New("网络异常")
+	d.Expect("b").WillReturnError(boom)
+
+	_, err := d.Execute("a")
+	if code := dialectors.GetErrorCode(err); code != nebula.ErrorCode_E_SEMANTIC_ERROR {
+		t.Fatalf("错误码 = %d", code)
+	}
+	if _, err = d.Execute("b"); !errors.Is(err, boom) {
+		t.Fatalf("错误 = %v, 期望 %v", err, boom)
+	}
+	d.Close()
+	if _, err = d.Execute("a"); err == nil {
+		t.Fatal("关闭后执行应返回错误")
+	}
+}
+
+// 录制后回放得到相同的结果与错误
+func TestRecordReplay(t *testing.T) {
+	origin := New()
+	origin.Expect("fetch a").WillReturnRows([]string{"name"}, []interface{}{"甲"})
+	origin.Expect("fetch a").WillReturnRows([]string{"name"}, []interface{}{"乙"})
+	origin.Expect("bad").WillReturnErrorCode(nebula.ErrorCode_E_SYNTAX_ERROR, "SyntaxError: syntax error")
+	origin.Expect("rpc").WillReturnError(errors.New("连接断开"))
+
+	path := t.TempDir() + "/record.json"
+	recorder := NewRecorder(origin, path)
+	dialer := recorder.Dialer()
+	for _, ngql := range []string{"fetch a", "fetch a", "bad", "rpc"} {
+		_, _ = dialer.Execute(ngql)
+	}
+	// 视图的 Close 不保存也不关闭真实拨号器
+	dialer.Close()
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := origin.Execute("fetch a"); err == nil || !strings.Contains(err.Error(), "已关闭") {
+		t.Fatalf("Recorder.Close 应关闭真实拨号器, 实际为 %v", err)
+	}
+
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 同一条nGQL按录制顺序返回, 超出次数后重复最后一次
+	for _, want := range []string{"甲", "乙", "乙"} {
+		result, err := replayer.Execute("fetch a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rows []map[string]interface{}
+		if err = result.UnmarshalResultSet(&rows); err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 1 || rows[0]["name"] != want {
+			t.Fatalf("回放结果 = %v, 期望 %s", rows, want)
+		}
+	}
+	_, err = replayer.Execute("bad")
+	var executeErr *dialectors.ExecuteError
+	if !errors.As(err, &executeErr) || executeErr.Code != nebula.ErrorCode_E_SYNTAX_ERROR || executeErr.Msg != "SyntaxError: syntax error" {
+		t.Fatalf("回放的执行错误 = %v", err)
+	}
+	if _, err = replayer.Execute("rpc"); err == nil || !strings.Contains(err.Error(), "连接断开") {
+		t.Fatalf("回放的客户端错误 = %v", err)
+	}
+	if _, err = replayer.Execute("unknown"); err == nil {
+		t.Fatal("未录制的nGQL应返回错误")
+	}
+}
+
+// 保存失败时 Close 返回错误并仍然关闭真实拨号器
+func TestRecorderCloseError(t *testing.T) {
+	origin := New()
+	recorder := NewRecorder(origin, t.TempDir()+"/missing/record.json")
+	if err := recorder.Close(); err == nil {
+		t.Fatal("保存到不存在的目录时应返回错误")
+	}
+	if _, err := origin.Execute("a"); err == nil || !strings.Contains(err.Error(), "已关闭") {
+		t.Fatalf("保存失败时也应关闭真实拨号器, 实际为 %v", err)
+	}
+	if _, err := NewReplayer(t.TempDir() + "/missing.json"); err == nil {
+		t.Fatal("录制文件不存在时应返回错误")
+	}
+}
@@ -0,0 +1,181 @@
+package mock
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+	"nebula-orm-go/dialectors"
+	"os"
+	"sync"
+)
+
+var (
+	_ dialectors.IDialer = recorderDialer{}
+	_ dialectors.IDialer = new(Replayer)
+)
+
+// Interaction 一次录制的请求与响应
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+type Interaction struct {
+	NGQL      string                   `json:"ngql"`               // 执行的nGQL
+	Response  *graph.ExecutionResponse `json:"response,omitempty"` // 执行成功时的响应
+	ErrorCode nebula.ErrorCode         `json:"error_code"`         // 执行失败时的错误码, 见 dialectors.GetErrorCode
+	Error     string                   `json:"error,omitempty"`    // 执行失败时的错误信息
+}
+
+// Recorder 录制拨号器, 将请求转发给真实拨号器并记录请求与响应, Close 时保存到文件。
+// Close 需要返回保存失败的错误, 因此 Recorder 本身不实现 IDialer, 通过 Dialer 获取传给 orm 的拨号器
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+type Recorder struct {
+	mu           sync.Mutex
+	dialer       dialectors.IDialer
+	path         string
+	interactions []Interaction
+}
+
+// NewRecorder 创建录制拨号器, 录制结果以JSON格式保存到 path
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func NewRecorder(dialer dialectors.IDialer, path string) *Recorder {
+	return &Recorder{dialer: dialer, path: path}
+}
+
+// Execute 转发给真实拨号器执行并记录请求与响应
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (r *Recorder) Execute(sql string) (*dialectors.ResultSet, error) {
+	result, err := r.dialer.Execute(sql)
+
+	interaction := Interaction{NGQL: sql}
+	if err != nil {
+		interaction.ErrorCode = dialectors.GetErrorCode(err)
+		interaction.Error = err.Error()
+		var executeError *dialectors.ExecuteError
+		if errors.As(err, &executeError) {
+			interaction.Error = executeError.Msg
+		}
+	} else {
+		interaction.Response = result.ToResponse()
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+	return result, err
+}
+
+// Save 将已录制的请求与响应保存到文件
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "序列化录制数据失败")
+	}
+	return errors.Wrap(os.WriteFile(r.path, data, 0644), "保存录制数据失败")
+}
+
+// Close 保存录制结果并关闭真实拨号器, 保存失败时仍会关闭真实拨号器并返回保存的错误
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (r *Recorder) Close() error {
+	defer r.dialer.Close()
+	return r.Save()
+}
+
+// Dialer 返回录制拨号器的 IDialer 视图, 用于 orm.Open 等需要拨号器的地方。
+// 视图的 Close 不做任何操作, 录制结束时需要调用 Recorder.Close 保存结果
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *Recorder) Dialer() dialectors.IDialer {
+	return recorderDialer{r}
+}
+
+// 录制拨号器的 IDialer 视图, Execute 由 Recorder 提供
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+type recorderDialer struct {
+	*Recorder
+}
+
+// Close 由 Recorder.Close 保存并关闭, 这里不做任何操作
+func (d recorderDialer) Close() {}
+
+// Replayer 回放拨号器, 从录制文件中按nGQL查找响应, 不需要连接graphd。
+// 同一条nGQL录制了多次时按录制顺序依次返回, 超出录制次数后重复返回最后一次的响应。
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+type Replayer struct {
+	mu           sync.Mutex
+	interactions map[string][]Interaction
+	cursor       map[string]int
+}
+
+// NewReplayer 从录制文件创建回放拨号器
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func NewReplayer(path string) (*Replayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取录制数据失败")
+	}
+	var interactions []Interaction
+	if err = json.Unmarshal(data, &interactions); err != nil {
+		return nil, errors.Wrap(err, "解析录制数据失败")
+	}
+
+	replayer := &Replayer{
+		interactions: make(map[string][]Interaction),
+		cursor:       make(map[string]int),
+	}
+	for _, interaction := range interactions {
+		replayer.interactions[interaction.NGQL] = append(replayer.interactions[interaction.NGQL], interaction)
+	}
+	return replayer, nil
+}
+
+// Execute 返回录制的响应, 未录制的nGQL返回错误
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func (r *Replayer) Execute(sql string) (*dialectors.ResultSet, error) {
+	r.mu.Lock()
+	interactions, ok := r.interactions[sql]
+	if !ok {
+		r.mu.Unlock()
+		return &dialectors.ResultSet{}, errors.Errorf("未录制的nGQL: %q", sql)
+	}
+	i := r.cursor[sql]
+	if i < len(interactions)-1 {
+		r.cursor[sql] = i + 1
+	}
+	interaction := interactions[i]
+	r.mu.Unlock()
+
+	if interaction.Error != "" || interaction.Response == nil {
+		if interaction.ErrorCode != nebula.ErrorCode_E_RPC_FAILURE {
+			return &dialectors.ResultSet{}, &dialectors.ExecuteError{Code: interaction.ErrorCode, Msg: interaction.Error}
+		}
+		return &dialectors.ResultSet{}, errors.New(interaction.Error)
+	}
+	return dialectors.NewResultSetFromResponse(interaction.Response)
+}
+
+// Close 回放拨号器无需关闭任何资源
+func (r *Replayer) Close() {}
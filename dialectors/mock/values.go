@@ -0,0 +1,58 @@
+package mock
+
+import (
+	nebula_type "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"nebula-orm-go/utils"
+)
+
+// Vertex 构造一个点值, 可直接作为 WillReturnRows 的单元格(例如 get subgraph 返回的点列表)
+// props 中的值通过 utils.InterfaceToNValue 转换, 转换失败时panic
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func Vertex(vid interface{}, tag string, props map[string]interface{}) *nebula_type.Vertex {
+	return &nebula_type.Vertex{
+		Vid:  mustNValue(vid),
+		Tags: []*nebula_type.Tag{{Name: []byte(tag), Props: mustProps(props)}},
+	}
+}
+
+// Edge 构造一个边值, 可直接作为 WillReturnRows 的单元格
+// props 中的值通过 utils.InterfaceToNValue 转换, 转换失败时panic
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func Edge(src, dst interface{}, name string, rank int64, props map[string]interface{}) *nebula_type.Edge {
+	return &nebula_type.Edge{
+		Src:     mustNValue(src),
+		Dst:     mustNValue(dst),
+		Type:    1,
+		Name:    []byte(name),
+		Ranking: rank,
+		Props:   mustProps(props),
+	}
+}
+
+// 转换属性map, 转换失败时panic
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func mustProps(props map[string]interface{}) map[string]*nebula_type.Value {
+	values := make(map[string]*nebula_type.Value, len(props))
+	for key, prop := range props {
+		values[key] = mustNValue(prop)
+	}
+	return values
+}
+
+// 转换单个值, 转换失败时panic
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func mustNValue(v interface{}) *nebula_type.Value {
+	value, err := utils.InterfaceToNValue(v)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
@@ -1,12 +1,17 @@
 package dialectors
 
 import (
+	"context"
 	"fmt"
 	"github.com/pkg/errors"
 	nebula "github.com/vesoft-inc/nebula-go/v3" // 导入Nebula Go客户端库
 	"nebula-orm-go/config"
+	"nebula-orm-go/metrics"
+	"nebula-orm-go/utils"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 var _ IDialer = new(NebulaDialer)
@@ -20,15 +25,35 @@ type IDialer interface {
 	Close()                                 // 关闭连接池
 }
 
+// IContextDialer 拨号器的可选接口, 实现时 orm 以携带语句 Span 的上下文调用 ExecuteContext,
+// 拨号器可以据此创建子 Span 或响应上下文取消
+//
+// @Author: 罗德
+// @Date: 2024/6/20
+type IContextDialer interface {
+	ExecuteContext(ctx context.Context, sql string) (*ResultSet, error)
+}
+
 // NebulaDialer 结构体，用于管理Nebula Graph的连接和会话
 //
 // @Author: 罗德
 // @Date: 2024/5/24
 type NebulaDialer struct {
-	pool     *nebula.ConnectionPool // 连接池实例
-	username string                 // 认证用户名
-	password string                 // 认证密码
-	space    string                 // 当前操作的图空间名
+	pool     sessionPool   // 连接池实例
+	username string        // 认证用户名
+	password string        // 认证密码
+	space    atomic.Value  // 当前操作的图空间名(string), UseSpace 可能与 Execute 并发调用
+	metrics  metrics.ISink // 连接池指标采集(会话等待时间、使用中的会话数)
+	inUse    int64         // 当前使用中的会话数
+}
+
+// 获取会话的连接池, 由 *nebula.ConnectionPool 实现
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+type sessionPool interface {
+	GetSession(username, password string) (*nebula.Session, error)
+	Close()
 }
 
 // NewNebulaDialer 创建一个新的NebulaDialer实例
@@ -63,6 +88,7 @@ func NewNebulaDialer(cfg config.DialerConfig) (*NebulaDialer, error) {
 		pool:     nPool,
 		username: cfg.Username,
 		password: cfg.Password,
+		metrics:  cfg.Metrics,
 	}, nil
 }
 
@@ -84,7 +110,7 @@ func MustNewNebulaDialer(cfg config.DialerConfig) *NebulaDialer {
 		}
 	}
 
-	dialer.space = cfg.Space
+	dialer.UseSpace(cfg.Space)
 
 	return dialer
 }
@@ -99,11 +125,11 @@ func (d *NebulaDialer) Execute(sql string) (*ResultSet, error) {
 	if err != nil {
 		return &ResultSet{}, err
 	}
-	defer session.Release()
+	defer d.releaseSession(session)
 
 	// 使用指定的图空间
-	if d.space != "" {
-		_, err = session.Execute("use " + d.space)
+	if space, _ := d.space.Load().(string); space != "" {
+		_, err = session.Execute("use " + space)
 		if err != nil {
 			return &ResultSet{}, err
 		}
@@ -124,33 +150,70 @@ func (d *NebulaDialer) Execute(sql string) (*ResultSet, error) {
 	return &ResultSet{result}, nil
 }
 
-// CreateSpace 创建图空间
+// CreateSpace 创建图空间, VID类型为 FIXED_STRING(64)
 //
 // @Author: 罗德
 // @Date: 2024/5/24
 func (d *NebulaDialer) CreateSpace(space string) error {
+	return d.CreateSpaceWithVidType(space, "FIXED_STRING(64)")
+}
+
+// CreateSpaceWithVidType 创建指定VID类型的图空间, vidType 为 INT64 或 FIXED_STRING(n)
+//
+// @Author: 罗德
+// @Date: 2024/7/5
+func (d *NebulaDialer) CreateSpaceWithVidType(space string, vidType string) error {
 	// 参数校验
 	if space == "" {
 		return fmt.Errorf("初始化图空间失败：space 参数无效")
 	}
+	typ, err := utils.ParseVidType(vidType)
+	if err != nil {
+		return fmt.Errorf("初始化图空间失败：%w", err)
+	}
 
 	// 创建空间
-	createSpaceSQL := fmt.Sprintf("CREATE SPACE IF NOT EXISTS %s(VID_TYPE=FIXED_STRING(64));", space)
+	createSpaceSQL := fmt.Sprintf("CREATE SPACE IF NOT EXISTS %s(VID_TYPE=%s);", space, typ)
 	if _, err := d.Execute(createSpaceSQL); err != nil {
 		return err
 	}
 
-	d.space = space
+	d.UseSpace(space)
 
 	return nil
 }
 
-// getSession 从连接池获取会话
+// UseSpace 切换后续语句使用的图空间, 每条语句执行前都会在会话中执行 use。
+// 可以与 Execute 并发调用, 已经开始执行的语句仍使用切换前的图空间
+//
+// @Author: 罗德
+// @Date: 2024/7/8
+func (d *NebulaDialer) UseSpace(space string) {
+	d.space.Store(space)
+}
+
+// getSession 从连接池获取会话, 并记录会话等待时间与使用中的会话数
 //
 // @Author: 罗德
 // @Date: 2024/5/24
 func (d *NebulaDialer) getSession() (*nebula.Session, error) {
-	return d.pool.GetSession(d.username, d.password)
+	start := time.Now()
+	session, err := d.pool.GetSession(d.username, d.password)
+	d.metrics.ObserveSessionWait(time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+	d.metrics.SetSessionsInUse(atomic.AddInt64(&d.inUse, 1))
+	return session, nil
+}
+
+// releaseSession 释放会话, 并更新使用中的会话数
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func (d *NebulaDialer) releaseSession(session *nebula.Session) {
+	session.Release()
+	d.metrics.SetSessionsInUse(atomic.AddInt64(&d.inUse, -1))
 }
 
 // Close 关闭连接池
@@ -167,12 +230,10 @@ func (d *NebulaDialer) Close() {
 // @Date: 2024/5/24
 func checkResultSet(nSet *nebula.ResultSet) error {
 	if nSet.GetErrorCode() != nebula.ErrorCode_SUCCEEDED {
-		return errors.New(fmt.Sprintf("code: %d, msg: %s",
-			nSet.GetErrorCode(), nSet.GetErrorMsg()))
+		return errors.WithStack(&ExecuteError{Code: nSet.GetErrorCode(), Msg: nSet.GetErrorMsg()})
 	}
 	if !nSet.IsSucceed() {
-		return errors.New(fmt.Sprintf("code: %d, msg: %s",
-			nSet.GetErrorCode(), nSet.GetErrorMsg()))
+		return errors.WithStack(&ExecuteError{Code: nSet.GetErrorCode(), Msg: nSet.GetErrorMsg()})
 	}
 	return nil
 }
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"nebula-orm-go/model"
+	"nebula-orm-go/plan"
 	"nebula-orm-go/utils"
 	"reflect"
 )
@@ -99,6 +101,17 @@ func (resultSet *ResultSet) ToVertexs() ([][]map[string]interface{}, error) {
 	return vertexMaps, nil
 }
 
+// Plan 解析 explain/profile 返回的执行计划为计划树, 语句未使用 explain/profile 时返回错误
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func (resultSet *ResultSet) Plan() (*plan.Plan, error) {
+	if resultSet.ResultSet == nil || !resultSet.IsSetPlanDesc() {
+		return nil, errors.New("结果集中没有执行计划, 请确认语句使用了 explain 或 profile")
+	}
+	return plan.FromPlanDescription(resultSet.GetPlanDesc())
+}
+
 // UnmarshalResultSet 解码 ResultSet 到给定的接口类型中。
 // 支持的目标类型包括：字典(map)，字典指针(*map)，字典切片指针(*[]map)，结构体及其指针，结构体切片及其指针，以及一些基本整数类型。
 //
@@ -236,14 +249,15 @@ func toStruct(val reflect.Value, resultSet *ResultSet) (err error) {
 		// 通过反射获取字段
 		field := val.Field(fieldPos)
 		// 尝试设置字段值，这里假设setFieldValue是一个处理字段赋值的函数
-		err = utils.SetFieldValue(field, value)
+		err = utils.SetStructFieldValue(field, val.Type().Field(fieldPos), value)
 		// 如果在设置字段值时发生错误，应立刻返回
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	// 解码完成后调用模型的查询后钩子
+	return callAfterFind(val)
 }
 
 // 将 ResultSet 中的多行数据转换并填充到结构体切片的反射值中。
@@ -296,16 +310,34 @@ func toStructSlice(val reflect.Value, resultSet *ResultSet) (err error) {
 			// 获取切片中的结构体实例
 			field := val.Index(i).Field(fieldPos)
 			// 尝试设置字段值
-			err = utils.SetFieldValue(field, nValue)
+			err = utils.SetStructFieldValue(field, val.Index(i).Type().Field(fieldPos), nValue)
 			// 如果在设置字段值时发生错误，应立刻返回
 			if err != nil {
 				return err
 			}
 		}
+		// 每个元素解码完成后调用模型的查询后钩子
+		if err = callAfterFind(val.Index(i)); err != nil {
+			return err
+		}
 	}
 	return
 }
 
+// 调用结构体的查询后钩子(model.IAfterFind), 优先使用指针接收者, 未实现钩子时直接返回nil
+//
+// @Author: 罗德
+// @Date: 2024/6/17
+func callAfterFind(val reflect.Value) error {
+	if val.CanAddr() {
+		val = val.Addr()
+	}
+	if hook, ok := val.Interface().(model.IAfterFind); ok {
+		return hook.AfterFind()
+	}
+	return nil
+}
+
 // 将 ResultSet 数据转换并填充到一个字典(map[string]interface{})中。
 //
 // 参数:
@@ -0,0 +1,64 @@
+package dialectors
+
+import (
+	"errors"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"nebula-orm-go/metrics"
+	"testing"
+	"time"
+)
+
+// 测试用连接池: 获取会话前等待 wait, err 不为空时获取失败; 返回的 nil 会话可以安全 Release
+type fakePool struct {
+	wait time.Duration
+	err  error
+}
+
+func (p *fakePool) GetSession(string, string) (*nebula.Session, error) {
+	time.Sleep(p.wait)
+	return nil, p.err
+}
+
+func (p *fakePool) Close() {}
+
+// 获取会话时记录等待时间, 持有会话期间计入使用中的会话数, 释放后减少
+func TestSessionMetrics(t *testing.T) {
+	sink := metrics.NewMemorySink(1, 10, 1000)
+	d := &NebulaDialer{pool: &fakePool{wait: 20 * time.Millisecond}, metrics: sink}
+
+	const count = 3
+	sessions := make([]*nebula.Session, count)
+	for i := range sessions {
+		session, err := d.getSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessions[i] = session
+		if got := sink.Snapshot().SessionsInUse; got != int64(i+1) {
+			t.Fatalf("获取 %d 个会话后 SessionsInUse = %d", i+1, got)
+		}
+	}
+	for i, session := range sessions {
+		d.releaseSession(session)
+		if got := sink.Snapshot().SessionsInUse; got != int64(count-i-1) {
+			t.Fatalf("释放 %d 个会话后 SessionsInUse = %d", i+1, got)
+		}
+	}
+	// 等待 20ms 落在 (10, 1000] 的桶
+	if wait := sink.Snapshot().SessionWait; wait.Count != count || wait.Counts[2] != count || wait.Sum < 20*count {
+		t.Errorf("SessionWait = %+v", wait)
+	}
+}
+
+// 获取会话失败时仍记录等待时间, 不计入使用中的会话数, Execute 返回连接池的错误
+func TestSessionMetricsError(t *testing.T) {
+	sink := metrics.NewMemorySink()
+	errPool := errors.New("没有可用的连接")
+	d := &NebulaDialer{pool: &fakePool{err: errPool}, metrics: sink}
+	if _, err := d.Execute("show spaces"); !errors.Is(err, errPool) {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if snapshot := sink.Snapshot(); snapshot.SessionWait.Count != 1 || snapshot.SessionsInUse != 0 {
+		t.Errorf("snapshot = %+v", snapshot)
+	}
+}
@@ -0,0 +1,113 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// CSVWriter 每个标签、每种边类型各输出一个 CSV 文件:
+//   - 点: vertex_<标签>.csv, 表头为 vid 与标签的属性, 拥有多个标签的点在每个标签的文件中各占一行
+//   - 边: edge_<边类型>.csv, 表头为 src、dst、rank 与边的属性
+//
+// null 输出为空字符串, 日期时间为 ISO 8601 文本, 导出的文件可以直接由 loader 包的 TagSpec/EdgeSpec 导入
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+type CSVWriter struct {
+	open    OpenFunc
+	schema  *Schema
+	files   map[string]*csvFile
+	ordered []*csvFile
+}
+
+// 一个 CSV 输出文件
+type csvFile struct {
+	closer io.Closer
+	writer *csv.Writer
+	props  []Prop
+}
+
+// NewCSVWriter 创建 CSV 写入器, 例如 exporter.NewCSVWriter(exporter.Dir("./out"))
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func NewCSVWriter(open OpenFunc) *CSVWriter {
+	return &CSVWriter{open: open, files: make(map[string]*csvFile)}
+}
+
+// Begin 实现 IWriter 接口, 为每个标签与边类型创建文件并写入表头
+func (w *CSVWriter) Begin(schema *Schema) error {
+	w.schema = schema
+	for _, item := range schema.Tags {
+		if err := w.create("vertex_"+item.Name+".csv", []string{"vid"}, item.Props); err != nil {
+			return err
+		}
+	}
+	for _, item := range schema.Edges {
+		if err := w.create("edge_"+item.Name+".csv", []string{"src", "dst", "rank"}, item.Props); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 创建文件并写入表头
+func (w *CSVWriter) create(name string, keys []string, props []Prop) error {
+	out, err := w.open(name)
+	if err != nil {
+		return fmt.Errorf("创建导出文件[%s]失败: %w", name, err)
+	}
+	file := &csvFile{closer: out, writer: csv.NewWriter(out), props: props}
+	w.files[name] = file
+	w.ordered = append(w.ordered, file)
+	header := keys
+	for _, prop := range props {
+		header = append(header, prop.Name)
+	}
+	return file.writer.Write(header)
+}
+
+// WriteVertex 实现 IWriter 接口
+func (w *CSVWriter) WriteVertex(vertex *Vertex) error {
+	for _, tag := range vertex.TagNames(w.schema) {
+		file := w.files["vertex_"+tag+".csv"]
+		if err := file.write([]string{formatText(vertex.Vid)}, vertex.Tags[tag]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteEdge 实现 IWriter 接口
+func (w *CSVWriter) WriteEdge(edge *Edge) error {
+	file, ok := w.files["edge_"+edge.Name+".csv"]
+	if !ok {
+		return fmt.Errorf("边类型[%s]不在导出范围内", edge.Name)
+	}
+	return file.write([]string{formatText(edge.Src), formatText(edge.Dst), formatText(edge.Rank)}, edge.Props)
+}
+
+// End 实现 IWriter 接口, 刷新并关闭所有文件
+func (w *CSVWriter) End() error {
+	var firstErr error
+	for _, file := range w.ordered {
+		file.writer.Flush()
+		if err := file.writer.Error(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if err := file.closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// 按属性定义的顺序写入一行
+func (f *csvFile) write(keys []string, props map[string]interface{}) error {
+	row := keys
+	for _, prop := range f.props {
+		row = append(row, formatText(props[prop.Name]))
+	}
+	return f.writer.Write(row)
+}
@@ -0,0 +1,458 @@
+// Package exporter 将标签、边类型的全量数据或某个点周围的子图导出为 CSV、JSON Lines 或 GraphML,
+// 保留 VID、rank 与属性类型, 全量导出通过 LOOKUP 读取 VID 后按批 FETCH 属性, 不会一次性加载整个图空间的属性。
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+package exporter
+
+import (
+	"context"
+	"fmt"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"nebula-orm-go/constants"
+	"nebula-orm-go/dialectors"
+	"nebula-orm-go/model"
+	"nebula-orm-go/orm"
+	"nebula-orm-go/utils"
+	"strings"
+	"time"
+)
+
+// Option 导出配置项
+type Option func(e *Exporter)
+
+// Exporter 导出器, 可以复用执行多次导出, 每次导出返回独立的报告
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+type Exporter struct {
+	db       *orm.DB
+	ctx      context.Context
+	pageSize int // 每批 fetch 的点边数量
+}
+
+// Report 导出报告
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+type Report struct {
+	Vertexs int64         // 导出的点数量
+	Edges   int64         // 导出的边数量
+	Pages   int           // 执行的查询次数(lookup 与 fetch)
+	Elapsed time.Duration // 导出耗时
+}
+
+// String 返回报告摘要
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func (r *Report) String() string {
+	return fmt.Sprintf("导出点 %d, 导出边 %d, 查询 %d 次, 耗时 %s", r.Vertexs, r.Edges, r.Pages, r.Elapsed)
+}
+
+// New 创建导出器
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func New(db *orm.DB, opts ...Option) *Exporter {
+	e := &Exporter{db: db, ctx: context.Background(), pageSize: constants.DefaultBatchSize}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// WithPageSize 设置每批 fetch 的点边数量, 默认 500
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func WithPageSize(size int) Option {
+	return func(e *Exporter) {
+		if size > 0 {
+			e.pageSize = size
+		}
+	}
+}
+
+// WithContext 设置上下文, 上下文取消后停止查询
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func WithContext(ctx context.Context) Option {
+	return func(e *Exporter) {
+		e.ctx = ctx
+	}
+}
+
+// Export 导出指定标签的全部点与指定边类型的全部边, 每个标签(边类型)先通过一次 LOOKUP 读取全部 VID(边为起点、终点与 rank),
+// 再按 VID(边键)分批 FETCH 属性, 查询次数与数据量成线性关系, 内存中只保留 VID 与当前批次;
+// 拥有多个导出标签的点只导出一次, 包含它的全部导出标签。对应的标签与边类型需要创建索引
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func (e *Exporter) Export(w IWriter, tags []string, edges []string) (report *Report, err error) {
+	start := time.Now()
+	report = &Report{}
+	defer func() {
+		report.Elapsed = time.Since(start)
+	}()
+
+	schema, err := e.describe(tags, edges)
+	if err != nil {
+		return report, err
+	}
+	if err = w.Begin(schema); err != nil {
+		return report, err
+	}
+	defer func() {
+		if endErr := w.End(); err == nil {
+			err = endErr
+		}
+	}()
+
+	for i := range tags {
+		if err = e.exportTag(w, schema, tags, i, report); err != nil {
+			return report, err
+		}
+	}
+	for _, edge := range edges {
+		if err = e.exportEdge(w, edge, report); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// 导出第 i 个标签的点, 按全部导出标签 fetch 以合并点的多个标签, 同时拥有之前标签的点已经导出过, 跳过
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func (e *Exporter) exportTag(w IWriter, schema *Schema, tags []string, i int, report *Report) error {
+	tag := tags[i]
+	result, err := e.page(fmt.Sprintf("lookup on %s yield id(vertex) as vid", tag), report)
+	if err != nil {
+		return fmt.Errorf("导出标签[%s]失败: %w", tag, err)
+	}
+	vids := make([]string, 0, result.GetRowSize())
+	for j := 0; j < result.GetRowSize(); j++ {
+		record, err := result.GetRowValuesByIndex(j)
+		if err != nil {
+			return err
+		}
+		value, err := record.GetValueByIndex(0)
+		if err != nil {
+			return err
+		}
+		vids = append(vids, vidLiteral(toValue(value)))
+	}
+
+	for start := 0; start < len(vids); start += e.pageSize {
+		end := start + e.pageSize
+		if end > len(vids) {
+			end = len(vids)
+		}
+		result, err = e.page(fmt.Sprintf("fetch prop on %s %s yield vertex as v", strings.Join(tags, ","), strings.Join(vids[start:end], ",")), report)
+		if err != nil {
+			return fmt.Errorf("导出标签[%s]失败: %w", tag, err)
+		}
+		for j := 0; j < result.GetRowSize(); j++ {
+			record, err := result.GetRowValuesByIndex(j)
+			if err != nil {
+				return err
+			}
+			node, err := columnNode(record, "v")
+			if err != nil {
+				return err
+			}
+			// LOOKUP 之后被删除标签的点不再导出
+			if !node.HasTag(tag) || hasAnyTag(node, tags[:i]) {
+				continue
+			}
+			vertex, err := toVertex(node, schema)
+			if err != nil {
+				return err
+			}
+			if err = w.WriteVertex(vertex); err != nil {
+				return err
+			}
+			report.Vertexs++
+		}
+	}
+	return nil
+}
+
+// 导出一种边类型的边, 先通过 LOOKUP 读取全部边键 src->dst@rank, 再分批 fetch
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func (e *Exporter) exportEdge(w IWriter, name string, report *Report) error {
+	result, err := e.page(fmt.Sprintf("lookup on %s yield src(edge) as src, dst(edge) as dst, rank(edge) as edge_rank", name), report)
+	if err != nil {
+		return fmt.Errorf("导出边类型[%s]失败: %w", name, err)
+	}
+	keys := make([]string, 0, result.GetRowSize())
+	for i := 0; i < result.GetRowSize(); i++ {
+		record, err := result.GetRowValuesByIndex(i)
+		if err != nil {
+			return err
+		}
+		var values [3]*nebula.ValueWrapper
+		for j := range values {
+			if values[j], err = record.GetValueByIndex(j); err != nil {
+				return err
+			}
+		}
+		rank, err := values[2].AsInt()
+		if err != nil {
+			return err
+		}
+		keys = append(keys, fmt.Sprintf("%s->%s@%d", vidLiteral(toValue(values[0])), vidLiteral(toValue(values[1])), rank))
+	}
+
+	for start := 0; start < len(keys); start += e.pageSize {
+		end := start + e.pageSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		result, err = e.page(fmt.Sprintf("fetch prop on %s %s yield edge as e", name, strings.Join(keys[start:end], ",")), report)
+		if err != nil {
+			return fmt.Errorf("导出边类型[%s]失败: %w", name, err)
+		}
+		for i := 0; i < result.GetRowSize(); i++ {
+			record, err := result.GetRowValuesByIndex(i)
+			if err != nil {
+				return err
+			}
+			value, err := record.GetValueByColName("e")
+			if err != nil {
+				return err
+			}
+			relationship, err := value.AsRelationship()
+			if err != nil {
+				return err
+			}
+			if err = w.WriteEdge(toEdge(relationship)); err != nil {
+				return err
+			}
+			report.Edges++
+		}
+	}
+	return nil
+}
+
+// ExportSubgraph 导出点周围 level 层内经由指定边类型相连的子图, 查询方式与 GetBothAllVertexByVid 相同(上下级双向),
+// 额外导出子图中的边; 子图中出现的标签与边类型都会被导出
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func (e *Exporter) ExportSubgraph(w IWriter, vertex model.IVertex, edge model.IEdge, level int) (report *Report, err error) {
+	start := time.Now()
+	report = &Report{}
+	defer func() {
+		report.Elapsed = time.Since(start)
+	}()
+
+	vid := utils.GetVidWithPolicy(vertex.GetVid(), vertex.GetPolicy())
+	// get subgraph 默认返回原点本身作为第一层, 所以查询 level 至少+1
+	result, err := e.page(fmt.Sprintf("get subgraph with prop %d steps from %s both %s yield vertices as nodes, edges as relationships",
+		level+1, vid, edge.EdgeName()), report)
+	if err != nil {
+		return report, err
+	}
+	nodes, relationships, err := subgraphValues(result)
+	if err != nil {
+		return report, err
+	}
+
+	// 收集子图中的标签与边类型, 按出现顺序描述
+	var tags, edges []string
+	seen := make(map[string]bool)
+	for _, node := range nodes {
+		for _, tag := range node.GetTags() {
+			if !seen["tag:"+tag] {
+				seen["tag:"+tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	referenced := make(map[string]bool)
+	for _, relationship := range relationships {
+		name := relationship.GetEdgeName()
+		if !seen["edge:"+name] {
+			seen["edge:"+name] = true
+			edges = append(edges, name)
+		}
+		src, dst := relationship.GetSrcVertexID(), relationship.GetDstVertexID()
+		referenced[src.String()], referenced[dst.String()] = true, true
+	}
+	schema, err := e.describe(tags, edges)
+	if err != nil {
+		return report, err
+	}
+
+	if err = w.Begin(schema); err != nil {
+		return report, err
+	}
+	defer func() {
+		if endErr := w.End(); err == nil {
+			err = endErr
+		}
+	}()
+	for _, node := range nodes {
+		// 不存在的起点在子图中表现为没有标签的点, 没有边引用时忽略
+		id := node.GetID()
+		if len(node.GetTags()) == 0 && !referenced[id.String()] {
+			continue
+		}
+		v, err := toVertex(node, schema)
+		if err != nil {
+			return report, err
+		}
+		if err = w.WriteVertex(v); err != nil {
+			return report, err
+		}
+		report.Vertexs++
+	}
+	for _, relationship := range relationships {
+		if err = w.WriteEdge(toEdge(relationship)); err != nil {
+			return report, err
+		}
+		report.Edges++
+	}
+	return report, nil
+}
+
+// 解析子图结果中的点与边, 点按 VID 去重, 边按 (边类型, 起点, 终点, rank) 去重
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func subgraphValues(result *dialectors.ResultSet) ([]*nebula.Node, []*nebula.Relationship, error) {
+	var nodes []*nebula.Node
+	var relationships []*nebula.Relationship
+	seen := make(map[string]bool)
+	for i := 0; i < result.GetRowSize(); i++ {
+		record, err := result.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		values, err := columnList(record, "nodes")
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, value := range values {
+			node, err := value.AsNode()
+			if err != nil {
+				return nil, nil, err
+			}
+			id := node.GetID()
+			if key := "v:" + id.String(); !seen[key] {
+				seen[key] = true
+				nodes = append(nodes, node)
+			}
+		}
+		if values, err = columnList(record, "relationships"); err != nil {
+			return nil, nil, err
+		}
+		for _, value := range values {
+			relationship, err := value.AsRelationship()
+			if err != nil {
+				return nil, nil, err
+			}
+			if key := "e:" + toEdge(relationship).key(); !seen[key] {
+				seen[key] = true
+				relationships = append(relationships, relationship)
+			}
+		}
+	}
+	return nodes, relationships, nil
+}
+
+// 通过 describe 获取标签与边类型的属性定义
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func (e *Exporter) describe(tags []string, edges []string) (*Schema, error) {
+	schema := &Schema{}
+	for _, tag := range tags {
+		item, err := e.describeItem("tag", tag)
+		if err != nil {
+			return nil, err
+		}
+		schema.Tags = append(schema.Tags, item)
+	}
+	for _, edge := range edges {
+		item, err := e.describeItem("edge", edge)
+		if err != nil {
+			return nil, err
+		}
+		schema.Edges = append(schema.Edges, item)
+	}
+	return schema, nil
+}
+
+func (e *Exporter) describeItem(kind string, name string) (*SchemaItem, error) {
+	result, err := e.db.WithContext(e.ctx).Execute(fmt.Sprintf("describe %s %s", kind, name))
+	if err != nil {
+		return nil, fmt.Errorf("获取%s[%s]的定义失败: %w", kind, name, err)
+	}
+	item := &SchemaItem{Name: name}
+	for i := 0; i < result.GetRowSize(); i++ {
+		record, err := result.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		field, err := columnString(record, "Field")
+		if err != nil {
+			return nil, err
+		}
+		typ, err := columnString(record, "Type")
+		if err != nil {
+			return nil, err
+		}
+		item.Props = append(item.Props, Prop{Name: field, Type: typ})
+	}
+	return item, nil
+}
+
+// 执行一次查询并计数
+func (e *Exporter) page(sql string, report *Report) (*dialectors.ResultSet, error) {
+	if err := e.ctx.Err(); err != nil {
+		return nil, err
+	}
+	report.Pages++
+	return e.db.WithContext(e.ctx).Execute(sql)
+}
+
+func columnNode(record *nebula.Record, column string) (*nebula.Node, error) {
+	value, err := record.GetValueByColName(column)
+	if err != nil {
+		return nil, err
+	}
+	return value.AsNode()
+}
+
+func columnList(record *nebula.Record, column string) ([]nebula.ValueWrapper, error) {
+	value, err := record.GetValueByColName(column)
+	if err != nil {
+		return nil, err
+	}
+	return value.AsList()
+}
+
+func columnString(record *nebula.Record, column string) (string, error) {
+	value, err := record.GetValueByColName(column)
+	if err != nil {
+		return "", err
+	}
+	return value.AsString()
+}
+
+// 点是否拥有任意一个指定的标签
+func hasAnyTag(node *nebula.Node, tags []string) bool {
+	for _, tag := range tags {
+		if node.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package exporter
+
+import (
+	"bytes"
+	"nebula-orm-go/config"
+	"nebula-orm-go/dialectors/memory"
+	"nebula-orm-go/orm"
+	"strings"
+	"testing"
+)
+
+// 测试用图空间: 点 c 同时拥有 person 与 animal 标签, 字符串 VID 含引号与反斜杠
+const testInitSql = "create space test(vid_type=FIXED_STRING(32)); use test; " +
+	"create tag person(name string, age int); create tag animal(legs int); create edge follow(degree double); " +
+	"create tag index person_index on person(); create tag index animal_index on animal(); create edge index follow_index on follow(); " +
+	`insert vertex person(name, age) values 'a':('甲', 30), 'b\'1':('乙', NULL), 'c\\2':('丙', 40); ` +
+	`insert vertex animal(legs) values 'c\\2':(4), 'd':(2); ` +
+	`insert edge follow(degree) values 'a'->'b\'1':(0.5), 'a'->'b\'1'@1:(1.0), 'b\'1'->'c\\2':(0.8)`
+
+// 使用内存拨号器创建导出器
+func newTestExporter(t *testing.T, opts ...Option) *Exporter {
+	t.Helper()
+	dialer, err := memory.New(memory.WithInitSql(testInitSql))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := orm.Open(dialer, config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(db, opts...)
+}
+
+func TestExport(t *testing.T) {
+	e := newTestExporter(t, WithPageSize(2))
+	var out bytes.Buffer
+	report, err := e.Export(NewJSONLWriter(&out), []string{"person", "animal"}, []string{"follow"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 每个标签(边类型)一次 lookup, 之后每 2 个一次 fetch: person 3 个, animal 2 个, follow 3 条
+	if report.Vertexs != 4 || report.Edges != 3 || report.Pages != 3+2+1+2 {
+		t.Fatalf("report = %s", report)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := map[string]bool{
+		`{"type":"vertex","vid":"a","tags":{"person":{"age":30,"name":"甲"}}}`:                        true,
+		`{"type":"vertex","vid":"b'1","tags":{"person":{"age":null,"name":"乙"}}}`:                    true,
+		`{"type":"vertex","vid":"c\\2","tags":{"animal":{"legs":4},"person":{"age":40,"name":"丙"}}}`: true,
+		`{"type":"vertex","vid":"d","tags":{"animal":{"legs":2}}}`:                                   true,
+		`{"type":"edge","edge":"follow","src":"a","dst":"b'1","rank":0,"props":{"degree":0.5}}`:      true,
+		`{"type":"edge","edge":"follow","src":"a","dst":"b'1","rank":1,"props":{"degree":1}}`:        true,
+		`{"type":"edge","edge":"follow","src":"b'1","dst":"c\\2","rank":0,"props":{"degree":0.8}}`:   true,
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("导出 %d 行, 期望 %d 行:\n%s", len(lines), len(want), out.String())
+	}
+	for _, line := range lines {
+		if !want[line] {
+			t.Errorf("意外的导出行 %s", line)
+		}
+	}
+}
+
+func TestExportWithoutIndex(t *testing.T) {
+	e := newTestExporter(t)
+	if _, err := e.db.Execute("drop tag index animal_index"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := e.Export(NewJSONLWriter(&bytes.Buffer{}), []string{"animal"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "导出标签[animal]失败") {
+		t.Fatalf("Export() error = %v", err)
+	}
+}
+
+func TestVidLiteral(t *testing.T) {
+	tests := []struct {
+		vid  interface{}
+		want string
+	}{
+		{vid: "a", want: "'a'"},
+		{vid: `b'1\`, want: `'b\'1\\'`},
+		{vid: "x\ny", want: `'x\ny'`},
+		{vid: int64(-3), want: "-3"},
+	}
+	for _, tt := range tests {
+		if got := vidLiteral(tt.vid); got != tt.want {
+			t.Errorf("vidLiteral(%q) = %s, 期望 %s", tt.vid, got, tt.want)
+		}
+	}
+}
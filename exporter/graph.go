@@ -0,0 +1,228 @@
+package exporter
+
+import (
+	"fmt"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"nebula-orm-go/clause"
+	"strconv"
+	"strings"
+)
+
+// Vertex 导出的点, 同一个点的多个标签合并在一起
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+type Vertex struct {
+	Vid  interface{}                       // 点ID, 整数 VID 为 int64, 否则为 string
+	Tags map[string]map[string]interface{} // 标签名 -> 属性名 -> 属性值
+}
+
+// TagNames 按 Schema 中的顺序返回点拥有的标签
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func (v *Vertex) TagNames(schema *Schema) []string {
+	names := make([]string, 0, len(v.Tags))
+	for _, item := range schema.Tags {
+		if _, ok := v.Tags[item.Name]; ok {
+			names = append(names, item.Name)
+		}
+	}
+	return names
+}
+
+// Edge 导出的边
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+type Edge struct {
+	Name  string                 // 边类型名称
+	Src   interface{}            // 起点ID
+	Dst   interface{}            // 终点ID
+	Rank  int64                  // rank
+	Props map[string]interface{} // 属性名 -> 属性值
+}
+
+// 边的唯一标识
+func (e *Edge) key() string {
+	return fmt.Sprintf("%s:%v->%v@%d", e.Name, e.Src, e.Dst, e.Rank)
+}
+
+// Prop 属性定义
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+type Prop struct {
+	Name string // 属性名
+	Type string // nebula 属性类型, 例如 string、int64、fixed_string(32)、datetime
+}
+
+// SchemaItem 标签或边类型的定义
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+type SchemaItem struct {
+	Name  string // 标签或边类型名称
+	Props []Prop // 属性定义, 与 describe 的顺序一致
+}
+
+// Schema 导出数据涉及的标签与边类型, 写入器据此生成表头或属性声明
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+type Schema struct {
+	Tags  []*SchemaItem
+	Edges []*SchemaItem
+}
+
+// Tag 按名称查找标签定义
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func (s *Schema) Tag(name string) *SchemaItem {
+	return findItem(s.Tags, name)
+}
+
+// Edge 按名称查找边类型定义
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func (s *Schema) Edge(name string) *SchemaItem {
+	return findItem(s.Edges, name)
+}
+
+func findItem(items []*SchemaItem, name string) *SchemaItem {
+	for _, item := range items {
+		if item.Name == name {
+			return item
+		}
+	}
+	return nil
+}
+
+// 将点值转换为导出的点, 只保留 Schema 中存在的标签
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func toVertex(node *nebula.Node, schema *Schema) (*Vertex, error) {
+	id := node.GetID()
+	vertex := &Vertex{Vid: toValue(&id), Tags: make(map[string]map[string]interface{})}
+	for _, tag := range node.GetTags() {
+		if schema.Tag(tag) == nil {
+			continue
+		}
+		props, err := node.Properties(tag)
+		if err != nil {
+			return nil, err
+		}
+		vertex.Tags[tag] = toProps(props)
+	}
+	return vertex, nil
+}
+
+// 将边值转换为导出的边
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func toEdge(relationship *nebula.Relationship) *Edge {
+	src, dst := relationship.GetSrcVertexID(), relationship.GetDstVertexID()
+	return &Edge{
+		Name:  relationship.GetEdgeName(),
+		Src:   toValue(&src),
+		Dst:   toValue(&dst),
+		Rank:  relationship.GetRanking(),
+		Props: toProps(relationship.Properties()),
+	}
+}
+
+func toProps(props map[string]*nebula.ValueWrapper) map[string]interface{} {
+	values := make(map[string]interface{}, len(props))
+	for name, value := range props {
+		values[name] = toValue(value)
+	}
+	return values
+}
+
+// 将 nebula 值转换为可导出的 Go 值: 整数为 int64, 浮点数为 float64, 日期时间为 ISO 8601 字符串, null 为 nil
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func toValue(value *nebula.ValueWrapper) interface{} {
+	switch {
+	case value == nil || value.IsNull() || value.IsEmpty():
+		return nil
+	case value.IsBool():
+		b, _ := value.AsBool()
+		return b
+	case value.IsInt():
+		i, _ := value.AsInt()
+		return i
+	case value.IsFloat():
+		f, _ := value.AsFloat()
+		return f
+	case value.IsString():
+		s, _ := value.AsString()
+		return s
+	case value.IsList() || value.IsSet():
+		var list []nebula.ValueWrapper
+		if value.IsList() {
+			list, _ = value.AsList()
+		} else {
+			list, _ = value.AsDedupList()
+		}
+		values := make([]interface{}, len(list))
+		for i := range list {
+			values[i] = toValue(&list[i])
+		}
+		return values
+	case value.IsMap():
+		m, _ := value.AsMap()
+		values := make(map[string]interface{}, len(m))
+		for k := range m {
+			v := m[k]
+			values[k] = toValue(&v)
+		}
+		return values
+	}
+	// 日期、时间、日期时间、地理位置、时长等按 nebula 的文本格式导出
+	return value.String()
+}
+
+// 将值格式化为文本, 用于 CSV 与 GraphML, null 为空字符串
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func formatText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		// 整数值的浮点数保留小数点, 以便与整数属性区分
+		text := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(text, ".eIN") {
+			text += ".0"
+		}
+		return text
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatText(item)
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	}
+	return fmt.Sprint(value)
+}
+
+// 生成 VID 字面量, 字符串 VID 按 clause.Quote 加引号并转义
+func vidLiteral(vid interface{}) string {
+	if s, ok := vid.(string); ok {
+		return clause.Quote(s)
+	}
+	return fmt.Sprint(vid)
+}
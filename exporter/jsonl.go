@@ -0,0 +1,76 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// JSONLWriter 输出 JSON Lines, 每行一个点或边:
+//
+//	{"type":"vertex","vid":"a","tags":{"test_vertex":{"chain_key":"a"}}}
+//	{"type":"edge","edge":"test_edge","src":"a","dst":"b","rank":0,"props":{"test":"x"}}
+//
+// 属性保留类型: 整数、浮点数、布尔值为 JSON 的数字与布尔值, null 为 null, 日期时间为 ISO 8601 字符串
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+type JSONLWriter struct {
+	out     io.Writer
+	buf     *bufio.Writer
+	encoder *json.Encoder
+}
+
+// JSON Lines 中的一行点
+type jsonlVertex struct {
+	Type string                            `json:"type"`
+	Vid  interface{}                       `json:"vid"`
+	Tags map[string]map[string]interface{} `json:"tags"`
+}
+
+// JSON Lines 中的一行边
+type jsonlEdge struct {
+	Type  string                 `json:"type"`
+	Edge  string                 `json:"edge"`
+	Src   interface{}            `json:"src"`
+	Dst   interface{}            `json:"dst"`
+	Rank  int64                  `json:"rank"`
+	Props map[string]interface{} `json:"props"`
+}
+
+// NewJSONLWriter 创建 JSON Lines 写入器, out 实现 io.Closer 时 End 会关闭它
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func NewJSONLWriter(out io.Writer) *JSONLWriter {
+	buf := bufio.NewWriter(out)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	return &JSONLWriter{out: out, buf: buf, encoder: encoder}
+}
+
+// Begin 实现 IWriter 接口
+func (w *JSONLWriter) Begin(*Schema) error {
+	return nil
+}
+
+// WriteVertex 实现 IWriter 接口
+func (w *JSONLWriter) WriteVertex(vertex *Vertex) error {
+	return w.encoder.Encode(jsonlVertex{Type: "vertex", Vid: vertex.Vid, Tags: vertex.Tags})
+}
+
+// WriteEdge 实现 IWriter 接口
+func (w *JSONLWriter) WriteEdge(edge *Edge) error {
+	return w.encoder.Encode(jsonlEdge{Type: "edge", Edge: edge.Name, Src: edge.Src, Dst: edge.Dst, Rank: edge.Rank, Props: edge.Props})
+}
+
+// End 实现 IWriter 接口
+func (w *JSONLWriter) End() error {
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	if closer, ok := w.out.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
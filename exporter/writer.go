@@ -0,0 +1,37 @@
+package exporter
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// IWriter 导出写入器, 导出器先调用 Begin 传入涉及的标签与边类型, 再逐个写入点边, 最后调用 End
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+type IWriter interface {
+	Begin(schema *Schema) error
+	WriteVertex(vertex *Vertex) error
+	WriteEdge(edge *Edge) error
+	End() error
+}
+
+// OpenFunc 按名称打开输出流, 用于需要输出多个文件的写入器
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+type OpenFunc func(name string) (io.WriteCloser, error)
+
+// Dir 在目录下创建文件作为输出流, 目录不存在时自动创建
+//
+// @Author: 罗德
+// @Date: 2024/6/29
+func Dir(dir string) OpenFunc {
+	return func(name string) (io.WriteCloser, error) {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		return os.Create(filepath.Join(dir, name))
+	}
+}
@@ -0,0 +1,153 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 含需要转义字符的测试数据
+var (
+	testSchema = &Schema{
+		Tags:  []*SchemaItem{{Name: "person", Props: []Prop{{Name: "name", Type: "string"}, {Name: "age", Type: "int64"}}}},
+		Edges: []*SchemaItem{{Name: "follow", Props: []Prop{{Name: "note", Type: "string"}, {Name: "degree", Type: "double"}}}},
+	}
+	testName   = "a,\"b\"\n<c> & 'd'"
+	testVertex = &Vertex{Vid: `v"1,<2>`, Tags: map[string]map[string]interface{}{"person": {"name": testName, "age": nil}}}
+	testEdge   = &Edge{Name: "follow", Src: `v"1,<2>`, Dst: int64(7), Rank: 3, Props: map[string]interface{}{"note": testName, "degree": float64(2)}}
+)
+
+// 按文件名保存输出的内存文件
+type memoryFiles map[string]*bytes.Buffer
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+func (m memoryFiles) open(name string) (io.WriteCloser, error) {
+	m[name] = &bytes.Buffer{}
+	return nopCloser{m[name]}, nil
+}
+
+// 依次调用写入器的全部方法
+func writeAll(t *testing.T, w IWriter) {
+	t.Helper()
+	if err := w.Begin(testSchema); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteVertex(testVertex); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEdge(testEdge); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.End(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	files := memoryFiles{}
+	writeAll(t, NewCSVWriter(files.open))
+
+	tests := []struct {
+		name string
+		want [][]string
+	}{
+		{name: "vertex_person.csv", want: [][]string{{"vid", "name", "age"}, {`v"1,<2>`, testName, ""}}},
+		{name: "edge_follow.csv", want: [][]string{{"src", "dst", "rank", "note", "degree"}, {`v"1,<2>`, "7", "3", testName, "2.0"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, ok := files[tt.name]
+			if !ok {
+				t.Fatalf("没有输出文件 %s", tt.name)
+			}
+			// 引号与分隔符按 RFC 4180 转义, 可以原样读回
+			records, err := csv.NewReader(strings.NewReader(file.String())).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(records, tt.want) {
+				t.Errorf("读回 %q, 期望 %q", records, tt.want)
+			}
+		})
+	}
+	if !strings.Contains(files["vertex_person.csv"].String(), `"v""1,<2>"`) {
+		t.Errorf("VID 未按 CSV 转义:\n%s", files["vertex_person.csv"])
+	}
+}
+
+func TestJSONLWriter(t *testing.T) {
+	var out bytes.Buffer
+	writeAll(t, NewJSONLWriter(&out))
+
+	want := `{"type":"vertex","vid":"v\"1,<2>","tags":{"person":{"age":null,"name":"a,\"b\"\n<c> & 'd'"}}}` + "\n" +
+		`{"type":"edge","edge":"follow","src":"v\"1,<2>","dst":7,"rank":3,"props":{"degree":2,"note":"a,\"b\"\n<c> & 'd'"}}` + "\n"
+	if out.String() != want {
+		t.Errorf("输出:\n%s\n期望:\n%s", out.String(), want)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var row map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			t.Fatalf("解析 %s 失败: %v", line, err)
+		}
+	}
+}
+
+func TestGraphMLWriter(t *testing.T) {
+	var out bytes.Buffer
+	writeAll(t, NewGraphMLWriter(&out))
+
+	for _, want := range []string{
+		`<key id="person.age" for="node" attr.name="person.age" attr.type="long"/>`,
+		`<key id="follow.degree" for="edge" attr.name="follow.degree" attr.type="double"/>`,
+		`<node id="v&#34;1,&lt;2&gt;">`,
+		`<data key="person.name">a,&#34;b&#34;&#xA;&lt;c&gt; &amp; &#39;d&#39;</data>`,
+		`<edge id="follow:v&#34;1,&lt;2&gt;-&gt;7@3" source="v&#34;1,&lt;2&gt;" target="7">`,
+		`<data key="rank">3</data>`,
+		`<data key="follow.degree">2.0</data>`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("输出中没有 %s:\n%s", want, out.String())
+		}
+	}
+	// null 属性不输出
+	if strings.Contains(out.String(), `key="person.age"`) {
+		t.Errorf("null 属性不应输出:\n%s", out.String())
+	}
+
+	// 输出是合法的 XML, 转义的值可以原样读回
+	var doc struct {
+		Nodes []struct {
+			ID   string `xml:"id,attr"`
+			Data []struct {
+				Key   string `xml:"key,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"data"`
+		} `xml:"graph>node"`
+	}
+	if err := xml.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Nodes) != 1 || doc.Nodes[0].ID != testVertex.Vid || doc.Nodes[0].Data[1].Value != testName {
+		t.Errorf("读回 %+v", doc.Nodes)
+	}
+}
+
+func TestWriteEdgeOutsideSchema(t *testing.T) {
+	writers := map[string]IWriter{"csv": NewCSVWriter(memoryFiles{}.open), "graphml": NewGraphMLWriter(&bytes.Buffer{})}
+	for name, w := range writers {
+		if err := w.Begin(testSchema); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WriteEdge(&Edge{Name: "like"}); err == nil || !strings.Contains(err.Error(), "边类型[like]不在导出范围内") {
+			t.Errorf("%s WriteEdge() error = %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package nebula_orm_go
+
+import (
+	"nebula-orm-go/clause"
+)
+
+// Expr 创建带 ? 占位符的更新表达式, 参数按类型编码并转义, 作为 Updates/Upserts 的属性值使用。
+// db.Model(vertex).Updates(map[string]interface{}{"cnt": nebula_orm_go.Expr("cnt * ? + ?", 2, 1)})
+//
+// @Author: 罗德
+// @Date: 2024/7/2
+func Expr(sql string, vars ...interface{}) clause.Expression {
+	return clause.Expr(sql, vars...)
+}
+
+// Inc 属性自增, 生成 prop = coalesce(prop, 0) + n
+//
+// @Author: 罗德
+// @Date: 2024/7/2
+func Inc(n interface{}) clause.IExpression {
+	return clause.Inc(n)
+}
+
+// Dec 属性自减, 生成 prop = coalesce(prop, 0) - n
+//
+// @Author: 罗德
+// @Date: 2024/7/2
+func Dec(n interface{}) clause.IExpression {
+	return clause.Dec(n)
+}
+
+// Append 向列表属性追加元素, 生成 prop = coalesce(prop, []) + [values...], nebula 3.x 的标签属性不支持列表类型
+//
+// @Author: 罗德
+// @Date: 2024/7/2
+func Append(values ...interface{}) clause.IExpression {
+	return clause.Append(values...)
+}
+
+// Coalesce 属性为 null 时依次使用候选值, 生成 prop = coalesce(prop, values...)
+//
+// @Author: 罗德
+// @Date: 2024/7/2
+func Coalesce(values ...interface{}) clause.IExpression {
+	return clause.Coalesce(values...)
+}
@@ -0,0 +1,371 @@
+// Package loader 从 CSV、JSON Lines 文件批量导入点边: 流式读取记录, 通过 IMapper 映射为点边模型或标签属性,
+// 按批次经 orm.DB 写入, 并报告行级错误、吞吐量与可用于断点续传的偏移。
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+package loader
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"nebula-orm-go/constants"
+	"nebula-orm-go/model"
+	"nebula-orm-go/orm"
+	"sort"
+	"time"
+)
+
+// Option 导入配置项
+type Option func(l *Loader)
+
+// Loader 批量导入器, 可以复用执行多次导入, 每次导入返回独立的报告
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type Loader struct {
+	db           *orm.DB
+	ctx          context.Context
+	batchSize    int              // 每批写入的记录行数
+	maxErrors    int              // 错误行数上限, 超过后停止导入, <=0 表示不限制
+	resume       Checkpoint       // 从断点继续导入
+	onCheckpoint func(Checkpoint) // 每批提交后的回调, 用于持久化断点
+	comma        rune             // CSV 分隔符
+	columns      []string         // CSV 列名, 指定后不再读取表头
+	lazyQuotes   bool             // CSV 是否允许不规范的引号
+}
+
+// New 创建批量导入器
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func New(db *orm.DB, opts ...Option) *Loader {
+	l := &Loader{
+		db:           db,
+		ctx:          context.Background(),
+		batchSize:    constants.DefaultBatchSize,
+		onCheckpoint: func(Checkpoint) {},
+		comma:        ',',
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// WithBatchSize 设置每批写入的记录行数, 默认 500
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func WithBatchSize(size int) Option {
+	return func(l *Loader) {
+		if size > 0 {
+			l.batchSize = size
+		}
+	}
+}
+
+// WithMaxErrors 设置错误行数上限, 超过后停止导入并返回错误, 默认不限制
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func WithMaxErrors(max int) Option {
+	return func(l *Loader) {
+		l.maxErrors = max
+	}
+}
+
+// WithResume 从断点继续导入, 数据源支持 Seek(例如 *os.File)时直接定位到断点的字节偏移, 否则逐行跳过断点前的记录
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func WithResume(checkpoint Checkpoint) Option {
+	return func(l *Loader) {
+		l.resume = checkpoint
+	}
+}
+
+// WithCheckpointFunc 设置每批提交后的回调, 可以将断点持久化, 导入中断后通过 WithResume 继续
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func WithCheckpointFunc(fn func(checkpoint Checkpoint)) Option {
+	return func(l *Loader) {
+		l.onCheckpoint = fn
+	}
+}
+
+// WithContext 设置上下文, 上下文取消后停止读取并返回已提交的断点
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func WithContext(ctx context.Context) Option {
+	return func(l *Loader) {
+		l.ctx = ctx
+	}
+}
+
+// WithComma 设置 CSV 分隔符, 默认为逗号
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func WithComma(comma rune) Option {
+	return func(l *Loader) {
+		l.comma = comma
+	}
+}
+
+// WithColumns 指定 CSV 列名, 用于没有表头的文件
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func WithColumns(columns ...string) Option {
+	return func(l *Loader) {
+		l.columns = columns
+	}
+}
+
+// WithLazyQuotes 允许 CSV 字段中出现不规范的引号
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func WithLazyQuotes() Option {
+	return func(l *Loader) {
+		l.lazyQuotes = true
+	}
+}
+
+// LoadCSV 从 CSV 导入, 默认第一行为表头
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func (l *Loader) LoadCSV(r io.Reader, mapper IMapper) (*Report, error) {
+	src, seeked, err := newCsvSource(r, l)
+	if err != nil {
+		return &Report{Checkpoint: l.resume}, err
+	}
+	return l.load(src, seeked, mapper)
+}
+
+// LoadJSONL 从 JSON Lines 导入, 每行一个 JSON 对象
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func (l *Loader) LoadJSONL(r io.Reader, mapper IMapper) (*Report, error) {
+	src, seeked, err := newJsonlSource(r, l)
+	if err != nil {
+		return &Report{Checkpoint: l.resume}, err
+	}
+	return l.load(src, seeked, mapper)
+}
+
+// 创建 CSV 读取器, 列数由导入器按表头校验
+func (l *Loader) newCsvReader(r io.Reader) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = l.comma
+	reader.LazyQuotes = l.lazyQuotes
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = false
+	return reader
+}
+
+// 待写入的映射结果及其所在行
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type pendingItem struct {
+	line int64
+	item interface{}
+}
+
+// 读取记录并按批次写入, seeked 表示数据源已经定位到断点, 否则需要逐行跳过断点前的记录
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func (l *Loader) load(src source, seeked bool, mapper IMapper) (*Report, error) {
+	start := time.Now()
+	report := &Report{Checkpoint: l.resume}
+	defer func() {
+		report.Elapsed = time.Since(start)
+		sort.SliceStable(report.Errors, func(i, j int) bool {
+			return report.Errors[i].Line < report.Errors[j].Line
+		})
+	}()
+
+	skipUntil := int64(0)
+	if !seeked {
+		skipUntil = l.resume.Line
+	}
+	var pending []pendingItem
+	records := 0
+	read := l.resume
+
+	// 提交当前批次并推进断点
+	commit := func() error {
+		if records > 0 {
+			l.flush(report, pending)
+			report.Batches++
+			pending, records = pending[:0], 0
+		}
+		if read != report.Checkpoint {
+			report.Checkpoint = read
+			l.onCheckpoint(read)
+		}
+		return l.checkErrors(report)
+	}
+
+	for {
+		if err := l.ctx.Err(); err != nil {
+			return report, err
+		}
+		record, offset, err := src.next()
+		if err == io.EOF {
+			break
+		}
+		if lineErr, ok := err.(*LineError); ok {
+			if lineErr.Line > skipUntil {
+				report.Errors = append(report.Errors, lineErr)
+				if err = l.checkErrors(report); err != nil {
+					return report, err
+				}
+			}
+			read = Checkpoint{Line: lineErr.Line, Offset: offset}
+			continue
+		}
+		if err != nil {
+			return report, fmt.Errorf("读取第%d行之后的数据失败: %w", read.Line, err)
+		}
+		read = Checkpoint{Line: record.Line, Offset: offset}
+		if record.Line <= skipUntil {
+			report.Skipped++
+			continue
+		}
+
+		report.Lines++
+		items, err := mapper.Map(record)
+		if err != nil {
+			report.Errors = append(report.Errors, &LineError{Line: record.Line, Err: err})
+			if err = l.checkErrors(report); err != nil {
+				return report, err
+			}
+			continue
+		}
+		for _, item := range items {
+			pending = append(pending, pendingItem{line: record.Line, item: item})
+		}
+		records++
+		if records >= l.batchSize {
+			if err = commit(); err != nil {
+				return report, err
+			}
+		}
+	}
+	return report, commit()
+}
+
+// 错误行数超过上限时返回错误
+func (l *Loader) checkErrors(report *Report) error {
+	if l.maxErrors > 0 && len(report.Errors) > l.maxErrors {
+		return fmt.Errorf("错误行数%d超过上限%d, 停止导入: %w", len(report.Errors), l.maxErrors, report.Errors[len(report.Errors)-1])
+	}
+	return nil
+}
+
+// 写入一个批次: 先写点再写边, 同类数据按模型(或标签属性列)分组后每组一条语句;
+// 某组写入失败时逐条重写该组, 以定位出错的行
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func (l *Loader) flush(report *Report, pending []pendingItem) {
+	db := l.db.WithContext(l.ctx)
+	var vertexs, edges []pendingItem
+	var vertexRows, edgeRows []pendingItem
+	for _, p := range pending {
+		switch item := p.item.(type) {
+		case model.IVertex:
+			vertexs = append(vertexs, p)
+		case model.IEdge:
+			edges = append(edges, p)
+		case *propRow:
+			if item.vertex {
+				vertexRows = append(vertexRows, p)
+			} else {
+				edgeRows = append(edgeRows, p)
+			}
+		default:
+			report.Errors = append(report.Errors, &LineError{Line: p.line, Err: fmt.Errorf("不支持的映射结果类型 %T", p.item)})
+		}
+	}
+
+	if len(vertexs) > 0 {
+		batch := make([]model.IVertex, len(vertexs))
+		for i, p := range vertexs {
+			batch[i] = p.item.(model.IVertex)
+		}
+		if err := db.InsertVertexGrouped(batch); err == nil {
+			report.Vertexs += int64(len(batch))
+		} else {
+			for _, p := range vertexs {
+				l.record(report, p, &report.Vertexs, db.InsertVertex(p.item.(model.IVertex)))
+			}
+		}
+	}
+	l.flushRows(db, report, vertexRows, &report.Vertexs)
+
+	if len(edges) > 0 {
+		batch := make([]model.IEdge, len(edges))
+		for i, p := range edges {
+			batch[i] = p.item.(model.IEdge)
+		}
+		if err := db.InsertEdgeGrouped(batch); err == nil {
+			report.Edges += int64(len(batch))
+		} else {
+			for _, p := range edges {
+				l.record(report, p, &report.Edges, db.InsertEdge(p.item.(model.IEdge)))
+			}
+		}
+	}
+	l.flushRows(db, report, edgeRows, &report.Edges)
+}
+
+// 写入 TagSpec/EdgeSpec 生成的属性行, 按标签(边类型)与属性列分组
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func (l *Loader) flushRows(db *orm.DB, report *Report, pending []pendingItem, counter *int64) {
+	var keys []string
+	groups := make(map[string][]pendingItem)
+	for _, p := range pending {
+		key := p.item.(*propRow).key
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], p)
+	}
+
+	for _, key := range keys {
+		group := groups[key]
+		rows := make([]*propRow, len(group))
+		for i, p := range group {
+			rows[i] = p.item.(*propRow)
+		}
+		if _, err := db.Execute(propRowsSql(rows)); err == nil {
+			*counter += int64(len(rows))
+			continue
+		}
+		for i, p := range group {
+			_, err := db.Execute(propRowsSql(rows[i : i+1]))
+			l.record(report, p, counter, err)
+		}
+	}
+}
+
+// 记录单条写入的结果
+func (l *Loader) record(report *Report, p pendingItem, counter *int64, err error) {
+	if err != nil {
+		report.Errors = append(report.Errors, &LineError{Line: p.line, Err: err})
+		return
+	}
+	*counter++
+}
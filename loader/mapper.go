@@ -0,0 +1,307 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"nebula-orm-go/constants"
+	"nebula-orm-go/model"
+	"nebula-orm-go/utils"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IMapper 将一行记录映射为待写入的点边, 一行可以映射为多个点边(例如同时写入边及其两端的点),
+// 返回值的元素为 model.IVertex、model.IEdge, 或者 TagSpec/EdgeSpec 生成的属性行
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type IMapper interface {
+	Map(record Record) ([]interface{}, error)
+}
+
+// MapperFunc 函数形式的映射器
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type MapperFunc func(record Record) ([]interface{}, error)
+
+// Map 实现 IMapper 接口
+func (f MapperFunc) Map(record Record) ([]interface{}, error) {
+	return f(record)
+}
+
+// Mappers 依次使用多个映射器映射同一行记录, 合并映射结果
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type Mappers []IMapper
+
+// Map 实现 IMapper 接口
+func (m Mappers) Map(record Record) ([]interface{}, error) {
+	var items []interface{}
+	for _, mapper := range m {
+		mapped, err := mapper.Map(record)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, mapped...)
+	}
+	return items, nil
+}
+
+// VertexMapping 将记录映射到点模型, 属性按结构体字段的 nebula 标签与列名对应
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type VertexMapping struct {
+	New     func() model.IVertex // 创建点模型, 必须返回嵌入 model.VModel 的结构体指针
+	Vid     string               // VID 所在的列
+	IntVid  bool                 // VID 是否为整数(图空间 vid_type 为 INT64)
+	Policy  constants.Policy     // VID 策略
+	Columns map[string]string    // 列名 -> 属性名, 未配置的列按同名属性映射, 映射为空字符串的列会被忽略
+}
+
+// Map 实现 IMapper 接口
+func (m VertexMapping) Map(record Record) ([]interface{}, error) {
+	vertex := m.New()
+	val := reflect.ValueOf(vertex)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("VertexMapping.New 必须返回结构体指针, 实际为 %T", vertex)
+	}
+	vid, err := recordVid(record, m.Vid, m.IntVid)
+	if err != nil {
+		return nil, err
+	}
+	if err = setNamedField(val.Elem(), "Vid", vid); err != nil {
+		return nil, err
+	}
+	if err = setNamedField(val.Elem(), "Policy", m.Policy); err != nil {
+		return nil, err
+	}
+	if err = fillStruct(val.Elem(), record, m.Columns); err != nil {
+		return nil, err
+	}
+	return []interface{}{vertex}, nil
+}
+
+// EdgeMapping 将记录映射到边模型, 属性按结构体字段的 nebula 标签与列名对应
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type EdgeMapping struct {
+	New       func() model.IEdge // 创建边模型, 必须返回嵌入 model.EModel 的结构体指针
+	Src       string             // 起点 VID 所在的列
+	Dst       string             // 终点 VID 所在的列
+	IntVid    bool               // VID 是否为整数(图空间 vid_type 为 INT64)
+	SrcPolicy constants.Policy   // 起点 VID 策略
+	DstPolicy constants.Policy   // 终点 VID 策略
+	Columns   map[string]string  // 列名 -> 属性名, 未配置的列按同名属性映射, 映射为空字符串的列会被忽略
+}
+
+// Map 实现 IMapper 接口
+func (m EdgeMapping) Map(record Record) ([]interface{}, error) {
+	edge := m.New()
+	val := reflect.ValueOf(edge)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("EdgeMapping.New 必须返回结构体指针, 实际为 %T", edge)
+	}
+	src, err := recordVid(record, m.Src, m.IntVid)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := recordVid(record, m.Dst, m.IntVid)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{"Src": src, "Dst": dst, "SrcPolicy": m.SrcPolicy, "DstPolicy": m.DstPolicy}
+	for name, value := range fields {
+		if err = setNamedField(val.Elem(), name, value); err != nil {
+			return nil, err
+		}
+	}
+	if err = fillStruct(val.Elem(), record, m.Columns); err != nil {
+		return nil, err
+	}
+	return []interface{}{edge}, nil
+}
+
+// 读取 VID 列, 整数 VID 需要能解析为 int64
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func recordVid(record Record, column string, intVid bool) (interface{}, error) {
+	value, ok := record.Get(column)
+	if !ok || value == nil || record.String(column) == "" {
+		return nil, fmt.Errorf("VID 列[%s]为空", column)
+	}
+	if !intVid {
+		return record.String(column), nil
+	}
+	vid, err := strconv.ParseInt(strings.TrimSpace(record.String(column)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("VID 列[%s]的值[%s]不是整数", column, record.String(column))
+	}
+	return vid, nil
+}
+
+// 设置嵌入的 VModel/EModel 字段
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func setNamedField(val reflect.Value, name string, value interface{}) error {
+	field := val.FieldByName(name)
+	if !field.IsValid() || !field.CanSet() {
+		return fmt.Errorf("%s 缺少可设置的字段 %s, 请嵌入 model.VModel 或 model.EModel", val.Type(), name)
+	}
+	field.Set(reflect.ValueOf(value).Convert(field.Type()))
+	return nil
+}
+
+// 按 nebula 标签将记录的列填充到结构体字段, 没有对应属性的列(例如 VID 列)会被忽略, 显式映射到不存在的属性时返回错误
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func fillStruct(val reflect.Value, record Record, columns map[string]string) error {
+	typ := val.Type()
+	props := make(map[string]int, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		tag := utils.GetPropName(typ.Field(i))
+		if tag != "" {
+			props[tag] = i
+		}
+	}
+
+	for column, value := range record.Values {
+		prop, ok := columns[column]
+		if !ok {
+			prop = column
+		}
+		i, found := props[prop]
+		if prop == "" || !found {
+			if !ok || prop == "" {
+				continue
+			}
+			return fmt.Errorf("列[%s]映射的属性[%s]在 %s 中不存在", column, prop, typ)
+		}
+		if err := setField(val.Field(i), value); err != nil {
+			return fmt.Errorf("列[%s]: %w", column, err)
+		}
+	}
+	return nil
+}
+
+// 将记录中的值转换为字段类型, 字符串按字段类型解析, 空字符串与null保持零值
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func setField(field reflect.Value, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	text, isText := value.(string)
+	if number, ok := value.(json.Number); ok {
+		text, isText = number.String(), true
+	}
+	if isText && text == "" && field.Kind() != reflect.String {
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		if isText {
+			field.SetString(text)
+		} else {
+			field.SetString(fmt.Sprint(value))
+		}
+		return nil
+	case reflect.Bool:
+		if b, ok := value.(bool); ok {
+			field.SetBool(b)
+			return nil
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			return fmt.Errorf("值[%v]不是布尔值", value)
+		}
+		field.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.Type() == reflect.TypeOf(time.Duration(0)) && isText {
+			d, err := time.ParseDuration(strings.TrimSpace(text))
+			if err != nil {
+				return fmt.Errorf("值[%v]不是时长", value)
+			}
+			field.SetInt(int64(d))
+			return nil
+		}
+		if f, ok := value.(float64); ok {
+			text = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		i, err := strconv.ParseInt(strings.TrimSpace(text), 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("值[%v]不是%s整数或超出范围", value, field.Type())
+		}
+		field.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if f, ok := value.(float64); ok {
+			text = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		u, err := strconv.ParseUint(strings.TrimSpace(text), 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("值[%v]不是%s整数或超出范围", value, field.Type())
+		}
+		field.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		if f, ok := value.(float64); ok {
+			field.SetFloat(f)
+			return nil
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(text), field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("值[%v]不是浮点数", value)
+		}
+		field.SetFloat(f)
+		return nil
+	}
+
+	if field.Type() == reflect.TypeOf(time.Time{}) && isText {
+		t, err := parseTime(text)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Type().ConvertibleTo(field.Type()) {
+		field.Set(rv.Convert(field.Type()))
+		return nil
+	}
+	return fmt.Errorf("不支持将%T转换为%s", value, field.Type())
+}
+
+// 支持的时间格式
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// 解析时间字符串
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func parseTime(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("值[%s]不是支持的时间格式", text)
+}
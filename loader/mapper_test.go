@@ -0,0 +1,148 @@
+package loader
+
+import (
+	"encoding/json"
+	"nebula-orm-go/constants"
+	"nebula-orm-go/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 测试用点
+type testPerson struct {
+	model.VModel
+	Name     string        `nebula:"name"`
+	Age      int8          `nebula:"age"`
+	Score    float64       `nebula:"score"`
+	Active   bool          `nebula:"active"`
+	Birthday time.Time     `nebula:"birthday"`
+	Timeout  time.Duration `nebula:"timeout"`
+	Ignored  string        `nebula:"-"`
+}
+
+func (p *testPerson) TagName() string {
+	return "person"
+}
+
+// 测试用边
+type testFollow struct {
+	model.EModel
+	Degree float64 `nebula:"degree"`
+}
+
+func (f *testFollow) EdgeName() string {
+	return "follow"
+}
+
+func TestVertexMapping(t *testing.T) {
+	mapping := VertexMapping{
+		New:     func() model.IVertex { return &testPerson{} },
+		Vid:     "id",
+		Policy:  constants.PolicyHash,
+		Columns: map[string]string{"nick": "name", "id": ""},
+	}
+	record := Record{Line: 1, Values: map[string]interface{}{
+		"id":       "p1",
+		"nick":     "Tom",
+		"age":      " 30 ",
+		"score":    json.Number("9.5"),
+		"active":   true,
+		"birthday": "2024-06-28",
+		"timeout":  "1m",
+		"extra":    "ignored",
+	}}
+	items, err := mapping.Map(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	person := items[0].(*testPerson)
+	want := testPerson{
+		VModel:   model.VModel{Vid: "p1", Policy: constants.PolicyHash},
+		Name:     "Tom",
+		Age:      30,
+		Score:    9.5,
+		Active:   true,
+		Birthday: time.Date(2024, 6, 28, 0, 0, 0, 0, time.UTC),
+		Timeout:  time.Minute,
+	}
+	if *person != want {
+		t.Errorf("Map() = %+v, 期望 %+v", *person, want)
+	}
+}
+
+func TestVertexMappingErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping VertexMapping
+		values  map[string]interface{}
+		err     string
+	}{
+		{
+			name:    "非结构体指针",
+			mapping: VertexMapping{New: func() model.IVertex { return model.VModel{} }, Vid: "id"},
+			values:  map[string]interface{}{"id": "1"},
+			err:     "必须返回结构体指针",
+		},
+		{
+			name:    "整数 VID",
+			mapping: VertexMapping{New: func() model.IVertex { return &testPerson{} }, Vid: "id", IntVid: true},
+			values:  map[string]interface{}{"id": "x"},
+			err:     "不是整数",
+		},
+		{
+			name:    "映射到不存在的属性",
+			mapping: VertexMapping{New: func() model.IVertex { return &testPerson{} }, Vid: "id", Columns: map[string]string{"nick": "nickname"}},
+			values:  map[string]interface{}{"id": "1", "nick": "a"},
+			err:     "属性[nickname]在",
+		},
+		{
+			name:    "整数溢出",
+			mapping: VertexMapping{New: func() model.IVertex { return &testPerson{} }, Vid: "id"},
+			values:  map[string]interface{}{"id": "1", "age": "300"},
+			err:     "列[age]: 值[300]不是int8整数或超出范围",
+		},
+		{
+			name:    "时间格式",
+			mapping: VertexMapping{New: func() model.IVertex { return &testPerson{} }, Vid: "id"},
+			values:  map[string]interface{}{"id": "1", "birthday": "28/06/2024"},
+			err:     "不是支持的时间格式",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.mapping.Map(Record{Line: 1, Values: tt.values})
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("Map() error = %v, 期望包含 %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestEdgeMapping(t *testing.T) {
+	mapping := Mappers{
+		EdgeMapping{
+			New:    func() model.IEdge { return &testFollow{} },
+			Src:    "from",
+			Dst:    "to",
+			IntVid: true,
+		},
+		MapperFunc(func(record Record) ([]interface{}, error) {
+			return []interface{}{record.String("from")}, nil
+		}),
+	}
+	items, err := mapping.Map(Record{Line: 1, Values: map[string]interface{}{"from": "1", "to": json.Number("2"), "degree": ""}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Map() 返回 %d 项, 期望 2", len(items))
+	}
+	follow := items[0].(*testFollow)
+	if follow.Src != int64(1) || follow.Dst != int64(2) || follow.Degree != 0 {
+		t.Errorf("Map() = %+v", follow)
+	}
+	if items[1] != "1" {
+		t.Errorf("MapperFunc 结果 = %v", items[1])
+	}
+}
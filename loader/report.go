@@ -0,0 +1,117 @@
+package loader
+
+import (
+	"fmt"
+	"time"
+)
+
+// Record 数据文件中的一行记录, CSV 的值均为字符串, JSONL 的值为 JSON 解码后的类型(数字为 json.Number)
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type Record struct {
+	Line   int64                  // 记录所在的行号, 从1开始
+	Values map[string]interface{} // 列名(字段名) -> 值
+}
+
+// Get 获取列的值, 列不存在时第二个返回值为false
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func (r Record) Get(column string) (interface{}, bool) {
+	value, ok := r.Values[column]
+	return value, ok
+}
+
+// String 以字符串形式获取列的值, 列不存在或值为null时返回空字符串
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func (r Record) String(column string) string {
+	value, ok := r.Values[column]
+	if !ok || value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// Checkpoint 断点, 表示 Line 行及之前的记录已经处理完成(写入成功或已记录为错误行),
+// Offset 为下一条记录在文件中的字节偏移, 通过 WithResume 传入即可从断点继续导入
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type Checkpoint struct {
+	Line   int64 `json:"line"`   // 已处理的最后一行的行号
+	Offset int64 `json:"offset"` // 下一条记录的字节偏移
+}
+
+// LineError 行级错误, 包括解析失败、映射失败与写入失败
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type LineError struct {
+	Line int64 // 出错的行号
+	Err  error // 错误原因
+}
+
+// Error 实现 error 接口
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func (e *LineError) Error() string {
+	return fmt.Sprintf("第%d行: %s", e.Line, e.Err.Error())
+}
+
+// Unwrap 返回原始错误
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Report 导入报告
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type Report struct {
+	Lines      int64         // 读取的记录行数(不含表头、空行与断点前跳过的行)
+	Skipped    int64         // 从断点继续时跳过的行数(无法定位字节偏移时逐行跳过)
+	Vertexs    int64         // 写入成功的点数量
+	Edges      int64         // 写入成功的边数量
+	Errors     []*LineError  // 行级错误, 按行号排列
+	Batches    int           // 执行的批次数
+	Elapsed    time.Duration // 导入耗时
+	Checkpoint Checkpoint    // 最后一次提交的断点
+}
+
+// Written 返回写入成功的点边总数
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func (r *Report) Written() int64 {
+	return r.Vertexs + r.Edges
+}
+
+// Throughput 返回每秒写入的点边数量
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func (r *Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Written()) / r.Elapsed.Seconds()
+}
+
+// String 返回报告摘要
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func (r *Report) String() string {
+	return fmt.Sprintf("读取 %d 行, 写入点 %d, 写入边 %d, 错误 %d 行, 批次 %d, 耗时 %s, 吞吐 %.1f/s, 断点 行%d@%d",
+		r.Lines, r.Vertexs, r.Edges, len(r.Errors), r.Batches, r.Elapsed, r.Throughput(), r.Checkpoint.Line, r.Checkpoint.Offset)
+}
@@ -0,0 +1,141 @@
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// 数据源, 逐条读取记录, 读取完毕时返回 io.EOF, 单行解析失败时返回 *LineError 且可以继续读取
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type source interface {
+	// next 返回记录与下一条记录的字节偏移
+	next() (Record, int64, error)
+}
+
+// CSV 数据源
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type csvSource struct {
+	reader  *csv.Reader
+	columns []string
+	line    int64 // 起始行号, 从断点继续时为断点的行号
+	offset  int64 // 起始字节偏移, 从断点继续时为断点的偏移
+}
+
+// 创建 CSV 数据源, 未指定列名时读取第一行作为表头; 断点的字节偏移有效且数据源支持 Seek 时直接定位到断点
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func newCsvSource(r io.Reader, l *Loader) (*csvSource, bool, error) {
+	src := &csvSource{reader: l.newCsvReader(r), columns: l.columns}
+	if len(src.columns) == 0 {
+		header, err := src.reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				return nil, false, errors.New("CSV 文件为空, 缺少表头")
+			}
+			return nil, false, fmt.Errorf("读取 CSV 表头失败: %w", err)
+		}
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+		src.columns = header
+	}
+
+	seeker, ok := r.(io.Seeker)
+	if !ok || l.resume.Offset <= 0 {
+		return src, false, nil
+	}
+	if _, err := seeker.Seek(l.resume.Offset, io.SeekStart); err != nil {
+		return nil, false, fmt.Errorf("定位到断点失败: %w", err)
+	}
+	src.reader = l.newCsvReader(r)
+	src.line, src.offset = l.resume.Line, l.resume.Offset
+	return src, true, nil
+}
+
+func (s *csvSource) next() (Record, int64, error) {
+	values, err := s.reader.Read()
+	offset := s.offset + s.reader.InputOffset()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Record{}, offset, &LineError{Line: s.line + int64(parseErr.StartLine), Err: parseErr.Err}
+		}
+		return Record{}, offset, err
+	}
+
+	line, _ := s.reader.FieldPos(0)
+	record := Record{Line: s.line + int64(line), Values: make(map[string]interface{}, len(s.columns))}
+	if len(values) != len(s.columns) {
+		return record, offset, &LineError{Line: record.Line, Err: fmt.Errorf("列数为%d, 与表头的%d列不一致", len(values), len(s.columns))}
+	}
+	for i, column := range s.columns {
+		record.Values[column] = values[i]
+	}
+	return record, offset, nil
+}
+
+// JSON Lines 数据源, 每行一个 JSON 对象, 空行会被忽略
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type jsonlSource struct {
+	reader *bufio.Reader
+	line   int64
+	offset int64
+}
+
+// 创建 JSON Lines 数据源, 断点的字节偏移有效且数据源支持 Seek 时直接定位到断点
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func newJsonlSource(r io.Reader, l *Loader) (*jsonlSource, bool, error) {
+	seeker, ok := r.(io.Seeker)
+	if !ok || l.resume.Offset <= 0 {
+		return &jsonlSource{reader: bufio.NewReader(r)}, false, nil
+	}
+	if _, err := seeker.Seek(l.resume.Offset, io.SeekStart); err != nil {
+		return nil, false, fmt.Errorf("定位到断点失败: %w", err)
+	}
+	return &jsonlSource{reader: bufio.NewReader(r), line: l.resume.Line, offset: l.resume.Offset}, true, nil
+}
+
+func (s *jsonlSource) next() (Record, int64, error) {
+	for {
+		data, err := s.reader.ReadBytes('\n')
+		if len(data) == 0 && err != nil {
+			return Record{}, s.offset, err
+		}
+		if err != nil && err != io.EOF {
+			return Record{}, s.offset, err
+		}
+		s.line++
+		s.offset += int64(len(data))
+		data = bytes.TrimSpace(data)
+		if s.line == 1 {
+			data = bytes.TrimPrefix(data, []byte("\ufeff"))
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		record := Record{Line: s.line}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err = decoder.Decode(&record.Values); err != nil || record.Values == nil {
+			if err == nil {
+				err = errors.New("不是 JSON 对象")
+			}
+			return record, s.offset, &LineError{Line: s.line, Err: fmt.Errorf("解析 JSON 失败: %w", err)}
+		}
+		return record, s.offset, nil
+	}
+}
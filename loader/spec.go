@@ -0,0 +1,224 @@
+package loader
+
+import (
+	"fmt"
+	"nebula-orm-go/clause"
+	"nebula-orm-go/constants"
+	"nebula-orm-go/utils"
+	"strconv"
+	"strings"
+)
+
+// 属性类型, 与 nebula 的属性类型对应
+const (
+	TypeString    = "string"
+	TypeInt       = "int"
+	TypeFloat     = "float"
+	TypeBool      = "bool"
+	TypeDate      = "date"
+	TypeTime      = "time"
+	TypeDateTime  = "datetime"
+	TypeTimestamp = "timestamp"
+)
+
+// PropSpec 列到属性的映射规则
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type PropSpec struct {
+	Column  string // 记录中的列名
+	Prop    string // 属性名, 为空时与列名相同
+	Type    string // 属性类型, 见 TypeString 等常量, 为空时按字符串处理
+	Default string // 列为空时使用的默认值(原始文本), 为空且列为空时写入 NULL(字符串类型写入空字符串)
+}
+
+// 属性名
+func (p PropSpec) name() string {
+	if p.Prop == "" {
+		return p.Column
+	}
+	return p.Prop
+}
+
+// TagSpec 不经过结构体, 直接将记录的列映射为标签属性
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type TagSpec struct {
+	Tag    string           // 标签名称
+	Vid    string           // VID 所在的列
+	IntVid bool             // VID 是否为整数(图空间 vid_type 为 INT64)
+	Policy constants.Policy // VID 策略
+	Props  []PropSpec       // 属性映射规则
+}
+
+// EdgeSpec 不经过结构体, 直接将记录的列映射为边属性
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type EdgeSpec struct {
+	Edge      string           // 边类型名称
+	Src       string           // 起点 VID 所在的列
+	Dst       string           // 终点 VID 所在的列
+	Rank      string           // rank 所在的列, 为空时不指定 rank
+	IntVid    bool             // VID 是否为整数(图空间 vid_type 为 INT64)
+	SrcPolicy constants.Policy // 起点 VID 策略
+	DstPolicy constants.Policy // 终点 VID 策略
+	Props     []PropSpec       // 属性映射规则
+}
+
+// 由 TagSpec/EdgeSpec 生成的一行属性, key 相同的行可以合并为一条语句
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+type propRow struct {
+	vertex bool   // 点或边
+	key    string // 标签(边类型)与属性列, 例如 test_vertex(chain_key,parent_key)
+	target string // 点为 vid, 边为 src -> dst[@rank]
+	values string // 以逗号分隔的属性值
+}
+
+// Map 实现 IMapper 接口
+func (s TagSpec) Map(record Record) ([]interface{}, error) {
+	vid, err := recordVid(record, s.Vid, s.IntVid)
+	if err != nil {
+		return nil, err
+	}
+	names, values, err := specValues(record, s.Props)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{&propRow{
+		vertex: true,
+		key:    fmt.Sprintf("%s(%s)", s.Tag, names),
+		target: formatVid(vid, s.Policy),
+		values: values,
+	}}, nil
+}
+
+// Map 实现 IMapper 接口
+func (s EdgeSpec) Map(record Record) ([]interface{}, error) {
+	src, err := recordVid(record, s.Src, s.IntVid)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := recordVid(record, s.Dst, s.IntVid)
+	if err != nil {
+		return nil, err
+	}
+	target := formatVid(src, s.SrcPolicy) + " -> " + formatVid(dst, s.DstPolicy)
+	if s.Rank != "" && record.String(s.Rank) != "" {
+		rank, err := strconv.ParseInt(strings.TrimSpace(record.String(s.Rank)), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("rank 列[%s]的值[%s]不是整数", s.Rank, record.String(s.Rank))
+		}
+		target += "@" + strconv.FormatInt(rank, 10)
+	}
+	names, values, err := specValues(record, s.Props)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{&propRow{
+		key:    fmt.Sprintf("%s(%s)", s.Edge, names),
+		target: target,
+		values: values,
+	}}, nil
+}
+
+// 按映射规则生成属性名与属性值
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func specValues(record Record, props []PropSpec) (string, string, error) {
+	names := make([]string, len(props))
+	values := make([]string, len(props))
+	for i, prop := range props {
+		names[i] = prop.name()
+		value, err := formatValue(record, prop)
+		if err != nil {
+			return "", "", fmt.Errorf("列[%s]: %w", prop.Column, err)
+		}
+		values[i] = value
+	}
+	return strings.Join(names, ","), strings.Join(values, ","), nil
+}
+
+// 将列的值格式化为 nGQL 字面量
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func formatValue(record Record, prop PropSpec) (string, error) {
+	value, _ := record.Get(prop.Column)
+	text := record.String(prop.Column)
+	if value == nil || text == "" {
+		if prop.Default != "" {
+			text = prop.Default
+		} else if prop.Type == "" || prop.Type == TypeString {
+			return "''", nil
+		} else {
+			return "NULL", nil
+		}
+	}
+	raw := text
+	text = strings.TrimSpace(text)
+
+	switch prop.Type {
+	case "", TypeString:
+		return clause.Quote(raw), nil
+	case TypeInt:
+		if _, err := strconv.ParseInt(text, 10, 64); err != nil {
+			return "", fmt.Errorf("值[%s]不是整数或超出范围", text)
+		}
+		return text, nil
+	case TypeFloat:
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return "", fmt.Errorf("值[%s]不是浮点数", text)
+		}
+		return text, nil
+	case TypeBool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return "", fmt.Errorf("值[%s]不是布尔值", text)
+		}
+		return strconv.FormatBool(b), nil
+	case TypeDate, TypeTime, TypeDateTime:
+		return fmt.Sprintf("%s(%s)", prop.Type, clause.Quote(text)), nil
+	case TypeTimestamp:
+		if _, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return text, nil
+		}
+		return fmt.Sprintf("timestamp(%s)", clause.Quote(text)), nil
+	}
+	return "", fmt.Errorf("不支持的属性类型[%s]", prop.Type)
+}
+
+// 格式化 VID, 整数 VID 不加引号, 字符串 VID 按 clause.Quote 转义
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func formatVid(vid interface{}, policy constants.Policy) string {
+	s, ok := vid.(string)
+	if !ok {
+		return utils.GetVidWithPolicy(vid, policy)
+	}
+	if policy == constants.PolicyHash {
+		return "hash(" + clause.Quote(s) + ")"
+	}
+	return clause.Quote(s)
+}
+
+// 生成一组属性行的 insert 语句, 调用方保证所有行的 key 相同
+//
+// @Author: 罗德
+// @Date: 2024/6/28
+func propRowsSql(rows []*propRow) string {
+	kind := "edge"
+	if rows[0].vertex {
+		kind = "vertex"
+	}
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		values[i] = fmt.Sprintf("%s:(%s)", row.target, row.values)
+	}
+	return fmt.Sprintf("insert %s %s values %s", kind, rows[0].key, strings.Join(values, ", "))
+}
@@ -0,0 +1,174 @@
+package loader
+
+import (
+	"encoding/json"
+	"nebula-orm-go/constants"
+	"strings"
+	"testing"
+)
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		prop  PropSpec
+		want  string
+		err   string
+	}{
+		{name: "字符串", value: "alice", prop: PropSpec{}, want: "'alice'"},
+		{name: "字符串保留空白", value: " a b ", prop: PropSpec{Type: TypeString}, want: "' a b '"},
+		{name: "字符串转义", value: "it's a\\b\nc\td", prop: PropSpec{}, want: `'it\'s a\\b\nc\td'`},
+		{name: "空字符串", value: "", prop: PropSpec{}, want: "''"},
+		{name: "空值", value: nil, prop: PropSpec{Type: TypeInt}, want: "NULL"},
+		{name: "默认值", value: "", prop: PropSpec{Type: TypeInt, Default: "7"}, want: "7"},
+		{name: "字符串默认值", value: nil, prop: PropSpec{Default: "new"}, want: "'new'"},
+		{name: "整数", value: " 42 ", prop: PropSpec{Type: TypeInt}, want: "42"},
+		{name: "JSON 数字", value: json.Number("-3"), prop: PropSpec{Type: TypeInt}, want: "-3"},
+		{name: "整数错误", value: "4.2", prop: PropSpec{Type: TypeInt}, err: "不是整数"},
+		{name: "浮点数", value: "1.5e3", prop: PropSpec{Type: TypeFloat}, want: "1.5e3"},
+		{name: "浮点数错误", value: "abc", prop: PropSpec{Type: TypeFloat}, err: "不是浮点数"},
+		{name: "布尔值", value: "T", prop: PropSpec{Type: TypeBool}, want: "true"},
+		{name: "布尔值错误", value: "yes", prop: PropSpec{Type: TypeBool}, err: "不是布尔值"},
+		{name: "日期", value: "2024-06-28", prop: PropSpec{Type: TypeDate}, want: "date('2024-06-28')"},
+		{name: "日期时间", value: "2024-06-28T10:00:00", prop: PropSpec{Type: TypeDateTime}, want: "datetime('2024-06-28T10:00:00')"},
+		{name: "日期时间转义", value: "x')", prop: PropSpec{Type: TypeTime}, want: `time('x\')')`},
+		{name: "时间戳数字", value: "1719540000", prop: PropSpec{Type: TypeTimestamp}, want: "1719540000"},
+		{name: "时间戳文本", value: "2024-06-28T10:00:00", prop: PropSpec{Type: TypeTimestamp}, want: "timestamp('2024-06-28T10:00:00')"},
+		{name: "不支持的类型", value: "1", prop: PropSpec{Type: "duration"}, err: "不支持的属性类型"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prop.Column = "col"
+			record := Record{Line: 1, Values: map[string]interface{}{"col": tt.value}}
+			got, err := formatValue(record, tt.prop)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("formatValue() error = %v, 期望包含 %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("formatValue() = %s, 期望 %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatVid(t *testing.T) {
+	tests := []struct {
+		name   string
+		vid    interface{}
+		policy constants.Policy
+		want   string
+	}{
+		{name: "字符串", vid: "a", want: "'a'"},
+		{name: "字符串转义", vid: "a'b\\c\n", want: `'a\'b\\c\n'`},
+		{name: "整数", vid: int64(10), want: "10"},
+		{name: "哈希", vid: "a'b", policy: constants.PolicyHash, want: `hash('a\'b')`},
+		{name: "整数哈希", vid: int64(10), policy: constants.PolicyHash, want: "hash(10)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatVid(tt.vid, tt.policy); got != tt.want {
+				t.Errorf("formatVid() = %s, 期望 %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagSpecMap(t *testing.T) {
+	spec := TagSpec{
+		Tag: "person",
+		Vid: "id",
+		Props: []PropSpec{
+			{Column: "name"},
+			{Column: "years", Prop: "age", Type: TypeInt},
+		},
+	}
+	record := Record{Line: 2, Values: map[string]interface{}{"id": "p'1", "name": "Tom", "years": "30"}}
+	items, err := spec.Map(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := items[0].(*propRow)
+	if !row.vertex || row.key != "person(name,age)" || row.target != `'p\'1'` || row.values != "'Tom',30" {
+		t.Fatalf("Map() = %+v", row)
+	}
+	if got, want := propRowsSql([]*propRow{row, row}), `insert vertex person(name,age) values 'p\'1':('Tom',30), 'p\'1':('Tom',30)`; got != want {
+		t.Errorf("propRowsSql() = %s, 期望 %s", got, want)
+	}
+
+	// 整数 VID
+	spec.IntVid = true
+	record.Values["id"] = " 12 "
+	if items, err = spec.Map(record); err != nil {
+		t.Fatal(err)
+	}
+	if row = items[0].(*propRow); row.target != "12" {
+		t.Errorf("整数 VID = %s, 期望 12", row.target)
+	}
+
+	errTests := []struct {
+		name   string
+		values map[string]interface{}
+		err    string
+	}{
+		{name: "VID 为空", values: map[string]interface{}{"id": "", "name": "a", "years": "1"}, err: "VID 列[id]为空"},
+		{name: "VID 不是整数", values: map[string]interface{}{"id": "x", "name": "a", "years": "1"}, err: "不是整数"},
+		{name: "属性错误", values: map[string]interface{}{"id": "1", "name": "a", "years": "x"}, err: "列[years]"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := spec.Map(Record{Line: 3, Values: tt.values})
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("Map() error = %v, 期望包含 %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestEdgeSpecMap(t *testing.T) {
+	spec := EdgeSpec{
+		Edge:      "follow",
+		Src:       "from",
+		Dst:       "to",
+		Rank:      "rank",
+		DstPolicy: constants.PolicyHash,
+		Props:     []PropSpec{{Column: "degree", Type: TypeFloat}},
+	}
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		target string
+		err    string
+	}{
+		{name: "带 rank", values: map[string]interface{}{"from": "a", "to": "b", "rank": "3", "degree": "0.5"}, target: "'a' -> hash('b')@3"},
+		{name: "rank 为空", values: map[string]interface{}{"from": "a", "to": "b", "rank": "", "degree": "0.5"}, target: "'a' -> hash('b')"},
+		{name: "rank 不是整数", values: map[string]interface{}{"from": "a", "to": "b", "rank": "x", "degree": "0.5"}, err: "rank 列[rank]"},
+		{name: "终点为空", values: map[string]interface{}{"from": "a", "rank": "1", "degree": "0.5"}, err: "VID 列[to]为空"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := spec.Map(Record{Line: 1, Values: tt.values})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Map() error = %v, 期望包含 %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			row := items[0].(*propRow)
+			if row.vertex || row.key != "follow(degree)" || row.target != tt.target || row.values != "0.5" {
+				t.Fatalf("Map() = %+v", row)
+			}
+			if got, want := propRowsSql([]*propRow{row}), "insert edge follow(degree) values "+tt.target+":(0.5)"; got != want {
+				t.Errorf("propRowsSql() = %s, 期望 %s", got, want)
+			}
+		})
+	}
+}
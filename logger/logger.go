@@ -0,0 +1,98 @@
+// Package logger 结构化日志抽象: 以 msg + 键值对 的形式记录日志, 与 log/slog 的调用方式保持一致
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+package logger
+
+import (
+	"fmt"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"strings"
+)
+
+// 日志中统一使用的字段名
+const (
+	KeyNGQL     = "ngql"     // 执行的nGQL语句
+	KeyDuration = "duration" // 客户端耗时(包含网络往返)
+	KeyLatency  = "latency"  // graphd 服务端耗时, 来自 ResultSet.GetLatency
+	KeySpace    = "space"    // 图空间名称
+	KeyError    = "error"    // 错误信息
+)
+
+// Logger 结构化日志接口, keysAndValues 为交替出现的键值对(key1, value1, key2, value2...)。
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// 将 nebula.Logger 适配为结构化日志接口, 键值对会被格式化为 key=value 拼接在消息之后
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+type nebulaLogger struct {
+	log nebula.Logger
+}
+
+// NewNebulaLogger 将只支持字符串的 nebula.Logger 适配为结构化日志接口 Logger。
+// nebula.Logger 没有 Debug 级别, Debug 日志将以 Info 级别输出。
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+func NewNebulaLogger(log nebula.Logger) Logger {
+	return &nebulaLogger{log: log}
+}
+
+func (l *nebulaLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.log.Info(format(msg, keysAndValues))
+}
+
+func (l *nebulaLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.log.Info(format(msg, keysAndValues))
+}
+
+func (l *nebulaLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.log.Warn(format(msg, keysAndValues))
+}
+
+func (l *nebulaLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.log.Error(format(msg, keysAndValues))
+}
+
+// 将消息与键值对格式化为 `msg key1=value1 key2=value2` 的形式, 落单的键以 !BADKEY 标记
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+func format(msg string, keysAndValues []interface{}) string {
+	if len(keysAndValues) == 0 {
+		return msg
+	}
+
+	builder := new(strings.Builder)
+	builder.WriteString(msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 >= len(keysAndValues) {
+			builder.WriteString(fmt.Sprintf(" !BADKEY=%v", keysAndValues[i]))
+			break
+		}
+		builder.WriteString(fmt.Sprintf(" %v=%v", keysAndValues[i], keysAndValues[i+1]))
+	}
+	return builder.String()
+}
+
+// Discard 丢弃所有日志的实现, 用于需要完全关闭日志的场景
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+var Discard Logger = discard{}
+
+type discard struct{}
+
+func (discard) Debug(string, ...interface{}) {}
+func (discard) Info(string, ...interface{})  {}
+func (discard) Warn(string, ...interface{})  {}
+func (discard) Error(string, ...interface{}) {}
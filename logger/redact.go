@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"strings"
+	"unicode"
+)
+
+// RedactedValue 属性值脱敏后的占位符
+const RedactedValue = "?"
+
+// Redact 对nGQL中的属性值做脱敏处理, 用于日志输出, 避免敏感数据落入日志。
+// 1. 所有单/双引号包裹的字符串字面量(包含字符串类型的VID)替换为 '?'
+// 2. 数字字面量(属性值、列表元素、整数VID、rank 等)替换为 ?, 布尔字面量 true/false 替换为 ?
+// 3. 关键字、标签名、属性名、反引号包裹的名称以及步数、limit 等结构信息保持不变, 便于定位语句
+//
+// 例: insert edge e(w) values 1->2@3:(true) => insert edge e(w) values ?->?@?:(?)
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+func Redact(ngql string) string {
+	runes := []rune(ngql)
+	builder := new(strings.Builder)
+	builder.Grow(len(ngql))
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'' || r == '"':
+			// 跳过整个字符串字面量, 支持反斜杠转义
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' {
+					j++
+				}
+			}
+			builder.WriteRune(r)
+			builder.WriteString(RedactedValue)
+			builder.WriteRune(r)
+			i = j
+
+		case r == '`':
+			// 反引号包裹的名称原样输出
+			j := i + 1
+			for j < len(runes) && runes[j] != '`' {
+				j++
+			}
+			if j == len(runes) {
+				j--
+			}
+			builder.WriteString(string(runes[i : j+1]))
+			i = j
+
+		case isIdentRune(r) && !unicode.IsDigit(r):
+			// 标识符整体输出, 其中的数字不是字面量
+			j := i
+			for j+1 < len(runes) && isIdentRune(runes[j+1]) {
+				j++
+			}
+			word := string(runes[i : j+1])
+			if strings.EqualFold(word, "true") || strings.EqualFold(word, "false") {
+				word = RedactedValue
+			}
+			builder.WriteString(word)
+			i = j
+
+		case unicode.IsDigit(r):
+			// 跳过整个数字字面量(包括小数、科学计数法与十六进制)
+			j := i
+			for j+1 < len(runes) && (isIdentRune(runes[j+1]) || runes[j+1] == '.') {
+				j++
+			}
+			if isStructural(runes, i, j) {
+				builder.WriteString(string(runes[i : j+1]))
+			} else {
+				builder.WriteString(RedactedValue)
+			}
+			i = j
+
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+// 步数、limit/skip/offset 等结构关键字的数字参数
+var structuralKeywords = map[string]bool{"go": true, "limit": true, "skip": true, "offset": true, "upto": true}
+
+// 判断 [i, j] 上的数字是否为结构信息而不是属性值: 前一个单词为结构关键字, 或后一个单词为 steps/step
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+func isStructural(runes []rune, i, j int) bool {
+	start := i - 1
+	for start >= 0 && unicode.IsSpace(runes[start]) {
+		start--
+	}
+	end := start
+	for start >= 0 && isIdentRune(runes[start]) {
+		start--
+	}
+	if structuralKeywords[strings.ToLower(string(runes[start+1:end+1]))] {
+		return true
+	}
+
+	start = j + 1
+	for start < len(runes) && unicode.IsSpace(runes[start]) {
+		start++
+	}
+	end = start
+	for end < len(runes) && isIdentRune(runes[end]) {
+		end++
+	}
+	next := strings.ToLower(string(runes[start:end]))
+	return next == "steps" || next == "step"
+}
+
+// 标识符中的字符: 字母、数字与下划线
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
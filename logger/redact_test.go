@@ -0,0 +1,35 @@
+package logger
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		ngql string
+		want string
+	}{
+		{name: "插入点", ngql: "insert vertex t(name,age) values 'v1':('张三',18)", want: "insert vertex t(name,age) values '?':('?',?)"},
+		{name: "转义的单引号", ngql: `fetch prop on t 'it\'s' yield t.name`, want: `fetch prop on t '?' yield t.name`},
+		{name: "转义的双引号", ngql: `match (v:t) where v.t.name == "a\"b\\" and v.t.age > 1 return v`, want: `match (v:t) where v.t.name == "?" and v.t.age > ? return v`},
+		{name: "另一种引号不结束字符串", ngql: `update vertex on t "a'b" set name = 'x"y'`, want: `update vertex on t "?" set name = '?'`},
+		{name: "未闭合的字符串", ngql: "fetch prop on t 'abc", want: "fetch prop on t '?'"},
+		{name: "小数与科学计数法", ngql: "lookup on t where t.score >= 18.5 or t.score < 1e10", want: "lookup on t where t.score >= ? or t.score < ?"},
+		{name: "负数与十六进制", ngql: "update vertex on t 1 set age = -3, code = 0x1F", want: "update vertex on t ? set age = -?, code = ?"},
+		{name: "整数VID与rank", ngql: "insert edge e(w) values 1->2@3:(5), 4 -> 5 @6:(7)", want: "insert edge e(w) values ?->?@?:(?), ? -> ? @?:(?)"},
+		{name: "列表元素", ngql: "lookup on t where t.age in [18, 20] yield id(vertex)", want: "lookup on t where t.age in [?, ?] yield id(vertex)"},
+		{name: "布尔值", ngql: "update vertex on t 'a' set flag = true, deleted = FALSE when t.ok == true", want: "update vertex on t '?' set flag = ?, deleted = ? when t.ok == ?"},
+		{name: "步数与limit不变", ngql: "go 3 steps from 'a' over follow yield dst(edge) | limit 10", want: "go 3 steps from '?' over follow yield dst(edge) | limit 10"},
+		{name: "步数范围不变", ngql: "go 1 to 3 steps from 7 over follow; find shortest path from 1 to 2 over * upto 5 steps", want: "go 1 to 3 steps from ? over follow; find shortest path from ? to ? over * upto 5 steps"},
+		{name: "反引号名称不变", ngql: "fetch prop on `tag 1` 'a' yield `tag 1`.`p 2`", want: "fetch prop on `tag 1` '?' yield `tag 1`.`p 2`"},
+		{name: "标识符中的数字不变", ngql: "fetch prop on tag_1,t2 $v1 yield t2.p3, truely", want: "fetch prop on tag_1,t2 $v1 yield t2.p3, truely"},
+		{name: "无需脱敏", ngql: "match (v:person)-[e:follow]->(n) return v, n", want: "match (v:person)-[e:follow]->(n) return v, n"},
+		{name: "空语句", ngql: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.ngql); got != tt.want {
+				t.Errorf("Redact(%q) = %q, 期望 %q", tt.ngql, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+//go:build go1.21
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// 将 *slog.Logger 适配为结构化日志接口, 键值对直接透传给 slog
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+type slogLogger struct {
+	log *slog.Logger
+}
+
+// NewSlogLogger 将标准库 log/slog 的 *slog.Logger 适配为结构化日志接口 Logger, 传入nil时使用 slog.Default()。
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+func NewSlogLogger(log *slog.Logger) Logger {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &slogLogger{log: log}
+}
+
+func (l *slogLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.log.Log(context.Background(), slog.LevelDebug, msg, keysAndValues...)
+}
+
+func (l *slogLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.log.Log(context.Background(), slog.LevelInfo, msg, keysAndValues...)
+}
+
+func (l *slogLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.log.Log(context.Background(), slog.LevelWarn, msg, keysAndValues...)
+}
+
+func (l *slogLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.log.Log(context.Background(), slog.LevelError, msg, keysAndValues...)
+}
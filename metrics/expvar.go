@@ -0,0 +1,14 @@
+package metrics
+
+import "expvar"
+
+// PublishExpvar 将内存指标以指定名称发布到 expvar, 可通过 /debug/vars 读取JSON格式的指标快照。
+// 注意: expvar 不允许重复发布同名变量, 同一名称只能调用一次, 否则会panic。
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func PublishExpvar(name string, sink *MemorySink) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return sink.Snapshot()
+	}))
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+)
+
+// 发布的变量每次读取都是当前指标的JSON快照
+func TestPublishExpvar(t *testing.T) {
+	sink := NewMemorySink(10)
+	PublishExpvar("norm_metrics_test", sink)
+	variable := expvar.Get("norm_metrics_test")
+	if variable == nil {
+		t.Fatal("变量未发布")
+	}
+
+	sink.ObserveStatement("lookup", -1005, time.Millisecond, 0)
+	sink.SetSessionsInUse(1)
+	var snapshot Snapshot
+	if err := json.Unmarshal([]byte(variable.String()), &snapshot); err != nil {
+		t.Fatalf("解析 %s 失败: %v", variable.String(), err)
+	}
+	lookup := snapshot.Statements["lookup"]
+	if lookup == nil || lookup.Codes[-1005] != 1 || lookup.Client.Count != 1 || snapshot.SessionsInUse != 1 {
+		t.Errorf("expvar = %s", variable.String())
+	}
+
+	sink.SetSessionsInUse(0)
+	if err := json.Unmarshal([]byte(variable.String()), &snapshot); err != nil || snapshot.SessionsInUse != 0 {
+		t.Errorf("再次读取 expvar = %s, err = %v", variable.String(), err)
+	}
+}
@@ -0,0 +1,177 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets 耗时直方图默认的桶上界(毫秒), 超过最后一个桶的样本计入 +Inf
+var DefaultBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// Histogram 耗时直方图, Counts[i] 为耗时 <= Buckets[i] 毫秒的样本数(非累计), Counts 最后一位为 +Inf 桶
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+type Histogram struct {
+	Buckets []float64 `json:"buckets"` // 桶上界(毫秒)
+	Counts  []int64   `json:"counts"`  // 各桶样本数, 长度为 len(Buckets)+1
+	Count   int64     `json:"count"`   // 样本总数
+	Sum     float64   `json:"sum"`     // 样本耗时总和(毫秒)
+}
+
+// 创建指定桶上界的直方图
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		Buckets: buckets,
+		Counts:  make([]int64, len(buckets)+1),
+	}
+}
+
+// 记录一个耗时样本
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func (h *Histogram) observe(d time.Duration) {
+	ms := float64(d) / float64(time.Millisecond)
+	h.Counts[sort.SearchFloat64s(h.Buckets, ms)]++
+	h.Count++
+	h.Sum += ms
+}
+
+// 深拷贝直方图, 用于生成快照
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func (h *Histogram) clone() *Histogram {
+	return &Histogram{
+		Buckets: h.Buckets,
+		Counts:  append([]int64(nil), h.Counts...),
+		Count:   h.Count,
+		Sum:     h.Sum,
+	}
+}
+
+// StatementStats 单个语句类型的统计数据
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+type StatementStats struct {
+	Codes  map[int]int64 `json:"codes"`  // 按错误码计数, 成功为0
+	Client *Histogram    `json:"client"` // 客户端耗时直方图
+	Server *Histogram    `json:"server"` // graphd 服务端耗时直方图
+}
+
+// Snapshot 指标快照, 可直接序列化为JSON
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+type Snapshot struct {
+	Statements    map[string]*StatementStats `json:"statements"`      // 按语句类型统计
+	SessionWait   *Histogram                 `json:"session_wait"`    // 获取会话等待时间直方图
+	SessionsInUse int64                      `json:"sessions_in_use"` // 当前使用中的会话数
+}
+
+// MemorySink 默认的内存指标实现, 通过 Snapshot 读取当前指标
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+type MemorySink struct {
+	mu            sync.Mutex
+	buckets       []float64
+	statements    map[string]*StatementStats
+	sessionWait   *Histogram
+	sessionsInUse int64
+}
+
+var _ ISink = new(MemorySink)
+
+// NewMemorySink 创建内存指标实现, buckets 为耗时直方图桶上界(毫秒, 升序), 为空时使用 DefaultBuckets
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func NewMemorySink(buckets ...float64) *MemorySink {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	return &MemorySink{
+		buckets:     buckets,
+		statements:  make(map[string]*StatementStats),
+		sessionWait: newHistogram(buckets),
+	}
+}
+
+// ObserveStatement 记录一条语句的执行结果
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func (m *MemorySink) ObserveStatement(kind string, code int, client, server time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.statements[kind]
+	if !ok {
+		stats = &StatementStats{
+			Codes:  make(map[int]int64),
+			Client: newHistogram(m.buckets),
+			Server: newHistogram(m.buckets),
+		}
+		m.statements[kind] = stats
+	}
+	stats.Codes[code]++
+	stats.Client.observe(client)
+	// 执行失败时没有服务端耗时, 不计入服务端直方图
+	if code == 0 {
+		stats.Server.observe(server)
+	}
+}
+
+// ObserveSessionWait 记录从连接池获取会话的等待时间
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func (m *MemorySink) ObserveSessionWait(wait time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessionWait.observe(wait)
+}
+
+// SetSessionsInUse 更新当前使用中的会话数
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func (m *MemorySink) SetSessionsInUse(n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessionsInUse = n
+}
+
+// Snapshot 返回当前指标的深拷贝, 调用方可以安全读取与序列化
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func (m *MemorySink) Snapshot() Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statements := make(map[string]*StatementStats, len(m.statements))
+	for kind, stats := range m.statements {
+		codes := make(map[int]int64, len(stats.Codes))
+		for code, cnt := range stats.Codes {
+			codes[code] = cnt
+		}
+		statements[kind] = &StatementStats{
+			Codes:  codes,
+			Client: stats.Client.clone(),
+			Server: stats.Server.clone(),
+		}
+	}
+	return Snapshot{
+		Statements:    statements,
+		SessionWait:   m.sessionWait.clone(),
+		SessionsInUse: m.sessionsInUse,
+	}
+}
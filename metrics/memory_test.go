@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 样本计入第一个上界 >= 耗时的桶, 超过最后一个上界的计入 +Inf
+func TestHistogramBuckets(t *testing.T) {
+	h := newHistogram([]float64{1, 5, 10})
+	for _, d := range []time.Duration{
+		0,
+		time.Millisecond,
+		1500 * time.Microsecond,
+		5 * time.Millisecond,
+		10 * time.Millisecond,
+		10*time.Millisecond + time.Microsecond,
+		time.Hour,
+	} {
+		h.observe(d)
+	}
+	if want := []int64{2, 2, 1, 2}; !reflect.DeepEqual(h.Counts, want) {
+		t.Errorf("Counts = %v, 期望 %v", h.Counts, want)
+	}
+	if h.Count != 7 {
+		t.Errorf("Count = %d, 期望 7", h.Count)
+	}
+	if want := 0 + 1 + 1.5 + 5 + 10 + 10.001 + 3600000; h.Sum != want {
+		t.Errorf("Sum = %v, 期望 %v", h.Sum, want)
+	}
+}
+
+func TestNewMemorySinkBuckets(t *testing.T) {
+	if got := NewMemorySink().Snapshot().SessionWait; !reflect.DeepEqual(got.Buckets, DefaultBuckets) || len(got.Counts) != len(DefaultBuckets)+1 {
+		t.Errorf("默认桶 = %v, %v", got.Buckets, got.Counts)
+	}
+	if got := NewMemorySink(2, 4).Snapshot().SessionWait; !reflect.DeepEqual(got.Buckets, []float64{2, 4}) || len(got.Counts) != 3 {
+		t.Errorf("指定桶 = %v, %v", got.Buckets, got.Counts)
+	}
+}
+
+// 按语句类型与错误码计数, 失败的语句不计入服务端耗时
+func TestObserveStatement(t *testing.T) {
+	sink := NewMemorySink(10, 100)
+	sink.ObserveStatement("fetch", 0, 2*time.Millisecond, time.Millisecond)
+	sink.ObserveStatement("fetch", 0, 20*time.Millisecond, 15*time.Millisecond)
+	sink.ObserveStatement("fetch", -1005, 200*time.Millisecond, 0)
+	sink.ObserveStatement("insert", -8, time.Millisecond, 0)
+
+	snapshot := sink.Snapshot()
+	if len(snapshot.Statements) != 2 {
+		t.Fatalf("Statements = %v", snapshot.Statements)
+	}
+	fetch := snapshot.Statements["fetch"]
+	if want := map[int]int64{0: 2, -1005: 1}; !reflect.DeepEqual(fetch.Codes, want) {
+		t.Errorf("fetch Codes = %v, 期望 %v", fetch.Codes, want)
+	}
+	if want := []int64{1, 1, 1}; fetch.Client.Count != 3 || !reflect.DeepEqual(fetch.Client.Counts, want) {
+		t.Errorf("fetch Client = %+v", fetch.Client)
+	}
+	if want := []int64{1, 1, 0}; fetch.Server.Count != 2 || !reflect.DeepEqual(fetch.Server.Counts, want) || fetch.Server.Sum != 16 {
+		t.Errorf("fetch Server = %+v", fetch.Server)
+	}
+	insert := snapshot.Statements["insert"]
+	if !reflect.DeepEqual(insert.Codes, map[int]int64{-8: 1}) || insert.Client.Count != 1 || insert.Server.Count != 0 {
+		t.Errorf("insert = %+v", insert)
+	}
+}
+
+// 快照是深拷贝: 修改快照不影响指标, 之后的记录也不会改变已有的快照
+func TestSnapshotCopy(t *testing.T) {
+	sink := NewMemorySink(10)
+	sink.ObserveStatement("go", 0, time.Millisecond, time.Millisecond)
+	sink.ObserveSessionWait(time.Millisecond)
+	sink.SetSessionsInUse(2)
+
+	snapshot := sink.Snapshot()
+	snapshot.Statements["go"].Codes[0] = 100
+	snapshot.Statements["go"].Client.Counts[0] = 100
+	snapshot.Statements["match"] = &StatementStats{}
+	snapshot.SessionWait.Counts[0] = 100
+
+	sink.ObserveStatement("go", 0, time.Millisecond, time.Millisecond)
+	sink.ObserveSessionWait(time.Millisecond)
+	sink.SetSessionsInUse(3)
+
+	current := sink.Snapshot()
+	if current.Statements["go"].Codes[0] != 2 || current.Statements["go"].Client.Counts[0] != 2 || current.SessionWait.Counts[0] != 2 {
+		t.Errorf("修改快照影响了指标: %+v", current.Statements["go"])
+	}
+	if _, ok := current.Statements["match"]; ok {
+		t.Error("快照中添加的语句类型出现在指标中")
+	}
+	if snapshot.SessionsInUse != 2 || current.SessionsInUse != 3 || snapshot.SessionWait.Count != 1 {
+		t.Errorf("快照 = %+v, 当前 = %+v", snapshot, current)
+	}
+}
+
+// 并发记录与读取快照
+func TestMemorySinkConcurrent(t *testing.T) {
+	sink := NewMemorySink()
+	const workers, count = 8, 100
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				sink.ObserveStatement("fetch", 0, time.Millisecond, time.Millisecond)
+				sink.ObserveSessionWait(time.Millisecond)
+				_ = sink.Snapshot()
+			}
+		}()
+	}
+	wg.Wait()
+	snapshot := sink.Snapshot()
+	if snapshot.Statements["fetch"].Codes[0] != workers*count || snapshot.SessionWait.Count != workers*count {
+		t.Errorf("snapshot = %+v", snapshot)
+	}
+}
@@ -0,0 +1,34 @@
+// Package metrics 语句执行指标采集: 按语句类型与错误码计数, 客户端/服务端耗时直方图, 连接池会话等待时间与使用中的会话数
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+package metrics
+
+import "time"
+
+// ISink 指标采集接口, orm.DB 在每条语句执行后上报语句指标, NebulaDialer 在获取/释放会话时上报连接池指标。
+// 实现必须是并发安全的。
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+type ISink interface {
+	// ObserveStatement 记录一条语句的执行结果
+	// kind: 语句类型(见 utils.GetStatementKind), code: nebula错误码(成功为0), client: 客户端耗时, server: graphd 服务端耗时
+	ObserveStatement(kind string, code int, client, server time.Duration)
+	// ObserveSessionWait 记录从连接池获取会话的等待时间
+	ObserveSessionWait(wait time.Duration)
+	// SetSessionsInUse 更新当前使用中的会话数
+	SetSessionsInUse(n int64)
+}
+
+// Nop 不做任何记录的指标实现, 未配置指标采集时使用
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+var Nop ISink = nop{}
+
+type nop struct{}
+
+func (nop) ObserveStatement(string, int, time.Duration, time.Duration) {}
+func (nop) ObserveSessionWait(time.Duration)                           {}
+func (nop) SetSessionsInUse(int64)                                     {}
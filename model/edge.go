@@ -49,12 +49,38 @@ func (v EModel) GetVidSrcPolicy() constants.Policy {
 	return v.SrcPolicy
 }
 
+// SetVidSrc 实现ISetEdgeVid接口，写入源顶点ID。
+func (v *EModel) SetVidSrc(vid interface{}) {
+	v.Src = vid
+}
+
 // GetVidDst 实现接口方法，返回目标顶点ID。
 func (v EModel) GetVidDst() interface{} {
 	return v.Dst
 }
 
+// SetVidDst 实现ISetEdgeVid接口，写入目标顶点ID。
+func (v *EModel) SetVidDst(vid interface{}) {
+	v.Dst = vid
+}
+
 // GetVidDstPolicy 实现接口方法，返回目标顶点ID的策略。
 func (v EModel) GetVidDstPolicy() constants.Policy {
 	return v.DstPolicy
 }
+
+// IEdgeRank 边模型通过实现该接口指定 rank, 查询边(DB.Fetch)时拼接为 src->dst@rank, 未实现时 rank 为0
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+type IEdgeRank interface {
+	GetRank() int64
+}
+
+// ISetEdgeRank 可以写入 rank 的边模型, 查询边(DB.Lookup、EdgeRepo)时写入 rank(edge)
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+type ISetEdgeRank interface {
+	SetRank(rank int64)
+}
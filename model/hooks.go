@@ -0,0 +1,45 @@
+package model
+
+// 以下接口为模型的可选生命周期钩子, 点/边模型按需实现即可, 未实现的钩子不会被调用。
+// 钩子方法需要修改模型字段时, 请使用指针接收者实现, 并在调用orm方法时传入模型指针。
+// Before 类钩子返回错误时会中止本次语句的执行, 并将该错误原样返回给调用方。
+
+// IBeforeInsert 插入前钩子, 在生成 insert 语句之前调用, 可用于设置派生字段或校验数据。
+//
+// @Author: 罗德
+// @Date: 2024/6/17
+type IBeforeInsert interface {
+	BeforeInsert() error
+}
+
+// IAfterInsert 插入后钩子, 在 insert 语句执行成功后调用。
+//
+// @Author: 罗德
+// @Date: 2024/6/17
+type IAfterInsert interface {
+	AfterInsert() error
+}
+
+// IBeforeUpdate 更新前钩子, 在生成 update/upsert 语句之前调用。
+//
+// @Author: 罗德
+// @Date: 2024/6/17
+type IBeforeUpdate interface {
+	BeforeUpdate() error
+}
+
+// IBeforeDelete 删除前钩子, 在生成 delete 语句之前调用。
+//
+// @Author: 罗德
+// @Date: 2024/6/17
+type IBeforeDelete interface {
+	BeforeDelete() error
+}
+
+// IAfterFind 查询后钩子, 在结果集解码到结构体之后调用, 解码到结构体切片时对每个元素调用一次。
+//
+// @Author: 罗德
+// @Date: 2024/6/17
+type IAfterFind interface {
+	AfterFind() error
+}
@@ -49,6 +49,11 @@ func (v VModel) GetVid() interface{} {
 	return v.Vid
 }
 
+// SetVid 实现ISetVid接口，写入顶点ID。
+func (v *VModel) SetVid(vid interface{}) {
+	v.Vid = vid
+}
+
 // GetPolicy 实现接口方法，返回顶点ID的策略。
 func (v VModel) GetPolicy() constants.Policy {
 	return v.Policy
@@ -0,0 +1,37 @@
+package model
+
+// IVidGenerator VID生成策略, 插入点时 Vid 为空(nil 或零值)则调用 Generate 生成VID。
+// 内置的雪花算法、UUID、ULID、组合属性哈希等策略见 vidgen 包
+//
+// @Author: 罗德
+// @Date: 2024/7/6
+type IVidGenerator interface {
+	// Generate 为点生成VID, 返回 int64(INT64 图空间) 或 string(FIXED_STRING 图空间)
+	Generate(vertex IVertex) (interface{}, error)
+}
+
+// IVidGenerate 点模型通过实现该接口指定VID生成策略, 每个模型可以使用不同的策略。
+// 生成的VID通过 ISetVid 写回模型, 嵌入 VModel 的模型以指针调用时调用方可以读取生成的VID
+//
+// @Author: 罗德
+// @Date: 2024/7/6
+type IVidGenerate interface {
+	VidGenerator() IVidGenerator
+}
+
+// ISetVid 可以写入VID的点模型, *VModel 已实现该接口
+//
+// @Author: 罗德
+// @Date: 2024/7/6
+type ISetVid interface {
+	SetVid(vid interface{})
+}
+
+// ISetEdgeVid 可以写入起点、终点VID的边模型, *EModel 已实现该接口
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+type ISetEdgeVid interface {
+	SetVidSrc(vid interface{})
+	SetVidDst(vid interface{})
+}
@@ -1,10 +1,19 @@
 package orm
 
 import (
+	"context"
+	"fmt"
 	"github.com/pkg/errors"
-	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"nebula-orm-go/clause"
 	"nebula-orm-go/config"
 	"nebula-orm-go/dialectors"
+	"nebula-orm-go/logger"
+	"nebula-orm-go/metrics"
+	"nebula-orm-go/model"
+	"nebula-orm-go/tracing"
+	"nebula-orm-go/utils"
+	"sync/atomic"
+	"time"
 )
 
 // DB 结构体代表一个数据库连接实例，封装了与数据库交互的方法和配置。
@@ -27,11 +36,60 @@ type DB struct {
 	// 是一个可执行函数，用于在某些操作完成后执行清理工作，如事务回滚或关闭连接。
 	teardown func()
 
-	// 提供日志记录功能，用于记录查询日志等。
-	logger nebula.Logger
+	// 提供结构化日志记录功能，用于记录查询日志、慢语句日志等。
+	logger logger.Logger
 
 	// 标记是否开启调试模式，若为true，则会输出执行的SQL语句。
 	debug bool
+
+	// 慢语句阈值, 执行耗时超过该值时记录慢语句日志, <=0 表示关闭。
+	slowThreshold time.Duration
+
+	// 日志中是否对nGQL的属性值做脱敏处理。
+	redact bool
+
+	// 语句指标采集, 按语句类型与错误码计数并记录耗时。
+	metrics metrics.ISink
+
+	// 链路追踪器, 每条语句创建一个 Span。
+	tracer tracing.ITracer
+
+	// 调用方传入的上下文, 用于传播链路追踪信息。
+	ctx context.Context
+
+	// 当前操作的点/边模型名称, 用于链路追踪。
+	model string
+
+	// 执行计划前缀(explain/profile), 为空时正常执行语句。
+	explain string
+
+	// 执行计划格式(row/dot/tck)。
+	explainFormat string
+
+	// 试运行模式下记录语句, 不为nil时只生成nGQL而不发送给graphd。
+	statement *Statement
+
+	// 分块写入时每条语句包含的点/边数量。
+	batchSize int
+
+	// 分块写入时同时执行的语句数量。
+	batchConcurrency int
+
+	// Model 指定的点/边模型实例, 供 Updates/Upserts 等方法使用。
+	dest interface{}
+
+	// Select/Omit 指定的属性过滤。
+	selects []string
+	omits   []string
+
+	// When 指定的更新条件。
+	when clause.ICondition
+
+	// 图空间的VID类型, 不为nil时写入前校验点边的VID; 在调用链之间共享, DetectVidType 可以与其它调用链并发更新。
+	vidType *atomic.Pointer[utils.VidType]
+
+	// 标签(边类型)的索引列缓存, 供 Lookup 校验索引, 在调用链之间共享。
+	indexes *indexCache
 }
 
 // Open 初始化并返回一个新的api.DB实例，同时根据提供的配置和选项配置数据库连接。
@@ -50,15 +108,33 @@ func Open(iDialer dialectors.IDialer, cfg config.Config, opts ...config.Option)
 	}
 	// 加载默认配置项。
 	cfg.LoadDefault()
+	// 解析VID类型, 未设置时不校验VID
+	vidType := new(atomic.Pointer[utils.VidType])
+	if cfg.VidType != "" {
+		typ, err := utils.ParseVidType(cfg.VidType)
+		if err != nil {
+			return &DB{}, err
+		}
+		vidType.Store(typ)
+	}
 
 	// 创建并返回api.DB实例。
 	return &DB{
-		dialer:   iDialer,
-		parent:   nil,
-		logger:   cfg.Logger,
-		debug:    cfg.DebugMode,
-		limit:    cfg.Limit,
-		teardown: func() {},
+		dialer:           iDialer,
+		parent:           nil,
+		logger:           cfg.StructuredLogger,
+		debug:            cfg.DebugMode,
+		limit:            cfg.Limit,
+		slowThreshold:    cfg.SlowThreshold,
+		redact:           cfg.RedactValues,
+		metrics:          cfg.Metrics,
+		tracer:           cfg.Tracer,
+		ctx:              context.Background(),
+		batchSize:        cfg.BatchSize,
+		batchConcurrency: cfg.BatchConcurrency,
+		vidType:          vidType,
+		indexes:          &indexCache{columns: make(map[string][][]string)},
+		teardown:         func() {},
 	}, nil
 }
 
@@ -87,13 +163,30 @@ func (db *DB) getInstance() (tx *DB) {
 	if db.parent == nil {
 		// 如果当前实例没有父实例（即链的开始），则创建一个新的实例并返回。
 		tx = &DB{
-			dialer:   db.dialer,
-			parent:   db,
-			sql:      db.sql,
-			logger:   db.logger,
-			debug:    db.debug,
-			limit:    db.limit,
-			teardown: func() {},
+			dialer:           db.dialer,
+			parent:           db,
+			sql:              db.sql,
+			logger:           db.logger,
+			debug:            db.debug,
+			limit:            db.limit,
+			slowThreshold:    db.slowThreshold,
+			redact:           db.redact,
+			metrics:          db.metrics,
+			tracer:           db.tracer,
+			ctx:              db.ctx,
+			model:            db.model,
+			explain:          db.explain,
+			explainFormat:    db.explainFormat,
+			statement:        db.statement,
+			batchSize:        db.batchSize,
+			batchConcurrency: db.batchConcurrency,
+			dest:             db.dest,
+			selects:          db.selects,
+			omits:            db.omits,
+			when:             db.when,
+			vidType:          db.vidType,
+			indexes:          db.indexes,
+			teardown:         func() {},
 		}
 		return tx
 	}
@@ -108,16 +201,160 @@ func (db *DB) getInstance() (tx *DB) {
 func (db *DB) execute(sql string) (*dialectors.ResultSet, error) {
 	tx := db.getInstance()
 
+	// 开启 explain/profile 时为语句添加执行计划前缀
+	if tx.explain != "" {
+		sql = fmt.Sprintf(`%s format="%s" %s`, tx.explain, tx.explainFormat, sql)
+	}
 	tx.sql = sql
+	defer tx.teardown()
+
+	// 试运行模式只记录语句, 返回不含数据行的空结果集
+	if tx.statement != nil {
+		if tx.debug {
+			tx.logger.Info("试运行nGQL", logger.KeyNGQL, tx.logSql(sql))
+		}
+		tx.statement.add(sql)
+		return dialectors.NewResultSet(nil)
+	}
 	if tx.debug {
-		tx.logger.Info(tx.sql)
+		tx.logger.Info("执行nGQL", logger.KeyNGQL, tx.logSql(sql))
 	}
-	defer tx.teardown()
 
-	result, err := tx.dialer.Execute(sql)
+	// 拨号器支持上下文时传入携带语句 Span 的上下文, 使其创建的 Span 成为语句 Span 的子级
+	ctx, span := tx.tracer.Start(tx.ctx, "nebula."+utils.GetStatementKind(sql))
+	defer span.End()
+
+	start := time.Now()
+	var result *dialectors.ResultSet
+	var err error
+	if dialer, ok := tx.dialer.(dialectors.IContextDialer); ok {
+		result, err = dialer.ExecuteContext(ctx, sql)
+	} else {
+		result, err = tx.dialer.Execute(sql)
+	}
+	elapsed := time.Since(start)
+	tx.logSlow(sql, elapsed, result, err)
+	tx.observe(sql, elapsed, result, err)
+	tx.trace(span, sql, result, err)
 	if err != nil {
 		return &dialectors.ResultSet{}, err
 	}
 
 	return result, nil
 }
+
+// toVertexs 解码结果集中的点值, 试运行模式下结果集为空, 直接返回空列表以便继续生成后续语句
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func (db *DB) toVertexs(result *dialectors.ResultSet) ([][]map[string]interface{}, error) {
+	if db.statement != nil {
+		return make([][]map[string]interface{}, 0), nil
+	}
+	return result.ToVertexs()
+}
+
+// withModel 为当前调用链记录操作的点/边模型名称, 用于链路追踪
+//
+// @Author: 罗德
+// @Date: 2024/6/20
+func (db *DB) withModel(name string) (tx *DB) {
+	tx = db.getInstance()
+	tx.model = name
+	return
+}
+
+// vertexsName 返回批量点模型的标签名称(以第一个元素为准), 用于链路追踪
+//
+// @Author: 罗德
+// @Date: 2024/6/20
+func vertexsName(vertexs []model.IVertex) string {
+	if len(vertexs) == 0 {
+		return ""
+	}
+	return vertexs[0].TagName()
+}
+
+// edgesName 返回批量边模型的边名称(以第一个元素为准), 用于链路追踪
+//
+// @Author: 罗德
+// @Date: 2024/6/20
+func edgesName(edges []model.IEdge) string {
+	if len(edges) == 0 {
+		return ""
+	}
+	return edges[0].EdgeName()
+}
+
+// trace 设置语句 Span 的属性: 图空间、语句类型、模型名称、返回行数与错误码
+//
+// @Author: 罗德
+// @Date: 2024/6/20
+func (db *DB) trace(span tracing.ISpan, sql string, result *dialectors.ResultSet, err error) {
+	attrs := []tracing.Attribute{
+		{Key: tracing.AttrDBSystem, Value: tracing.SystemNebula},
+		{Key: tracing.AttrDBOperation, Value: utils.GetStatementKind(sql)},
+		{Key: tracing.AttrDBStatement, Value: db.logSql(sql)},
+		{Key: tracing.AttrErrorCode, Value: int(dialectors.GetErrorCode(err))},
+	}
+	if db.model != "" {
+		attrs = append(attrs, tracing.Attribute{Key: tracing.AttrModel, Value: db.model})
+	}
+	if err == nil && result != nil && result.ResultSet != nil {
+		attrs = append(attrs,
+			tracing.Attribute{Key: tracing.AttrDBName, Value: result.GetSpaceName()},
+			tracing.Attribute{Key: tracing.AttrRows, Value: result.GetRowSize()})
+	}
+	span.SetAttributes(attrs...)
+	if err != nil {
+		span.RecordError(err)
+	}
+}
+
+// logSlow 执行耗时超过慢语句阈值时, 以Warn级别记录nGQL、客户端耗时、服务端耗时和图空间
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+func (db *DB) logSlow(sql string, elapsed time.Duration, result *dialectors.ResultSet, err error) {
+	if db.slowThreshold <= 0 || elapsed < db.slowThreshold {
+		return
+	}
+
+	keysAndValues := []interface{}{
+		logger.KeyNGQL, db.logSql(sql),
+		logger.KeyDuration, elapsed,
+	}
+	// 执行失败时结果集可能为空, 无法获取服务端耗时和图空间
+	if result != nil && result.ResultSet != nil {
+		keysAndValues = append(keysAndValues,
+			logger.KeyLatency, time.Duration(result.GetLatency())*time.Microsecond,
+			logger.KeySpace, result.GetSpaceName())
+	}
+	if err != nil {
+		keysAndValues = append(keysAndValues, logger.KeyError, err.Error())
+	}
+	db.logger.Warn("慢nGQL", keysAndValues...)
+}
+
+// observe 上报语句指标: 语句类型、错误码、客户端耗时与服务端耗时
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func (db *DB) observe(sql string, elapsed time.Duration, result *dialectors.ResultSet, err error) {
+	var server time.Duration
+	if err == nil && result != nil && result.ResultSet != nil {
+		server = time.Duration(result.GetLatency()) * time.Microsecond
+	}
+	db.metrics.ObserveStatement(utils.GetStatementKind(sql), int(dialectors.GetErrorCode(err)), elapsed, server)
+}
+
+// logSql 返回用于日志输出的nGQL, 开启脱敏时属性值会被替换为占位符
+//
+// @Author: 罗德
+// @Date: 2024/6/18
+func (db *DB) logSql(sql string) string {
+	if db.redact {
+		return logger.Redact(sql)
+	}
+	return sql
+}
@@ -0,0 +1,180 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"nebula-orm-go/config"
+	"nebula-orm-go/dialectors"
+	"nebula-orm-go/model"
+	"nebula-orm-go/tracing"
+	"nebula-orm-go/utils"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 记录上报的语句指标
+type recordingSink struct {
+	mu         sync.Mutex
+	statements []observedStatement
+}
+
+type observedStatement struct {
+	kind   string
+	code   int
+	client time.Duration
+}
+
+func (s *recordingSink) ObserveStatement(kind string, code int, client, _ time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statements = append(s.statements, observedStatement{kind: kind, code: code, client: client})
+}
+
+func (s *recordingSink) ObserveSessionWait(time.Duration) {}
+func (s *recordingSink) SetSessionsInUse(int64)           {}
+
+// 每条语句执行后按语句类型与错误码上报指标, 成功为0
+func TestExecuteMetrics(t *testing.T) {
+	sink := &recordingSink{}
+	db, dialer := openMock(t, config.WithMetrics(sink))
+	dialer.Expect("fetch prop on person 'a' yield vertex as v").WillReturnRows([]string{"v"})
+	dialer.Expect("lookup on person yield id(vertex)").WillReturnErrorCode(nebula.ErrorCode_E_SEMANTIC_ERROR, "没有索引")
+	dialer.Expect("insert vertex person() values 'a':()").WillReturnError(errTest)
+
+	if _, err := db.Execute("fetch prop on person 'a' yield vertex as v"); err != nil {
+		t.Fatal(err)
+	}
+	for _, sql := range []string{"lookup on person yield id(vertex)", "insert vertex person() values 'a':()"} {
+		if _, err := db.Execute(sql); err == nil {
+			t.Fatalf("%s 应返回错误", sql)
+		}
+	}
+
+	want := []observedStatement{
+		{kind: utils.StatementFetch, code: 0},
+		{kind: utils.StatementLookup, code: int(nebula.ErrorCode_E_SEMANTIC_ERROR)},
+		{kind: utils.StatementInsertVertex, code: int(nebula.ErrorCode_E_RPC_FAILURE)},
+	}
+	if len(sink.statements) != len(want) {
+		t.Fatalf("上报了 %d 条语句指标: %+v", len(sink.statements), sink.statements)
+	}
+	for i, got := range sink.statements {
+		if got.kind != want[i].kind || got.code != want[i].code || got.client < 0 {
+			t.Errorf("第 %d 条语句指标 = %+v, 期望 %+v", i+1, got, want[i])
+		}
+	}
+}
+
+type spanKey struct{}
+
+// 记录创建的 Span, Start 返回的上下文携带新建的 Span
+type recordingTracer struct {
+	spans []*recordingSpan
+}
+
+type recordingSpan struct {
+	name   string
+	parent *recordingSpan
+	attrs  map[string]interface{}
+	errs   []error
+	ended  bool
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string) (context.Context, tracing.ISpan) {
+	parent, _ := ctx.Value(spanKey{}).(*recordingSpan)
+	span := &recordingSpan{name: name, parent: parent, attrs: make(map[string]interface{})}
+	r.spans = append(r.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...tracing.Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) { s.errs = append(s.errs, err) }
+func (s *recordingSpan) End()                  { s.ended = true }
+
+// 支持上下文的拨号器, 记录执行时上下文中的 Span
+type contextDialer struct {
+	dialectors.IDialer
+	spans []*recordingSpan
+}
+
+func (d *contextDialer) ExecuteContext(ctx context.Context, sql string) (*dialectors.ResultSet, error) {
+	span, _ := ctx.Value(spanKey{}).(*recordingSpan)
+	d.spans = append(d.spans, span)
+	return d.Execute(sql)
+}
+
+// 每条语句创建一个 Span, 以 WithContext 的上下文为父级, 携带该 Span 的上下文传给拨号器
+func TestExecuteTrace(t *testing.T) {
+	dialer := &contextDialer{IDialer: openMemory(t, "create space s(vid_type=FIXED_STRING(16)); use s; "+
+		"create tag save_vertex(name string, level int); insert vertex save_vertex(name, level) values 'a':('甲', 1)").dialer}
+	tracer := &recordingTracer{}
+	db, err := Open(dialer, config.Config{}, config.WithTracer(tracer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, parent := tracer.Start(context.Background(), "request")
+
+	v := saveVertex{VModel: model.VModel{Vid: "a"}}
+	if err = db.WithContext(ctx).Fetch(&v); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.WithContext(ctx).Execute("fetch prop on unknown 'a' yield vertex as v"); err == nil {
+		t.Fatal("查询不存在的标签应返回错误")
+	}
+
+	if len(tracer.spans) != 3 {
+		t.Fatalf("创建了 %d 个 Span, 期望 3 个", len(tracer.spans))
+	}
+	success, failure := tracer.spans[1], tracer.spans[2]
+	if want := []*recordingSpan{success, failure}; !reflect.DeepEqual(dialer.spans, want) {
+		t.Errorf("拨号器收到的 Span = %v, 期望语句 Span", dialer.spans)
+	}
+
+	tests := []struct {
+		name  string
+		span  *recordingSpan
+		attrs map[string]interface{}
+		err   bool
+	}{
+		{name: "成功", span: success, attrs: map[string]interface{}{
+			tracing.AttrDBSystem:    tracing.SystemNebula,
+			tracing.AttrDBOperation: utils.StatementFetch,
+			tracing.AttrDBStatement: "fetch prop on save_vertex 'a' yield id(vertex) as vid_, save_vertex.name as name, save_vertex.level as level",
+			tracing.AttrErrorCode:   0,
+			tracing.AttrModel:       "save_vertex",
+			tracing.AttrDBName:      "s",
+			tracing.AttrRows:        1,
+		}},
+		{name: "失败", span: failure, err: true, attrs: map[string]interface{}{
+			tracing.AttrDBSystem:    tracing.SystemNebula,
+			tracing.AttrDBOperation: utils.StatementFetch,
+			tracing.AttrDBStatement: "fetch prop on unknown 'a' yield vertex as v",
+			tracing.AttrErrorCode:   int(nebula.ErrorCode_E_SEMANTIC_ERROR),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.span.name != "nebula.fetch" || tt.span.parent != parent || !tt.span.ended {
+				t.Errorf("Span = %s, 父级 = %v, 已结束 = %v", tt.span.name, tt.span.parent, tt.span.ended)
+			}
+			if !reflect.DeepEqual(tt.span.attrs, tt.attrs) {
+				t.Errorf("属性 = %v, 期望 %v", tt.span.attrs, tt.attrs)
+			}
+			if tt.err != (len(tt.span.errs) == 1) {
+				t.Fatalf("RecordError = %v", tt.span.errs)
+			}
+			var executeError *dialectors.ExecuteError
+			if tt.err && !errors.As(tt.span.errs[0], &executeError) {
+				t.Errorf("记录的错误 = %v", tt.span.errs[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrStaleObject 乐观锁冲突: 模型的版本号与图数据库中的不一致, 说明点边已被其它调用方修改, 需要重新读取后再更新。
+// 通过 errors.Is(err, orm.ErrStaleObject) 判断, 通过 errors.As 获取 *StaleObjectError 查看详情
+var ErrStaleObject = errors.New("版本号已过期, 点边已被其它调用方修改")
+
+// StaleObjectError 乐观锁冲突的详情
+//
+// @Author: 罗德
+// @Date: 2024/7/3
+type StaleObjectError struct {
+	Model   string // 点的标签名或边类型名称
+	Version int64  // 更新时模型的版本号
+	Current *int64 // 图数据库中的版本号, 无法获取时为nil
+}
+
+// Error 实现 error 接口
+//
+// @Author: 罗德
+// @Date: 2024/7/3
+func (e *StaleObjectError) Error() string {
+	if e.Current == nil {
+		return fmt.Sprintf("%s: %s 的版本号 %d", ErrStaleObject.Error(), e.Model, e.Version)
+	}
+	return fmt.Sprintf("%s: %s 的版本号 %d, 当前版本号 %d", ErrStaleObject.Error(), e.Model, e.Version, *e.Current)
+}
+
+// Is 使 errors.Is(err, ErrStaleObject) 成立
+//
+// @Author: 罗德
+// @Date: 2024/7/3
+func (e *StaleObjectError) Is(target error) bool {
+	return target == ErrStaleObject
+}
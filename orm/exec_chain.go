@@ -5,9 +5,12 @@
 package orm
 
 import (
+	"context"
 	"fmt"
+	"nebula-orm-go/clause"
 	"nebula-orm-go/constants"
 	"nebula-orm-go/model"
+	"nebula-orm-go/plan"
 	"nebula-orm-go/utils"
 	"strings"
 )
@@ -22,6 +25,146 @@ func (db *DB) Debug() (tx *DB) {
 	return
 }
 
+// WithContext 为当前调用链设置上下文, 语句的链路追踪 Span 将以该上下文作为父级。
+//
+// @Author: 罗德
+// @Date: 2024/6/20
+func (db *DB) WithContext(ctx context.Context) (tx *DB) {
+	tx = db.getInstance()
+	tx.ctx = ctx
+	return
+}
+
+// DryRun 开启试运行模式, 之后的方法只生成nGQL而不发送给graphd, 生成的语句通过 Statement() 获取。
+// 试运行时查询返回空结果集, 插入后钩子不会被调用, 插入前/更新前/删除前钩子仍会调用以保证语句与真实执行一致。
+// stmt := db.DryRun(); stmt.DeleteVertexBatch(vertexs); fmt.Println(stmt.Statement())
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func (db *DB) DryRun() (tx *DB) {
+	tx = db.getInstance()
+	tx.statement = &Statement{}
+	return
+}
+
+// Statement 返回试运行模式下记录的语句, 未开启试运行时返回nil
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func (db *DB) Statement() *Statement {
+	return db.statement
+}
+
+// BatchSize 设置分块写入(InsertVertexChunked/InsertEdgeChunked)时每条语句包含的点/边数量, 覆盖 config.BatchSize。
+// 单条语句过大可能超过 graphd 的语句长度限制, 属性较多时应适当调小。
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func (db *DB) BatchSize(size int) (tx *DB) {
+	tx = db.getInstance()
+	if size > 0 {
+		tx.batchSize = size
+	}
+	return
+}
+
+// Concurrency 设置分块写入时同时执行的语句数量, 覆盖 config.BatchConcurrency, 不应超过连接池大小。
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func (db *DB) Concurrency(concurrency int) (tx *DB) {
+	tx = db.getInstance()
+	if concurrency > 0 {
+		tx.batchConcurrency = concurrency
+	}
+	return
+}
+
+// Model 指定要操作的点/边模型实例, 配合 Updates/Upserts 按结构体或 map 更新多个属性。
+// db.Model(&vertex).Select("chain_key", "parent_key").When(clause.Eq("parent_key", "无")).Updates(vertex)
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func (db *DB) Model(value interface{}) (tx *DB) {
+	tx = db.getInstance()
+	tx.dest = value
+	return
+}
+
+// Select 指定只更新的属性(属性名或结构体字段名), 指定后零值字段也会更新。
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func (db *DB) Select(fields ...string) (tx *DB) {
+	tx = db.getInstance()
+	tx.selects = append(tx.selects[:len(tx.selects):len(tx.selects)], fields...)
+	return
+}
+
+// Omit 指定不更新的属性(属性名或结构体字段名)。
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func (db *DB) Omit(fields ...string) (tx *DB) {
+	tx = db.getInstance()
+	tx.omits = append(tx.omits[:len(tx.omits):len(tx.omits)], fields...)
+	return
+}
+
+// When 指定 update/upsert 的更新条件, 条件不成立时不更新, 多次调用时条件以 and 组合。
+// db.Model(&vertex).When(clause.And(clause.Eq("parent_key", "无"), clause.IsNotNull("chain_key"))).Updates(values)
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func (db *DB) When(cond clause.ICondition) (tx *DB) {
+	tx = db.getInstance()
+	if tx.when == nil {
+		tx.when = cond
+	} else {
+		tx.when = clause.And(tx.when, cond)
+	}
+	return
+}
+
+// Explain 为之后执行的语句添加 explain 前缀, 只生成执行计划而不真正执行语句, 通过 ResultSet.Plan() 获取解析后的计划树。
+// format 可选 row(默认)、dot、tck。
+// EXPLAIN format="row" GO FROM "player100" OVER follow YIELD dst(edge);
+// https://docs.nebula-graph.com.cn/3.6.0/3.ngql-guide/17.query-tuning-statements/1.explain-and-profile/
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func (db *DB) Explain(format ...string) (tx *DB) {
+	tx = db.getInstance()
+	tx.explain = "explain"
+	tx.explainFormat = explainFormat(format)
+	return
+}
+
+// Profile 为之后执行的语句添加 profile 前缀, 执行语句并返回包含各算子行数与耗时的执行计划, 通过 ResultSet.Plan() 获取解析后的计划树。
+// format 可选 row(默认)、dot、tck。
+// PROFILE format="row" GO FROM "player100" OVER follow YIELD dst(edge);
+// https://docs.nebula-graph.com.cn/3.6.0/3.ngql-guide/17.query-tuning-statements/1.explain-and-profile/
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func (db *DB) Profile(format ...string) (tx *DB) {
+	tx = db.getInstance()
+	tx.explain = "profile"
+	tx.explainFormat = explainFormat(format)
+	return
+}
+
+// 获取执行计划格式, 未指定时默认为 row
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func explainFormat(format []string) string {
+	if len(format) == 0 || format[0] == "" {
+		return plan.FormatRow
+	}
+	return format[0]
+}
+
 // Match 语句使用的路径类型是trail，这意味着点可以重复出现，但边不能重复
 // MATCH (v)-[e:follow*1..2]->(v2) WHERE id(v) == "player100" RETURN id(v2) AS destination; # 查询 player100 1~2 跳内的朋友。
 // https://docs.nebula-graph.com.cn/3.6.0/3.ngql-guide/7.general-query-statements/2.match/
@@ -30,7 +173,7 @@ func (db *DB) Debug() (tx *DB) {
 // @Date: 2024/5/27
 func (db *DB) Match(match string) (tx *DB) {
 	tx = db.getInstance()
-	tx.sql += fmt.Sprintf(" match %d ", match)
+	tx.sql += fmt.Sprintf(" match %s ", match)
 	return
 }
 
@@ -23,7 +23,7 @@ func (db *DB) Execute(sql string) (*dialectors.ResultSet, error) {
 // @Date: 2024/5/28
 func (db *DB) ExecuteAndParse(sql string, in interface{}) error {
 	nResult, err := db.execute(sql)
-	if err != nil {
+	if err != nil || db.statement != nil {
 		return err
 	}
 	return nResult.UnmarshalResultSet(in)
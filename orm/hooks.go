@@ -0,0 +1,47 @@
+package orm
+
+import "nebula-orm-go/model"
+
+// callBeforeInsert 调用模型的插入前钩子, 模型未实现钩子时直接返回nil
+//
+// @Author: 罗德
+// @Date: 2024/6/17
+func callBeforeInsert(in interface{}) error {
+	if hook, ok := in.(model.IBeforeInsert); ok {
+		return hook.BeforeInsert()
+	}
+	return nil
+}
+
+// callAfterInsert 调用模型的插入后钩子, 模型未实现钩子时直接返回nil
+//
+// @Author: 罗德
+// @Date: 2024/6/17
+func callAfterInsert(in interface{}) error {
+	if hook, ok := in.(model.IAfterInsert); ok {
+		return hook.AfterInsert()
+	}
+	return nil
+}
+
+// callBeforeUpdate 调用模型的更新前钩子, 模型未实现钩子时直接返回nil
+//
+// @Author: 罗德
+// @Date: 2024/6/17
+func callBeforeUpdate(in interface{}) error {
+	if hook, ok := in.(model.IBeforeUpdate); ok {
+		return hook.BeforeUpdate()
+	}
+	return nil
+}
+
+// callBeforeDelete 调用模型的删除前钩子, 模型未实现钩子时直接返回nil
+//
+// @Author: 罗德
+// @Date: 2024/6/17
+func callBeforeDelete(in interface{}) error {
+	if hook, ok := in.(model.IBeforeDelete); ok {
+		return hook.BeforeDelete()
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package orm
+
+import (
+	"errors"
+	"testing"
+)
+
+// 插入前后、更新前、删除前钩子的调用顺序与语句执行
+func TestHooks(t *testing.T) {
+	db, dialer := openMock(t)
+	dialer.Expect("insert vertex hook_vertex(name) values 'a':('甲')")
+	dialer.Expect("delete vertex 'a' with edge")
+
+	v := &hookVertex{Name: "甲"}
+	v.Vid = "a"
+	if err := db.InsertVertex(v); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeleteVertex(v); err != nil {
+		t.Fatal(err)
+	}
+	assertStrings(t, "钩子", v.calls, []string{"BeforeInsert", "AfterInsert", "BeforeDelete"})
+}
+
+// 插入前钩子返回错误时不执行语句, 执行失败时不调用插入后钩子
+func TestHooksAbort(t *testing.T) {
+	db, dialer := openMock(t)
+	v := &hookVertex{Name: "甲", fail: "BeforeInsert"}
+	v.Vid = "a"
+	if err := db.InsertVertex(v); err == nil {
+		t.Fatal("插入前钩子失败时应返回错误")
+	}
+	if len(dialer.Executed()) != 0 {
+		t.Fatalf("插入前钩子失败时不应执行语句: %q", dialer.Executed())
+	}
+
+	dialer.Expect("insert vertex hook_vertex(name) values 'a':('甲')").WillReturnError(errTest)
+	v = &hookVertex{Name: "甲"}
+	v.Vid = "a"
+	if err := db.InsertVertex(v); !errors.Is(err, errTest) {
+		t.Fatalf("错误 = %v, 期望 %v", err, errTest)
+	}
+	assertStrings(t, "钩子", v.calls, []string{"BeforeInsert"})
+}
+
+// 试运行只记录语句: 插入前钩子照常调用, 插入后钩子不调用, 也不会发送给拨号器
+func TestDryRunHooks(t *testing.T) {
+	db, dialer := openMock(t)
+	tx := db.DryRun()
+	v := &hookVertex{Name: "甲"}
+	v.Vid = "a"
+	if err := tx.InsertVertex(v); err != nil {
+		t.Fatal(err)
+	}
+	if len(dialer.Executed()) != 0 {
+		t.Fatalf("试运行不应执行语句: %q", dialer.Executed())
+	}
+	assertStrings(t, "钩子", v.calls, []string{"BeforeInsert"})
+	assertStrings(t, "语句", tx.Statement().NGQLs(), []string{"insert vertex hook_vertex(name) values 'a':('甲')"})
+}
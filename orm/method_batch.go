@@ -0,0 +1,285 @@
+package orm
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"nebula-orm-go/constants"
+	converts2 "nebula-orm-go/converts"
+	"nebula-orm-go/model"
+	"sync"
+	"time"
+)
+
+// ChunkResult 分块写入中单个分块的执行结果
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+type ChunkResult struct {
+	Index   int             // 分块序号, 从0开始
+	Offset  int             // 分块第一个元素在输入切片中的下标
+	Size    int             // 分块包含的点/边数量
+	Elapsed time.Duration   // 分块语句的客户端耗时, 未执行时为0
+	Err     error           // 分块执行失败的原因, 成功时为nil
+	Vertexs []model.IVertex // 分块包含的点(写入点时)
+	Edges   []model.IEdge   // 分块包含的边(写入边时)
+}
+
+// BatchReport 分块写入的执行报告, Chunks 按输入顺序排列, 与执行完成的先后无关
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+type BatchReport struct {
+	Total  int            // 输入的点/边总数
+	Chunks []*ChunkResult // 每个分块的执行结果
+}
+
+// Succeeded 返回写入成功的点/边数量
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func (r *BatchReport) Succeeded() int {
+	count := 0
+	for _, chunk := range r.Chunks {
+		if chunk.Err == nil {
+			count += chunk.Size
+		}
+	}
+	return count
+}
+
+// Failed 返回执行失败的分块
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func (r *BatchReport) Failed() []*ChunkResult {
+	var failed []*ChunkResult
+	for _, chunk := range r.Chunks {
+		if chunk.Err != nil {
+			failed = append(failed, chunk)
+		}
+	}
+	return failed
+}
+
+// FailedVertexs 返回写入失败的点, 可直接再次传入 InsertVertexChunked 重试
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func (r *BatchReport) FailedVertexs() []model.IVertex {
+	var vertexs []model.IVertex
+	for _, chunk := range r.Failed() {
+		vertexs = append(vertexs, chunk.Vertexs...)
+	}
+	return vertexs
+}
+
+// FailedEdges 返回写入失败的边, 可直接再次传入 InsertEdgeChunked 重试
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func (r *BatchReport) FailedEdges() []model.IEdge {
+	var edges []model.IEdge
+	for _, chunk := range r.Failed() {
+		edges = append(edges, chunk.Edges...)
+	}
+	return edges
+}
+
+// Err 汇总失败分块的错误, 全部成功时返回nil, 否则返回第一个失败分块的错误并附带失败数量
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func (r *BatchReport) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	return errors.Wrapf(failed[0].Err, "分块写入失败 %d/%d 块, 第一个失败的分块序号为 %d",
+		len(failed), len(r.Chunks), failed[0].Index)
+}
+
+// InsertVertexChunked 按 BatchSize 将点切分为多条 insert vertex 语句, 以 Concurrency 限制的并发数执行,
+// 返回每个分块的执行报告, 失败分块中的点可以通过 BatchReport.FailedVertexs 获取后重试。
+// 所有点需要属于同一个标签且属性列一致, 混合不同的点模型时返回错误。
+// 插入前钩子在生成语句前对全部点调用, 任一钩子返回错误时不执行任何分块; 插入后钩子只对写入成功的分块调用。
+// 某个分块失败不会中止其它分块, 返回的 error 为 BatchReport.Err()。
+// 与 InsertVertexBatch 相同, 生成VID的非指针元素在 vertexs 中被替换为副本, 分块报告中的点也是替换后的元素。
+//
+// 参数:
+// vertexs ([]model.IVertex): 点实体切片的接口，需实现IVertex接口, 同一批点需要属于同一个标签
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func (db *DB) InsertVertexChunked(vertexs []model.IVertex) (*BatchReport, error) {
+	if len(vertexs) == 0 {
+		return nil, fmt.Errorf("参数为空")
+	}
+	// 分块前校验标签与属性列, 避免部分分块写入后才发现模型不一致
+	if groups := converts2.GroupVertexs(vertexs); len(groups) > 1 {
+		return nil, fmt.Errorf("点包含%d种标签或属性列, 请先使用 converts.GroupVertexs 分组后逐组写入", len(groups))
+	}
+	for i, vertex := range vertexs {
+		if err := callBeforeInsert(vertex); err != nil {
+			return nil, err
+		}
+		vertex, err := generateVid(vertex)
+		if err != nil {
+			return nil, err
+		}
+		vertexs[i] = vertex
+		if err := db.checkVid(vertex); err != nil {
+			return nil, err
+		}
+	}
+
+	tx := db.withModel(vertexsName(vertexs))
+	report := &BatchReport{Total: len(vertexs)}
+	size := tx.chunkSize()
+	for offset := 0; offset < len(vertexs); offset += size {
+		end := offset + size
+		if end > len(vertexs) {
+			end = len(vertexs)
+		}
+		report.Chunks = append(report.Chunks, &ChunkResult{
+			Index:   len(report.Chunks),
+			Offset:  offset,
+			Size:    end - offset,
+			Vertexs: vertexs[offset:end],
+		})
+	}
+
+	tx.runChunks(report, func(chunk *ChunkResult) error {
+		sql, err := converts2.ConvertToInsertVertexBatchSql(chunk.Vertexs)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.fork().execute(sql); err != nil || tx.statement != nil {
+			return err
+		}
+		for _, vertex := range chunk.Vertexs {
+			if err = callAfterInsert(vertex); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return report, report.Err()
+}
+
+// InsertEdgeChunked 按 BatchSize 将边切分为多条 insert edge 语句, 以 Concurrency 限制的并发数执行,
+// 返回每个分块的执行报告, 失败分块中的边可以通过 BatchReport.FailedEdges 获取后重试。
+// 钩子与错误的处理方式与 InsertVertexChunked 相同。
+//
+// 参数:
+// edges ([]model.IEdge): 边实体切片的接口，需实现IEdge接口, 同一批边需要属于同一个边类型
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func (db *DB) InsertEdgeChunked(edges []model.IEdge) (*BatchReport, error) {
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("参数为空")
+	}
+	// 分块前校验边类型与属性列, 避免部分分块写入后才发现模型不一致
+	if groups := converts2.GroupEdges(edges); len(groups) > 1 {
+		return nil, fmt.Errorf("边包含%d种边类型或属性列, 请先使用 converts.GroupEdges 分组后逐组写入", len(groups))
+	}
+	for _, edge := range edges {
+		if err := callBeforeInsert(edge); err != nil {
+			return nil, err
+		}
+		if err := db.checkVid(edge); err != nil {
+			return nil, err
+		}
+	}
+
+	tx := db.withModel(edgesName(edges))
+	report := &BatchReport{Total: len(edges)}
+	size := tx.chunkSize()
+	for offset := 0; offset < len(edges); offset += size {
+		end := offset + size
+		if end > len(edges) {
+			end = len(edges)
+		}
+		report.Chunks = append(report.Chunks, &ChunkResult{
+			Index:  len(report.Chunks),
+			Offset: offset,
+			Size:   end - offset,
+			Edges:  edges[offset:end],
+		})
+	}
+
+	tx.runChunks(report, func(chunk *ChunkResult) error {
+		sql, err := converts2.ConvertToInsertEdgeBatchSql(chunk.Edges)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.fork().execute(sql); err != nil || tx.statement != nil {
+			return err
+		}
+		for _, edge := range chunk.Edges {
+			if err = callAfterInsert(edge); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return report, report.Err()
+}
+
+// runChunks 以有界并发执行全部分块, 结果写回各自的 ChunkResult。
+// 上下文被取消后尚未开始的分块不再执行, 以上下文的错误标记为失败; 试运行模式下按顺序执行以保证记录的语句顺序稳定。
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func (db *DB) runChunks(report *BatchReport, run func(chunk *ChunkResult) error) {
+	concurrency := db.batchConcurrency
+	if concurrency <= 0 || db.statement != nil {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, chunk := range report.Chunks {
+		sem <- struct{}{}
+		if err := db.ctx.Err(); err != nil {
+			<-sem
+			chunk.Err = err
+			continue
+		}
+		wg.Add(1)
+		go func(chunk *ChunkResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			start := time.Now()
+			chunk.Err = run(chunk)
+			chunk.Elapsed = time.Since(start)
+		}(chunk)
+	}
+	wg.Wait()
+}
+
+// chunkSize 返回分块大小, 未通过 Open 创建的实例没有配置时使用默认值
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func (db *DB) chunkSize() int {
+	if db.batchSize <= 0 {
+		return constants.DefaultBatchSize
+	}
+	return db.batchSize
+}
+
+// fork 复制当前调用链的状态, 用于并发执行语句, 避免多个协程同时修改同一个调用链实例
+//
+// @Author: 罗德
+// @Date: 2024/6/26
+func (db *DB) fork() *DB {
+	tx := *db
+	if tx.parent == nil {
+		tx.parent = db
+	}
+	tx.teardown = func() {}
+	return &tx
+}
@@ -0,0 +1,86 @@
+package orm
+
+import (
+	"errors"
+	"nebula-orm-go/model"
+	"testing"
+)
+
+// 按 BatchSize 分块执行, 失败分块中的点可以取出重试, 插入后钩子只对成功的分块调用
+func TestInsertVertexChunked(t *testing.T) {
+	db, dialer := openMock(t)
+	dialer.Expect("insert vertex hook_vertex(name) values 'a':('甲'), 'b':('乙')")
+	dialer.Expect("insert vertex hook_vertex(name) values 'c':('丙'), 'd':('丁')").WillReturnError(errTest)
+	dialer.Expect("insert vertex hook_vertex(name) values 'e':('戊')")
+
+	var vertexs []model.IVertex
+	for i, name := range []string{"甲", "乙", "丙", "丁", "戊"} {
+		v := &hookVertex{Name: name}
+		v.Vid = string(rune('a' + i))
+		vertexs = append(vertexs, v)
+	}
+	report, err := db.BatchSize(2).Concurrency(1).InsertVertexChunked(vertexs)
+	if !errors.Is(err, errTest) {
+		t.Fatalf("错误 = %v, 期望包含 %v", err, errTest)
+	}
+	if len(report.Chunks) != 3 || report.Succeeded() != 3 {
+		t.Fatalf("分块数 = %d, 成功数 = %d", len(report.Chunks), report.Succeeded())
+	}
+	failed := report.FailedVertexs()
+	if len(failed) != 2 || failed[0].GetVid() != "c" || failed[1].GetVid() != "d" {
+		t.Fatalf("失败的点 = %v", failed)
+	}
+	for i, vertex := range vertexs {
+		want := []string{"BeforeInsert", "AfterInsert"}
+		if i == 2 || i == 3 {
+			want = want[:1]
+		}
+		assertStrings(t, "钩子", vertex.(*hookVertex).calls, want)
+	}
+}
+
+// 混合不同标签时不执行任何分块, 试运行时记录全部分块语句
+func TestInsertVertexChunkedDryRun(t *testing.T) {
+	db, dialer := openMock(t)
+	mixed := []model.IVertex{
+		&hookVertex{VModel: model.VModel{Vid: "a"}},
+		saveVertex{VModel: model.VModel{Vid: "b"}},
+	}
+	if _, err := db.InsertVertexChunked(mixed); err == nil {
+		t.Fatal("混合标签时应返回错误")
+	}
+
+	tx := db.DryRun().BatchSize(2).Concurrency(1)
+	var vertexs []model.IVertex
+	for _, vid := range []string{"a", "b", "c"} {
+		vertexs = append(vertexs, saveVertex{VModel: model.VModel{Vid: vid}, Level: 1})
+	}
+	if _, err := tx.InsertVertexChunked(vertexs); err != nil {
+		t.Fatal(err)
+	}
+	if len(dialer.Executed()) != 0 {
+		t.Fatalf("试运行不应执行语句: %q", dialer.Executed())
+	}
+	assertStrings(t, "语句", tx.Statement().NGQLs(), []string{
+		"insert vertex save_vertex(name,level) values 'a':('',1), 'b':('',1)",
+		"insert vertex save_vertex(name,level) values 'c':('',1)",
+	})
+}
+
+// 边的分块写入
+func TestInsertEdgeChunked(t *testing.T) {
+	db, dialer := openMock(t)
+	dialer.Expect("insert edge follow(degree) values 'a' -> 'b':(0.5), 'b' -> 'c':(1)")
+
+	edges := []model.IEdge{
+		testEdge{EModel: model.EModel{Src: "a", Dst: "b"}, Degree: 0.5},
+		testEdge{EModel: model.EModel{Src: "b", Dst: "c"}, Degree: 1},
+	}
+	report, err := db.BatchSize(10).InsertEdgeChunked(edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Succeeded() != 2 {
+		t.Fatalf("成功数 = %d, 期望 2", report.Succeeded())
+	}
+}
@@ -13,11 +13,17 @@ import (
 // @Author: 罗德
 // @Date: 2024/6/6
 func (db *DB) DeleteVertex(vertex model.IVertex) error {
+	if err := callBeforeDelete(vertex); err != nil {
+		return err
+	}
+	if err := db.checkVid(vertex); err != nil {
+		return err
+	}
 	sql, err := converts2.ConvertToDeleteVertexSql(vertex)
 	if err != nil {
 		return err
 	}
-	_, err = db.execute(sql)
+	_, err = db.withModel(vertex.TagName()).execute(sql)
 	return err
 }
 
@@ -29,11 +35,19 @@ func (db *DB) DeleteVertex(vertex model.IVertex) error {
 // @Author: 罗德
 // @Date: 2024/6/6
 func (db *DB) DeleteVertexBatch(vertexs []model.IVertex) error {
+	for _, vertex := range vertexs {
+		if err := callBeforeDelete(vertex); err != nil {
+			return err
+		}
+		if err := db.checkVid(vertex); err != nil {
+			return err
+		}
+	}
 	sql, err := converts2.ConvertToDeleteVertexBatchSql(vertexs)
 	if err != nil {
 		return err
 	}
-	_, err = db.execute(sql)
+	_, err = db.withModel(vertexsName(vertexs)).execute(sql)
 	return err
 }
 
@@ -45,11 +59,17 @@ func (db *DB) DeleteVertexBatch(vertexs []model.IVertex) error {
 // @Author: 罗德
 // @Date: 2024/6/6
 func (db *DB) DeleteEdge(edge model.IEdge) error {
+	if err := callBeforeDelete(edge); err != nil {
+		return err
+	}
+	if err := db.checkVid(edge); err != nil {
+		return err
+	}
 	sql, err := converts2.ConvertToDeleteEdgeSql(edge)
 	if err != nil {
 		return err
 	}
-	_, err = db.execute(sql)
+	_, err = db.withModel(edge.EdgeName()).execute(sql)
 	return err
 }
 
@@ -61,10 +81,18 @@ func (db *DB) DeleteEdge(edge model.IEdge) error {
 // @Author: 罗德
 // @Date: 2024/6/6
 func (db *DB) DeleteEdgeBatch(edges []model.IEdge) error {
+	for _, edge := range edges {
+		if err := callBeforeDelete(edge); err != nil {
+			return err
+		}
+		if err := db.checkVid(edge); err != nil {
+			return err
+		}
+	}
 	sql, err := converts2.ConvertToDeleteEdgeBatchSql(edges)
 	if err != nil {
 		return err
 	}
-	_, err = db.execute(sql)
+	_, err = db.withModel(edgesName(edges)).execute(sql)
 	return err
 }
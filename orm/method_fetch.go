@@ -0,0 +1,252 @@
+package orm
+
+import (
+	"fmt"
+	nebula_type "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"nebula-orm-go/clause"
+	"nebula-orm-go/constants"
+	"nebula-orm-go/dialectors"
+	"nebula-orm-go/model"
+	"nebula-orm-go/utils"
+	"reflect"
+	"strings"
+)
+
+// 查询边时 rank(edge) 的别名
+const yieldRank = "rank_"
+
+// NotFoundError Fetch 时部分点边不存在, 通过 errors.Is(err, dialectors.RecordNotFoundError) 判断,
+// 通过 errors.As 获取 *NotFoundError 查看不存在的点边
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+type NotFoundError struct {
+	Vertexs []model.IVertex // 不存在的点
+	Edges   []model.IEdge   // 不存在的边
+}
+
+// Error 实现 error 接口
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (e *NotFoundError) Error() string {
+	var keys []string
+	for _, vertex := range e.Vertexs {
+		keys = append(keys, vertex.TagName()+" "+vidLiteral(vertex.GetVid(), vertex.GetPolicy()))
+	}
+	for _, edge := range e.Edges {
+		keys = append(keys, edge.EdgeName()+" "+edgeKey(edge))
+	}
+	return fmt.Sprintf("%s: %s", dialectors.RecordNotFoundError.Error(), strings.Join(keys, ", "))
+}
+
+// Is 使 errors.Is(err, dialectors.RecordNotFoundError) 成立
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (e *NotFoundError) Is(target error) bool {
+	return target == dialectors.RecordNotFoundError
+}
+
+// Fetch 通过 fetch prop on 查询点边的属性并解码到传入的模型, 比 GetVertexByVid 使用的 match 更快。
+// 同一标签(边类型)的模型合并为一条语句, 例如 fetch prop on tag vid1, vid2 yield ... 与 fetch prop on edge src->dst@rank yield ...,
+// 边的 rank 通过 model.IEdgeRank 指定。VID策略为 PolicyHash 的点边无法与结果对应, 每个单独执行一条语句。
+// 全部语句执行后, 存在不存在的点边时返回 *NotFoundError, 存在的点边仍会解码。
+// db.Fetch(&vertex1, &vertex2, &edge)
+//
+// 参数:
+// models (...interface{}): 点边模型的结构体指针, 需实现IVertex或IEdge接口并设置VID
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (db *DB) Fetch(models ...interface{}) error {
+	if len(models) == 0 {
+		return fmt.Errorf("参数为空")
+	}
+	var groups []*fetchGroup
+	index := make(map[string]*fetchGroup)
+	for _, in := range models {
+		val := reflect.ValueOf(in)
+		if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+			return fmt.Errorf("查询时必须传入模型的结构体指针以解码结果, 实际为 %T", in)
+		}
+		if err := db.checkVid(in); err != nil {
+			return err
+		}
+		var name, key string
+		var hash bool
+		switch m := in.(type) {
+		case model.IVertex:
+			name = m.TagName()
+			key = vidLiteral(m.GetVid(), m.GetPolicy())
+			hash = m.GetPolicy() == constants.PolicyHash
+		case model.IEdge:
+			name = m.EdgeName()
+			key = edgeKey(m)
+			hash = m.GetVidSrcPolicy() == constants.PolicyHash || m.GetVidDstPolicy() == constants.PolicyHash
+		default:
+			return fmt.Errorf("%T 需要实现 model.IVertex 或 model.IEdge 接口", in)
+		}
+		// 同一标签(边类型)且同一结构体的模型合并查询, PolicyHash 的模型单独查询
+		groupKey := name + "|" + val.Type().String()
+		group := index[groupKey]
+		if group == nil || hash {
+			group = &fetchGroup{name: name, models: make(map[string][]interface{})}
+			groups = append(groups, group)
+			if !hash {
+				index[groupKey] = group
+			}
+		}
+		if _, ok := group.models[key]; !ok {
+			group.keys = append(group.keys, key)
+		}
+		group.models[key] = append(group.models[key], in)
+		group.hash = hash
+	}
+
+	missing := &NotFoundError{}
+	for _, group := range groups {
+		if err := db.fetchGroup(group, missing); err != nil {
+			return err
+		}
+	}
+	if db.statement != nil || len(missing.Vertexs)+len(missing.Edges) == 0 {
+		return nil
+	}
+	return missing
+}
+
+// 一条 fetch 语句查询的模型, models 以语句中的 VID(或 src->dst@rank)为键
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+type fetchGroup struct {
+	name   string
+	keys   []string
+	models map[string][]interface{}
+	hash   bool
+}
+
+// 执行一组模型的 fetch 语句, 按结果中的 VID 解码到对应的模型, 不存在的模型记录到 missing
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (db *DB) fetchGroup(group *fetchGroup, missing *NotFoundError) error {
+	first := group.models[group.keys[0]][0]
+	_, isVertex := first.(model.IVertex)
+	result, err := db.withModel(group.name).execute(fetchSql(first, group.name, group.keys))
+	if err != nil {
+		return err
+	}
+
+	found := make(map[string]bool)
+	for _, row := range result.GetRows() {
+		values := row.GetValues()
+		var key string
+		switch {
+		case group.hash:
+			// PolicyHash 的模型单独查询, 返回的行即为该模型
+			key = group.keys[0]
+		case isVertex:
+			key = vidLiteral(utils.NValueToInterface(values[0]), constants.PolicyNothing)
+		default:
+			key = formatEdgeKey(utils.NValueToInterface(values[0]), constants.PolicyNothing,
+				utils.NValueToInterface(values[1]), constants.PolicyNothing, values[2].GetIVal())
+		}
+		for _, in := range group.models[key] {
+			if err = decodeRow(in, result.GetColNames(), values); err != nil {
+				return err
+			}
+		}
+		found[key] = true
+	}
+	for _, key := range group.keys {
+		if found[key] {
+			continue
+		}
+		for _, in := range group.models[key] {
+			if isVertex {
+				missing.Vertexs = append(missing.Vertexs, in.(model.IVertex))
+			} else {
+				missing.Edges = append(missing.Edges, in.(model.IEdge))
+			}
+		}
+	}
+	return nil
+}
+
+// 生成 fetch 语句, 点按 VID、边按 src->dst@rank 查询, 先 yield VID(或起点、终点、rank)再 yield 模型的全部属性。
+// Fetch 与泛型仓储共用该语句
+//
+// 参数:
+// m (interface{}): 点或边模型, 用于生成 yield 的属性
+// name (string): 标签或边类型名称
+// keys ([]string): 已按VID策略处理的 VID 或 edgeKey/formatEdgeKey 生成的边键
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func fetchSql(m interface{}, name string, keys []string) string {
+	if _, ok := m.(model.IVertex); ok {
+		return fmt.Sprintf("fetch prop on %s %s yield id(vertex) as %s%s",
+			name, strings.Join(keys, ","), yieldVid, yieldProps(m, name))
+	}
+	return fmt.Sprintf("fetch prop on %s %s yield src(edge) as %s, dst(edge) as %s, rank(edge) as %s%s",
+		name, strings.Join(keys, ","), yieldSrc, yieldDst, yieldRank, yieldProps(m, name))
+}
+
+// 边在 fetch 语句中的键: src->dst@rank
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func edgeKey(edge model.IEdge) string {
+	var rank int64
+	if r, ok := edge.(model.IEdgeRank); ok {
+		rank = r.GetRank()
+	}
+	return formatEdgeKey(edge.GetVidSrc(), edge.GetVidSrcPolicy(), edge.GetVidDst(), edge.GetVidDstPolicy(), rank)
+}
+
+// 按起点、终点的VID策略拼接边键 src->dst@rank
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func formatEdgeKey(src interface{}, srcPolicy constants.Policy, dst interface{}, dstPolicy constants.Policy, rank int64) string {
+	return fmt.Sprintf("%s->%s@%d", vidLiteral(src, srcPolicy), vidLiteral(dst, dstPolicy), rank)
+}
+
+// 按VID策略生成 VID 字面量, 字符串 VID 通过 clause.Quote 转义, 结果中的 VID 按同样的规则生成键以对应模型
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func vidLiteral(vid interface{}, policy constants.Policy) string {
+	s, ok := vid.(string)
+	if !ok {
+		return utils.GetVidWithPolicy(vid, policy)
+	}
+	if policy == constants.PolicyHash {
+		return "hash(" + clause.Quote(s) + ")"
+	}
+	return clause.Quote(s)
+}
+
+// 将结果集的一行按列名解码到模型的属性字段, VID等非属性字段保持不变, 解码后调用查询后钩子
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func decodeRow(in interface{}, cols []string, values []*nebula_type.Value) error {
+	val := reflect.ValueOf(in).Elem()
+	fieldTagMap := utils.GetStructFieldTagMap(val.Type())
+	for j, col := range cols {
+		fieldPos, ok := fieldTagMap[col]
+		if !ok {
+			continue
+		}
+		if err := utils.SetStructFieldValue(val.Field(fieldPos), val.Type().Field(fieldPos), values[j]); err != nil {
+			return err
+		}
+	}
+	if hook, ok := in.(model.IAfterFind); ok {
+		return hook.AfterFind()
+	}
+	return nil
+}
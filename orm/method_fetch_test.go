@@ -0,0 +1,135 @@
+package orm
+
+import (
+	"errors"
+	"nebula-orm-go/config"
+	"nebula-orm-go/dialectors"
+	"nebula-orm-go/dialectors/memory"
+	"nebula-orm-go/model"
+	"testing"
+)
+
+// 指定 rank 的测试边
+type rankEdge struct {
+	model.EModel
+	Degree float64 `nebula:"degree"`
+	Rank   int64
+}
+
+func (e rankEdge) EdgeName() string {
+	return "follow"
+}
+
+func (e rankEdge) GetRank() int64 {
+	return e.Rank
+}
+
+func (e *rankEdge) SetRank(rank int64) {
+	e.Rank = rank
+}
+
+// 使用内存图引擎打开数据库
+func openMemory(t *testing.T, initSql string) *DB {
+	t.Helper()
+	dialer, err := memory.New(memory.WithInitSql(initSql))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open(dialer, config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// 字符串 VID 中的引号与反斜杠经过转义, 存在的点解码到模型, 不存在的点记录到 NotFoundError
+func TestFetchVertex(t *testing.T) {
+	db := openMemory(t, "create space s(vid_type=FIXED_STRING(16)); use s; "+
+		"create tag save_vertex(name string, level int); "+
+		`insert vertex save_vertex(name, level) values 'a':('甲', 1), 'b\'1':('乙', 2), 'c\\2':('丙', 3)`)
+
+	a := saveVertex{VModel: model.VModel{Vid: "a"}}
+	b := saveVertex{VModel: model.VModel{Vid: "b'1"}}
+	c := saveVertex{VModel: model.VModel{Vid: `c\2`}}
+	missing := saveVertex{VModel: model.VModel{Vid: "x'y"}, Name: "原值"}
+	err := db.Fetch(&a, &b, &c, &missing)
+
+	var notFound *NotFoundError
+	if !errors.Is(err, dialectors.RecordNotFoundError) || !errors.As(err, &notFound) {
+		t.Fatalf("Fetch() error = %v, 期望 *NotFoundError", err)
+	}
+	if len(notFound.Vertexs) != 1 || notFound.Vertexs[0] != &missing || len(notFound.Edges) != 0 {
+		t.Fatalf("不存在的点 = %+v", notFound)
+	}
+	if want := dialectors.RecordNotFoundError.Error() + `: save_vertex 'x\'y'`; err.Error() != want {
+		t.Errorf("Error() = %s, 期望 %s", err.Error(), want)
+	}
+	for _, tt := range []struct {
+		got   saveVertex
+		name  string
+		level int64
+	}{
+		{a, "甲", 1},
+		{b, "乙", 2},
+		{c, "丙", 3},
+		{missing, "原值", 0},
+	} {
+		if tt.got.Name != tt.name || tt.got.Level != tt.level {
+			t.Errorf("点 %v = %+v, 期望 %s %d", tt.got.Vid, tt.got, tt.name, tt.level)
+		}
+	}
+
+	// 仓储使用同样的转义
+	vertexs, err := NewRepo[*saveVertex](db).GetMany("b'1", `c\2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vertexs) != 2 || vertexs[0].Vid != "b'1" || vertexs[1].Vid != `c\2` || vertexs[1].Name != "丙" {
+		t.Fatalf("仓储查询结果 = %+v", vertexs)
+	}
+}
+
+// INT64 类型的 VID 不加引号, 结果中的 VID 与模型对应
+func TestFetchInt64Vid(t *testing.T) {
+	db := openMemory(t, "create space s(vid_type=INT64); use s; "+
+		"create tag save_vertex(name string, level int); "+
+		"insert vertex save_vertex(name, level) values 1:('甲', 1), -2:('乙', 2)")
+
+	a := saveVertex{VModel: model.VModel{Vid: int64(1)}}
+	b := saveVertex{VModel: model.VModel{Vid: int64(-2)}}
+	if err := db.Fetch(&a, &b); err != nil {
+		t.Fatal(err)
+	}
+	if a.Name != "甲" || b.Name != "乙" || b.Level != 2 {
+		t.Fatalf("a = %+v, b = %+v", a, b)
+	}
+
+	missing := saveVertex{VModel: model.VModel{Vid: int64(3)}}
+	if err := db.Fetch(&missing); err == nil || err.Error() != dialectors.RecordNotFoundError.Error()+": save_vertex 3" {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+}
+
+// 边按 src->dst@rank 查询, 相同起点终点不同 rank 的边分别解码
+func TestFetchEdgeRank(t *testing.T) {
+	db := openMemory(t, "create space s(vid_type=FIXED_STRING(16)); use s; "+
+		"create edge follow(degree double); "+
+		`insert edge follow(degree) values 'a'->'b\'1':(0.5), 'a'->'b\'1'@2:(2.0)`)
+
+	edge := func(rank int64) *rankEdge {
+		return &rankEdge{EModel: model.EModel{Src: "a", Dst: "b'1"}, Rank: rank}
+	}
+	first, second, missing := edge(0), edge(2), edge(3)
+	err := db.Fetch(first, second, missing)
+
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) || len(notFound.Edges) != 1 || notFound.Edges[0] != missing {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if want := dialectors.RecordNotFoundError.Error() + `: follow 'a'->'b\'1'@3`; err.Error() != want {
+		t.Errorf("Error() = %s, 期望 %s", err.Error(), want)
+	}
+	if first.Degree != 0.5 || second.Degree != 2 || missing.Degree != 0 {
+		t.Fatalf("degree = %v %v %v", first.Degree, second.Degree, missing.Degree)
+	}
+}
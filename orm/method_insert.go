@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"fmt"
 	converts2 "nebula-orm-go/converts"
 	"nebula-orm-go/model"
 )
@@ -14,12 +15,25 @@ import (
 // @Author: 罗德
 // @Date: 2024/5/28
 func (db *DB) InsertVertex(vertex model.IVertex) error {
+	if err := callBeforeInsert(vertex); err != nil {
+		return err
+	}
+	vertex, err := generateVid(vertex)
+	if err != nil {
+		return err
+	}
+	if err := db.checkVid(vertex); err != nil {
+		return err
+	}
 	sql, err := converts2.ConvertToInsertVertexSql(vertex)
 	if err != nil {
 		return err
 	}
-	_, err = db.execute(sql)
-	return err
+	_, err = db.withModel(vertex.TagName()).execute(sql)
+	if err != nil || db.statement != nil {
+		return err
+	}
+	return callAfterInsert(vertex)
 }
 
 // InsertVertexIgnore 根据给定的顶点模型插入一个顶点到图数据库
@@ -34,16 +48,30 @@ func (db *DB) InsertVertex(vertex model.IVertex) error {
 // @Author: 罗德
 // @Date: 2024/5/28
 func (db *DB) InsertVertexIgnore(vertex model.IVertex) error {
+	if err := callBeforeInsert(vertex); err != nil {
+		return err
+	}
+	vertex, err := generateVid(vertex)
+	if err != nil {
+		return err
+	}
+	if err := db.checkVid(vertex); err != nil {
+		return err
+	}
 	sql, err := converts2.ConvertToInsertVertexIgnoreSql(vertex)
 	if err != nil {
 		return err
 	}
-	_, err = db.execute(sql)
-	return err
+	_, err = db.withModel(vertex.TagName()).execute(sql)
+	if err != nil || db.statement != nil {
+		return err
+	}
+	return callAfterInsert(vertex)
 }
 
 // InsertVertexBatch 根据给定的顶点模型插入多个顶点到图数据库
 // 存在则默认覆盖结构体所有属性, 参数 vertex 没有被明确赋值的属性也会被覆盖为零值
+// VID为空的点按 model.IVidGenerate 生成VID: 指针元素直接写入模型, 非指针元素会被替换为写入VID后的副本, 即会修改传入的 vertexs 切片
 //
 // 参数:
 // vertexs (model.IVertex): 点实体切片的接口，需实现IVertex接口
@@ -51,12 +79,33 @@ func (db *DB) InsertVertexIgnore(vertex model.IVertex) error {
 // @Author: 罗德
 // @Date: 2024/5/28
 func (db *DB) InsertVertexBatch(vertexs []model.IVertex) error {
+	for i, vertex := range vertexs {
+		if err := callBeforeInsert(vertex); err != nil {
+			return err
+		}
+		vertex, err := generateVid(vertex)
+		if err != nil {
+			return err
+		}
+		vertexs[i] = vertex
+		if err := db.checkVid(vertex); err != nil {
+			return err
+		}
+	}
 	sql, err := converts2.ConvertToInsertVertexBatchSql(vertexs)
 	if err != nil {
 		return err
 	}
-	_, err = db.execute(sql)
-	return err
+	_, err = db.withModel(vertexsName(vertexs)).execute(sql)
+	if err != nil || db.statement != nil {
+		return err
+	}
+	for _, vertex := range vertexs {
+		if err = callAfterInsert(vertex); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // InsertEdge 根据给定的边模型插入一条边到图数据库
@@ -68,12 +117,21 @@ func (db *DB) InsertVertexBatch(vertexs []model.IVertex) error {
 // @Author: 罗德
 // @Date: 2024/5/28
 func (db *DB) InsertEdge(edge model.IEdge) error {
+	if err := callBeforeInsert(edge); err != nil {
+		return err
+	}
+	if err := db.checkVid(edge); err != nil {
+		return err
+	}
 	sql, err := converts2.ConvertToInsertEdgeSql(edge)
 	if err != nil {
 		return err
 	}
-	_, err = db.execute(sql)
-	return err
+	_, err = db.withModel(edge.EdgeName()).execute(sql)
+	if err != nil || db.statement != nil {
+		return err
+	}
+	return callAfterInsert(edge)
 }
 
 // InsertEdgeIgnore 根据给定的边模型插入一条边到图数据库
@@ -88,12 +146,21 @@ func (db *DB) InsertEdge(edge model.IEdge) error {
 // @Author: 罗德
 // @Date: 2024/5/28
 func (db *DB) InsertEdgeIgnore(edge model.IEdge) error {
+	if err := callBeforeInsert(edge); err != nil {
+		return err
+	}
+	if err := db.checkVid(edge); err != nil {
+		return err
+	}
 	sql, err := converts2.ConvertToInsertEdgeIgnoreSql(edge)
 	if err != nil {
 		return err
 	}
-	_, err = db.execute(sql)
-	return err
+	_, err = db.withModel(edge.EdgeName()).execute(sql)
+	if err != nil || db.statement != nil {
+		return err
+	}
+	return callAfterInsert(edge)
 }
 
 // InsertEdgeBatch 根据给定的边模型批量插入边到图数据库
@@ -105,10 +172,113 @@ func (db *DB) InsertEdgeIgnore(edge model.IEdge) error {
 // @Author: 罗德
 // @Date: 2024/5/28
 func (db *DB) InsertEdgeBatch(edges []model.IEdge) error {
+	for _, edge := range edges {
+		if err := callBeforeInsert(edge); err != nil {
+			return err
+		}
+		if err := db.checkVid(edge); err != nil {
+			return err
+		}
+	}
 	sql, err := converts2.ConvertToInsertEdgeBatchSql(edges)
 	if err != nil {
 		return err
 	}
-	_, err = db.execute(sql)
-	return err
+	_, err = db.withModel(edgesName(edges)).execute(sql)
+	if err != nil || db.statement != nil {
+		return err
+	}
+	for _, edge := range edges {
+		if err = callAfterInsert(edge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InsertVertexGrouped 插入可以混合不同点模型的一批点, 按标签与属性列分组, 每组执行一条 insert vertex 语句。
+// 分组按首次出现的顺序依次执行, 某一组失败时停止执行后续分组并返回错误, 已执行的分组不会回滚。
+// 存在则默认覆盖结构体所有属性, 参数 vertexs 没有被明确赋值的属性也会被覆盖为零值
+// 生成VID时与 InsertVertexBatch 一样会替换 vertexs 中的非指针元素
+//
+// 参数:
+// vertexs ([]model.IVertex): 点实体切片的接口，需实现IVertex接口
+//
+// @Author: 罗德
+// @Date: 2024/6/27
+func (db *DB) InsertVertexGrouped(vertexs []model.IVertex) error {
+	for i, vertex := range vertexs {
+		if err := callBeforeInsert(vertex); err != nil {
+			return err
+		}
+		vertex, err := generateVid(vertex)
+		if err != nil {
+			return err
+		}
+		vertexs[i] = vertex
+		if err := db.checkVid(vertex); err != nil {
+			return err
+		}
+	}
+	if len(vertexs) == 0 {
+		return fmt.Errorf("参数为空")
+	}
+	for _, group := range converts2.GroupVertexs(vertexs) {
+		sql, err := converts2.ConvertToInsertVertexBatchSql(group)
+		if err != nil {
+			return err
+		}
+		if _, err = db.withModel(vertexsName(group)).execute(sql); err != nil {
+			return err
+		}
+		if db.statement != nil {
+			continue
+		}
+		for _, vertex := range group {
+			if err = callAfterInsert(vertex); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// InsertEdgeGrouped 插入可以混合不同边模型的一批边, 按边类型与属性列分组, 每组执行一条 insert edge 语句。
+// 分组按首次出现的顺序依次执行, 某一组失败时停止执行后续分组并返回错误, 已执行的分组不会回滚。
+//
+// 参数:
+// edges ([]model.IEdge): 边实体切片的接口，需实现IEdge接口
+//
+// @Author: 罗德
+// @Date: 2024/6/27
+func (db *DB) InsertEdgeGrouped(edges []model.IEdge) error {
+	for _, edge := range edges {
+		if err := callBeforeInsert(edge); err != nil {
+			return err
+		}
+		if err := db.checkVid(edge); err != nil {
+			return err
+		}
+	}
+	if len(edges) == 0 {
+		return fmt.Errorf("参数为空")
+	}
+	for _, group := range converts2.GroupEdges(edges) {
+		sql, err := converts2.ConvertToInsertEdgeBatchSql(group)
+		if err != nil {
+			return err
+		}
+		if _, err = db.withModel(edgesName(group)).execute(sql); err != nil {
+			return err
+		}
+		if db.statement != nil {
+			continue
+		}
+		for _, edge := range group {
+			if err = callAfterInsert(edge); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
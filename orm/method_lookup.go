@@ -0,0 +1,353 @@
+package orm
+
+import (
+	"fmt"
+	"nebula-orm-go/clause"
+	"nebula-orm-go/model"
+	"nebula-orm-go/utils"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// LookupQuery 基于索引的属性查询: lookup on tag|edge where ... yield ..., 由 DB.Lookup 创建。
+// 与调用链的 Where(sql) 不同, 过滤条件使用类型化的 clause.ICondition, 属性引用自动生成为 tag.prop
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+type LookupQuery struct {
+	db     *DB
+	model  interface{}    // 点或边模型, 用于获取标签(边类型)名称与属性
+	name   string         // 标签或边类型名称
+	edge   bool           // 是否查询边
+	props  map[string]int // 属性名到结构体字段下标
+	where  clause.ICondition
+	yields []string // 返回的属性, 为空时返回模型的全部属性
+	limit  int
+	err    error
+}
+
+// Lookup 创建基于索引的属性查询, 执行前会校验标签(边类型)存在索引且过滤的属性被索引包含。
+// var vertexs []models.SdkVertex
+// err := db.Lookup(&models.SdkVertex{}).Where(clause.Eq("parent_key", "无")).Yield("chain_key").Find(&vertexs)
+//
+// 参数:
+// m (interface{}): 点边模型, 需实现IVertex或IEdge接口
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (db *DB) Lookup(m interface{}) *LookupQuery {
+	q := &LookupQuery{db: db.getInstance(), model: m}
+	switch v := m.(type) {
+	case model.IVertex:
+		q.name = v.TagName()
+	case model.IEdge:
+		q.name, q.edge = v.EdgeName(), true
+	default:
+		q.err = fmt.Errorf("%T 需要实现 model.IVertex 或 model.IEdge 接口", m)
+		return q
+	}
+	typ := reflect.Indirect(reflect.ValueOf(m)).Type()
+	if typ.Kind() != reflect.Struct {
+		q.err = fmt.Errorf("参数必须是一个结构体")
+		return q
+	}
+	q.props = utils.GetStructFieldTagMap(typ)
+	return q
+}
+
+// Where 指定过滤条件, 多次调用时条件以 and 组合, 条件中的属性需要被索引包含
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (q *LookupQuery) Where(cond clause.ICondition) *LookupQuery {
+	if q.where == nil {
+		q.where = cond
+	} else {
+		q.where = clause.And(q.where, cond)
+	}
+	return q
+}
+
+// Yield 指定返回的属性(属性名或结构体字段名), 未指定时返回模型的全部属性, 未返回的字段保持零值
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (q *LookupQuery) Yield(fields ...string) *LookupQuery {
+	q.yields = append(q.yields, fields...)
+	return q
+}
+
+// Limit 限制返回的数量
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (q *LookupQuery) Limit(limit int) *LookupQuery {
+	q.limit = limit
+	return q
+}
+
+// Find 执行查询并将结果解码到模型切片, 点写入VID, 边写入起点、终点(实现 model.ISetEdgeRank 时同时写入 rank)
+//
+// 参数:
+// out (interface{}): 模型结构体切片或结构体指针切片的指针, 例如 *[]models.SdkVertex
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (q *LookupQuery) Find(out interface{}) error {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("查询结果必须传入切片指针, 实际为 %T", out)
+	}
+	elemType := slice.Elem().Type().Elem()
+	structType := elemType
+	if elemType.Kind() == reflect.Ptr {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("查询结果必须传入结构体切片指针, 实际为 %T", out)
+	}
+
+	sql, err := q.build()
+	if err != nil {
+		return err
+	}
+	if err = q.checkIndex(); err != nil {
+		return err
+	}
+	result, err := q.db.withModel(q.name).execute(sql)
+	if err != nil {
+		return err
+	}
+
+	values := reflect.MakeSlice(slice.Elem().Type(), 0, result.GetRowSize())
+	for _, row := range result.GetRows() {
+		cells := row.GetValues()
+		elem := reflect.New(structType)
+		// 先写入VID, 以便查询后钩子中可以读取
+		if q.edge {
+			if setter, ok := elem.Interface().(model.ISetEdgeVid); ok {
+				setter.SetVidSrc(utils.NValueToInterface(cells[0]))
+				setter.SetVidDst(utils.NValueToInterface(cells[1]))
+			}
+			if setter, ok := elem.Interface().(model.ISetEdgeRank); ok {
+				setter.SetRank(cells[2].GetIVal())
+			}
+		} else if setter, ok := elem.Interface().(model.ISetVid); ok {
+			setter.SetVid(utils.NValueToInterface(cells[0]))
+		}
+		if err = decodeRow(elem.Interface(), result.GetColNames(), cells); err != nil {
+			return err
+		}
+		if elemType.Kind() == reflect.Ptr {
+			values = reflect.Append(values, elem)
+		} else {
+			values = reflect.Append(values, elem.Elem())
+		}
+	}
+	slice.Elem().Set(values)
+	return nil
+}
+
+// 生成 lookup 语句
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (q *LookupQuery) build() (string, error) {
+	if q.err != nil {
+		return "", q.err
+	}
+	var sb strings.Builder
+	sb.WriteString("lookup on " + q.name)
+	if q.where != nil {
+		for _, prop := range q.where.Props() {
+			if _, ok := q.props[prop]; !ok {
+				return "", fmt.Errorf("属性[%s]在 %T 中不存在", prop, q.model)
+			}
+		}
+		where, err := q.where.Build(q.name)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(" where " + where)
+	}
+	if q.edge {
+		sb.WriteString(fmt.Sprintf(" yield src(edge) as %s, dst(edge) as %s, rank(edge) as %s", yieldSrc, yieldDst, yieldRank))
+	} else {
+		sb.WriteString(fmt.Sprintf(" yield id(vertex) as %s", yieldVid))
+	}
+	if len(q.yields) == 0 {
+		sb.WriteString(yieldProps(q.model, q.name))
+	}
+	for _, field := range q.yields {
+		prop, err := q.resolve(field)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(fmt.Sprintf(", %s.%s as %s", q.name, prop, prop))
+	}
+	if q.limit > 0 {
+		sb.WriteString(fmt.Sprintf(" | limit %d", q.limit))
+	}
+	return sb.String(), nil
+}
+
+// 将属性名或结构体字段名解析为属性名
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (q *LookupQuery) resolve(field string) (string, error) {
+	if _, ok := q.props[field]; ok {
+		return field, nil
+	}
+	typ := reflect.Indirect(reflect.ValueOf(q.model)).Type()
+	for prop, i := range q.props {
+		if typ.Field(i).Name == field {
+			return prop, nil
+		}
+	}
+	return "", fmt.Errorf("属性[%s]在 %T 中不存在", field, q.model)
+}
+
+// 通过 show tag|edge indexes 校验标签(边类型)存在索引, 且过滤条件中的属性是某一个索引的最左前缀,
+// 例如索引 (a, b, c) 可以用于过滤 a 或 a、b, 不能用于只过滤 b。试运行时不校验。
+// 索引列按标签(边类型)缓存, 缓存的索引不满足时重新读取一次, 以便识别之后新建的索引
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (q *LookupQuery) checkIndex() error {
+	if q.db.statement != nil {
+		return nil
+	}
+	var props []string
+	if q.where != nil {
+		props = q.where.Props()
+	}
+	indexes, cached := q.db.indexes.get(q.key())
+	if cached && matchIndex(indexes, props) {
+		return nil
+	}
+	indexes, err := q.loadIndexes()
+	if err != nil {
+		return err
+	}
+	q.db.indexes.set(q.key(), indexes)
+	if len(indexes) == 0 {
+		return fmt.Errorf("%s 没有索引, lookup 需要先创建索引", q.name)
+	}
+	if !matchIndex(indexes, props) {
+		return fmt.Errorf("%s 没有以属性%v为最左前缀的索引, 请先创建以这些属性开头的索引", q.name, props)
+	}
+	return nil
+}
+
+// 索引缓存的键
+func (q *LookupQuery) key() string {
+	if q.edge {
+		return "edge:" + q.name
+	}
+	return "tag:" + q.name
+}
+
+// 读取标签(边类型)全部索引的属性列。直接通过拨号器执行, 不添加 explain 前缀, 也不记录日志与指标
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (q *LookupQuery) loadIndexes() ([][]string, error) {
+	kind := "tag"
+	if q.edge {
+		kind = "edge"
+	}
+	result, err := q.db.dialer.Execute(fmt.Sprintf("show %s indexes", kind))
+	if err != nil {
+		return nil, err
+	}
+	var indexes [][]string
+	for i := 0; i < result.GetRowSize(); i++ {
+		record, err := result.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		// 第二列为索引所属的标签(边类型), 列名为 By Tag 或 By Edge
+		owner, err := record.GetValueByIndex(1)
+		if err != nil {
+			return nil, err
+		}
+		if name, err := owner.AsString(); err != nil || name != q.name {
+			continue
+		}
+		columns, err := record.GetValueByColName("Columns")
+		if err != nil {
+			return nil, err
+		}
+		list, err := columns.AsList()
+		if err != nil {
+			return nil, err
+		}
+		index := make([]string, 0, len(list))
+		for _, column := range list {
+			if prop, err := column.AsString(); err == nil {
+				index = append(index, prop)
+			}
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+// 是否存在可用于过滤 props 的索引: props 去重后恰好是某个索引的前 len(props) 列; 没有过滤条件时任一索引均可
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func matchIndex(indexes [][]string, props []string) bool {
+	filtered := make(map[string]bool, len(props))
+	for _, prop := range props {
+		filtered[prop] = true
+	}
+	for _, index := range indexes {
+		if len(index) < len(filtered) {
+			continue
+		}
+		prefix := true
+		for _, column := range index[:len(filtered)] {
+			if !filtered[column] {
+				prefix = false
+				break
+			}
+		}
+		if prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// 标签(边类型)的索引列缓存, 键为 tag:名称 或 edge:名称
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+type indexCache struct {
+	mu      sync.RWMutex
+	columns map[string][][]string
+}
+
+// 获取缓存的索引列, 未通过 Open 创建的 DB 没有缓存
+func (c *indexCache) get(key string) ([][]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	indexes, ok := c.columns[key]
+	return indexes, ok
+}
+
+// 更新缓存的索引列
+func (c *indexCache) set(key string, indexes [][]string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.columns[key] = indexes
+}
@@ -0,0 +1,137 @@
+package orm
+
+import (
+	"nebula-orm-go/clause"
+	"nebula-orm-go/config"
+	"nebula-orm-go/dialectors/memory"
+	"nebula-orm-go/model"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// 索引按最左前缀匹配过滤条件
+func TestMatchIndex(t *testing.T) {
+	indexes := [][]string{{"name", "level"}, {"city"}}
+	tests := []struct {
+		props []string
+		want  bool
+	}{
+		{nil, true},
+		{[]string{"name"}, true},
+		{[]string{"level", "name"}, true},
+		{[]string{"name", "name"}, true},
+		{[]string{"city"}, true},
+		{[]string{"level"}, false},
+		{[]string{"name", "city"}, false},
+		{[]string{"name", "level", "city"}, false},
+	}
+	for _, tt := range tests {
+		if got := matchIndex(indexes, tt.props); got != tt.want {
+			t.Errorf("matchIndex(%v) = %v, 期望 %v", tt.props, got, tt.want)
+		}
+	}
+	if matchIndex(nil, nil) {
+		t.Error("没有索引时不能 lookup")
+	}
+}
+
+// 使用内存图引擎执行 lookup, 过滤条件需要是索引的最左前缀
+func TestLookup(t *testing.T) {
+	dialer := memory.MustNew(memory.WithInitSql("create space s(vid_type=FIXED_STRING(16)); use s; " +
+		"create tag save_vertex(name string, level int); create tag index save_name_level on save_vertex(name(16), level); " +
+		"insert vertex save_vertex(name, level) values 'a':('甲', 1), 'b':('乙', 2), 'c':('甲', 3)"))
+	db, err := Open(dialer, config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var vertexs []saveVertex
+	err = db.Lookup(&saveVertex{}).Where(clause.Eq("name", "甲")).Where(clause.Gt("level", 1)).Find(&vertexs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vertexs) != 1 || vertexs[0].Vid != "c" || vertexs[0].Level != 3 {
+		t.Fatalf("lookup = %+v", vertexs)
+	}
+
+	err = db.Lookup(&saveVertex{}).Where(clause.Eq("level", 2)).Find(&vertexs)
+	if err == nil || !strings.Contains(err.Error(), "最左前缀") {
+		t.Fatalf("只过滤索引的第二列时应返回错误, 实际为 %v", err)
+	}
+
+	// 新建索引后缓存失效, 可以使用新的索引
+	if _, err = dialer.Execute("create tag index save_level on save_vertex(level)"); err != nil {
+		t.Fatal(err)
+	}
+	if err = db.Lookup(&saveVertex{}).Where(clause.Eq("level", 2)).Find(&vertexs); err != nil {
+		t.Fatal(err)
+	}
+	if len(vertexs) != 1 || vertexs[0].Vid != "b" {
+		t.Fatalf("lookup = %+v", vertexs)
+	}
+
+	var edges []testEdge
+	if err = db.Lookup(&testEdge{}).Find(&edges); err == nil || !strings.Contains(err.Error(), "没有索引") {
+		t.Fatalf("没有索引时应返回错误, 实际为 %v", err)
+	}
+}
+
+// 查询边时写入起点、终点与 rank, 相同起点终点不同 rank 的边分别返回
+func TestLookupEdge(t *testing.T) {
+	db := openMemory(t, "create space s(vid_type=FIXED_STRING(16)); use s; "+
+		"create edge follow(degree double); create edge index follow_degree on follow(degree); "+
+		"insert edge follow(degree) values 'a'->'b':(0.5), 'a'->'b'@2:(2.0), 'b'->'c'@1:(1.5)")
+
+	var edges []*rankEdge
+	if err := db.Lookup(&rankEdge{}).Where(clause.Gt("degree", 1)).Find(&edges); err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].Degree < edges[j].Degree })
+	if len(edges) != 2 {
+		t.Fatalf("lookup = %+v", edges)
+	}
+	for i, want := range []rankEdge{
+		{EModel: model.EModel{Src: "b", Dst: "c"}, Degree: 1.5, Rank: 1},
+		{EModel: model.EModel{Src: "a", Dst: "b"}, Degree: 2, Rank: 2},
+	} {
+		got := edges[i]
+		if got.Src != want.Src || got.Dst != want.Dst || got.Degree != want.Degree || got.Rank != want.Rank {
+			t.Errorf("第 %d 条边 = %+v, 期望 %+v", i, *got, want)
+		}
+	}
+
+	// 读回的 rank 可以直接用于查询同一条边
+	edge := &rankEdge{EModel: model.EModel{Src: edges[1].Src, Dst: edges[1].Dst}, Rank: edges[1].Rank}
+	if err := db.Fetch(edge); err != nil || edge.Degree != 2 {
+		t.Fatalf("Fetch() = %+v, %v", edge, err)
+	}
+
+	// 仓储同样写入 rank
+	got, err := NewEdgeRepo[*rankEdge](db).GetMany(EdgeKey{Src: "a", Dst: "b", Rank: 2})
+	if err != nil || len(got) != 1 || got[0].Rank != 2 {
+		t.Fatalf("仓储查询结果 = %+v, %v", got, err)
+	}
+}
+
+// 索引按标签缓存, 直接通过拨号器读取, 不受 explain 影响
+func TestLookupIndexCache(t *testing.T) {
+	db, dialer := openMock(t)
+	dialer.Expect("show tag indexes").
+		WillReturnRows([]string{"Index Name", "By Tag", "Columns"},
+			[]interface{}{"other", "other_vertex", []interface{}{"name"}},
+			[]interface{}{"save_name", "save_vertex", []interface{}{"name"}})
+	lookup := "lookup on save_vertex where save_vertex.name == '甲' yield id(vertex) as vid_, save_vertex.name as name, save_vertex.level as level"
+	dialer.Expect(lookup).Times(2)
+	dialer.Expect(`explain format="row" ` + lookup)
+
+	var vertexs []saveVertex
+	for i := 0; i < 2; i++ {
+		if err := db.Lookup(&saveVertex{}).Where(clause.Eq("name", "甲")).Find(&vertexs); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Explain().Lookup(&saveVertex{}).Where(clause.Eq("name", "甲")).Find(&vertexs); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,98 @@
+package orm
+
+import (
+	"fmt"
+	converts2 "nebula-orm-go/converts"
+	"nebula-orm-go/dialectors"
+	"nebula-orm-go/model"
+	"reflect"
+)
+
+// Save 以 upsert 方式保存点: 点不存在时插入, 存在时更新结构体中全部带 nebula 标签的属性(包括零值),
+// 标签中未在结构体声明的属性保持不变, 执行后将 yield 返回的属性回填到结构体。
+// 模型带有版本号字段时按版本号更新, 版本号不一致时返回 ErrStaleObject。
+//
+// 参数:
+// vertex (model.IVertex): 点实体的指针, 需实现IVertex接口
+//
+// @Author: 罗德
+// @Date: 2024/7/1
+func (db *DB) Save(vertex model.IVertex) error {
+	if err := checkSavePointer(vertex); err != nil {
+		return err
+	}
+	if err := callBeforeUpdate(vertex); err != nil {
+		return err
+	}
+	vertex, err := generateVid(vertex)
+	if err != nil {
+		return err
+	}
+	if err := db.checkVid(vertex); err != nil {
+		return err
+	}
+	ver, err := versionOf(vertex)
+	if err != nil {
+		return err
+	}
+	sql, err := converts2.ConvertToSaveVertexSql(vertex)
+	if err != nil {
+		return err
+	}
+	result, err := db.withModel(vertex.TagName()).execute(sql)
+	return db.saveResult(vertex, vertex.TagName(), ver, result, err)
+}
+
+// SaveEdge 以 upsert 方式保存边, 语义同 Save。
+//
+// 参数:
+// edge (model.IEdge): 边实体的指针, 需实现IEdge接口
+//
+// @Author: 罗德
+// @Date: 2024/7/1
+func (db *DB) SaveEdge(edge model.IEdge) error {
+	if err := checkSavePointer(edge); err != nil {
+		return err
+	}
+	if err := callBeforeUpdate(edge); err != nil {
+		return err
+	}
+	if err := db.checkVid(edge); err != nil {
+		return err
+	}
+	ver, err := versionOf(edge)
+	if err != nil {
+		return err
+	}
+	sql, err := converts2.ConvertToSaveEdgeSql(edge)
+	if err != nil {
+		return err
+	}
+	result, err := db.withModel(edge.EdgeName()).execute(sql)
+	return db.saveResult(edge, edge.EdgeName(), ver, result, err)
+}
+
+// 将 upsert 返回的属性回填到模型, 试运行时不回填; 模型带有版本号字段时先校验版本号, 冲突时不回填
+//
+// @Author: 罗德
+// @Date: 2024/7/1
+func (db *DB) saveResult(in interface{}, name string, ver *version, result *dialectors.ResultSet, err error) error {
+	if err != nil || db.statement != nil {
+		return err
+	}
+	if ver != nil {
+		if err = ver.check(name, result); err != nil {
+			return err
+		}
+	}
+	return result.UnmarshalResultSet(in)
+}
+
+// 保存需要传入模型指针, 以便回填 yield 的结果
+func checkSavePointer(in interface{}) error {
+	val := reflect.ValueOf(in)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("保存时必须传入模型的结构体指针以回填结果, 实际为 %T", in)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package orm
+
+import (
+	"nebula-orm-go/model"
+	"testing"
+)
+
+// 测试用保存点
+type saveVertex struct {
+	model.VModel
+	Name  string `nebula:"name"`
+	Level int64  `nebula:"level"`
+}
+
+func (v saveVertex) TagName() string {
+	return "save_vertex"
+}
+
+// Save 以 upsert 写入全部属性(包括零值)并回填 yield 的结果
+func TestSave(t *testing.T) {
+	db, dialer := openMock(t)
+	dialer.Expect("upsert vertex on save_vertex 'a' set name = '甲', level = 0  yield name as name,level as level").
+		WillReturnRows([]string{"name", "level"}, []interface{}{"甲", int64(3)})
+
+	v := saveVertex{VModel: model.VModel{Vid: "a"}, Name: "甲"}
+	if err := db.Save(&v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Level != 3 {
+		t.Fatalf("回填的 level = %d, 期望 3", v.Level)
+	}
+}
+
+// Save 需要传入指针, 试运行时不回填
+func TestSaveDryRun(t *testing.T) {
+	db, dialer := openMock(t)
+	if err := db.Save(saveVertex{VModel: model.VModel{Vid: "a"}}); err == nil {
+		t.Fatal("传入结构体值时应返回错误")
+	}
+
+	tx := db.DryRun()
+	v := saveVertex{VModel: model.VModel{Vid: "a"}, Name: "甲", Level: 1}
+	if err := tx.Save(&v); err != nil {
+		t.Fatal(err)
+	}
+	if len(dialer.Executed()) != 0 {
+		t.Fatalf("试运行不应执行语句: %q", dialer.Executed())
+	}
+	assertStrings(t, "语句", tx.Statement().NGQLs(),
+		[]string{"upsert vertex on save_vertex 'a' set name = '甲', level = 1  yield name as name,level as level"})
+}
+
+// SaveEdge 写入边的全部属性
+func TestSaveEdge(t *testing.T) {
+	db, dialer := openMock(t)
+	dialer.Expect("upsert edge on follow 'a' -> 'b' set degree = 0.5  yield degree as degree").
+		WillReturnRows([]string{"degree"}, []interface{}{0.5})
+
+	e := testEdge{EModel: model.EModel{Src: "a", Dst: "b"}, Degree: 0.5}
+	if err := db.SaveEdge(&e); err != nil {
+		t.Fatal(err)
+	}
+}
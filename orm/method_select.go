@@ -21,13 +21,17 @@ import (
 // @Author: 罗德
 // @Date: 2024/5/27
 func (db *DB) GetVertexByVid(vertex model.IVertex) (*dialectors.ResultSet, error) {
+	tx := db.withModel(vertex.TagName())
+	if err := tx.checkVid(vertex); err != nil {
+		return nil, err
+	}
 	vid := utils.GetVidWithPolicy(vertex.GetVid(), vertex.GetPolicy())
 	clause, err := utils.GetVClause(vertex, vertex.TagName())
 	if err != nil {
 		return nil, err
 	}
-	db.sql = fmt.Sprintf("match(v:%s) where id(v)==%s return %s", vertex.TagName(), vid, clause)
-	result, err := db.ReturnRow()
+	tx.sql = fmt.Sprintf("match(v:%s) where id(v)==%s return %s", vertex.TagName(), vid, clause)
+	result, err := tx.ReturnRow()
 	if err != nil {
 		return result, err
 	}
@@ -48,13 +52,17 @@ func (db *DB) GetVertexByVid(vertex model.IVertex) (*dialectors.ResultSet, error
 // @Author: 罗德
 // @Date: 2024/5/27
 func (db *DB) GetNextVertexByVid(vertex model.IVertex, edge model.IEdge, level int) (*dialectors.ResultSet, error) {
+	tx := db.withModel(vertex.TagName())
+	if err := tx.checkVid(vertex); err != nil {
+		return nil, err
+	}
 	vid := utils.GetVidWithPolicy(vertex.GetVid(), vertex.GetPolicy())
 	clause, err := utils.GetVClause(vertex, vertex.TagName())
 	if err != nil {
 		return nil, err
 	}
-	db.sql = fmt.Sprintf("match p=(v)<-[:%s*1..%d]-(n) where id(n)==%s return %s", edge.EdgeName(), level, vid, clause)
-	result, err := db.ReturnRow()
+	tx.sql = fmt.Sprintf("match p=(v)<-[:%s*1..%d]-(n) where id(n)==%s return %s", edge.EdgeName(), level, vid, clause)
+	result, err := tx.ReturnRow()
 	if err != nil {
 		return result, err
 	}
@@ -75,14 +83,18 @@ func (db *DB) GetNextVertexByVid(vertex model.IVertex, edge model.IEdge, level i
 // @Author: 罗德
 // @Date: 2024/5/27
 func (db *DB) GetNextVertexMapByVid(vertex model.IVertex, edge model.IEdge, level int) ([][]map[string]interface{}, error) {
+	tx := db.withModel(vertex.TagName())
+	if err := tx.checkVid(vertex); err != nil {
+		return nil, err
+	}
 	vid := utils.GetVidWithPolicy(vertex.GetVid(), vertex.GetPolicy())
 	// 查询下级, 因为get subgraph默认返回原点本身作为第一层, 所以查询level至少+1
-	db.sql = fmt.Sprintf(`get subgraph with prop %d steps from %s out %s yield vertices as %s`, level+1, vid, edge.EdgeName(), constants.V)
-	resultOut, err := db.ReturnRow()
+	tx.sql = fmt.Sprintf(`get subgraph with prop %d steps from %s out %s yield vertices as %s`, level+1, vid, edge.EdgeName(), constants.V)
+	resultOut, err := tx.ReturnRow()
 	if err != nil {
 		return nil, err
 	}
-	vertexs, err := resultOut.ToVertexs()
+	vertexs, err := tx.toVertexs(resultOut)
 	// 移除当前查询的点本身
 	if len(vertexs) <= 1 {
 		return make([][]map[string]interface{}, 0), err
@@ -104,13 +116,17 @@ func (db *DB) GetNextVertexMapByVid(vertex model.IVertex, edge model.IEdge, leve
 // @Author: 罗德
 // @Date: 2024/5/27
 func (db *DB) GetUpVertexByVid(vertex model.IVertex, edge model.IEdge, level int) (*dialectors.ResultSet, error) {
+	tx := db.withModel(vertex.TagName())
+	if err := tx.checkVid(vertex); err != nil {
+		return nil, err
+	}
 	vid := utils.GetVidWithPolicy(vertex.GetVid(), vertex.GetPolicy())
 	clause, err := utils.GetVClause(vertex, vertex.TagName())
 	if err != nil {
 		return nil, err
 	}
-	db.sql = fmt.Sprintf("match p=(n)<-[:%s*1..%d]-(v) where id(n)==%s return %s", edge.EdgeName(), level, vid, clause)
-	result, err := db.ReturnRow()
+	tx.sql = fmt.Sprintf("match p=(n)<-[:%s*1..%d]-(v) where id(n)==%s return %s", edge.EdgeName(), level, vid, clause)
+	result, err := tx.ReturnRow()
 	if err != nil {
 		return result, err
 	}
@@ -131,14 +147,18 @@ func (db *DB) GetUpVertexByVid(vertex model.IVertex, edge model.IEdge, level int
 // @Author: 罗德
 // @Date: 2024/5/27
 func (db *DB) GetUpVertexMapByVid(vertex model.IVertex, edge model.IEdge, level int) ([][]map[string]interface{}, error) {
+	tx := db.withModel(vertex.TagName())
+	if err := tx.checkVid(vertex); err != nil {
+		return nil, err
+	}
 	vid := utils.GetVidWithPolicy(vertex.GetVid(), vertex.GetPolicy())
 	// 查询上级, 因为get subgraph默认返回原点本身作为第一层, 所以查询level至少+1
-	db.sql = fmt.Sprintf(`get subgraph with prop %d steps from %s in %s yield vertices as %s`, level+1, vid, edge.EdgeName(), constants.V)
-	resultOut, err := db.ReturnRow()
+	tx.sql = fmt.Sprintf(`get subgraph with prop %d steps from %s in %s yield vertices as %s`, level+1, vid, edge.EdgeName(), constants.V)
+	resultOut, err := tx.ReturnRow()
 	if err != nil {
 		return nil, err
 	}
-	vertexs, err := resultOut.ToVertexs()
+	vertexs, err := tx.toVertexs(resultOut)
 	// 移除当前查询的点本身
 	if len(vertexs) <= 1 {
 		return make([][]map[string]interface{}, 0), err
@@ -161,15 +181,19 @@ func (db *DB) GetUpVertexMapByVid(vertex model.IVertex, edge model.IEdge, level
 // @Author: 罗德
 // @Date: 2024/6/5
 func (db *DB) GetBothAllVertexByVid(vertex model.IVertex, edge model.IEdge, level int) ([][]map[string]interface{}, error) {
+	tx := db.withModel(vertex.TagName())
+	if err := tx.checkVid(vertex); err != nil {
+		return nil, err
+	}
 	vid := utils.GetVidWithPolicy(vertex.GetVid(), vertex.GetPolicy())
 	// 查询上下级, 因为get subgraph默认返回原点本身作为第一层, 所以查询level至少+1
-	db.sql = fmt.Sprintf("get subgraph with prop %d steps from %s both %s yield vertices as %s", level+1, vid, edge.EdgeName(), constants.V)
-	result, err := db.ReturnRow()
+	tx.sql = fmt.Sprintf("get subgraph with prop %d steps from %s both %s yield vertices as %s", level+1, vid, edge.EdgeName(), constants.V)
+	result, err := tx.ReturnRow()
 	if err != nil {
 		return nil, err
 	}
 
-	vertexs, err := result.ToVertexs()
+	vertexs, err := tx.toVertexs(result)
 	if len(vertexs) > 0 && vertexs[0] == nil {
 		vertexs = make([][]map[string]interface{}, 0)
 	}
@@ -191,25 +215,29 @@ func (db *DB) GetBothAllVertexByVid(vertex model.IVertex, edge model.IEdge, leve
 // @Author: 罗德
 // @Date: 2024/6/5
 func (db *DB) GetBothVertexByVid(vertex model.IVertex, edge model.IEdge, level int) ([][]map[string]interface{}, error) {
+	tx := db.withModel(vertex.TagName())
+	if err := tx.checkVid(vertex); err != nil {
+		return nil, err
+	}
 	vid := utils.GetVidWithPolicy(vertex.GetVid(), vertex.GetPolicy())
 	// 查询上级, 因为get subgraph默认返回原点本身作为第一层, 所以查询level至少+1
-	db.sql = fmt.Sprintf(`get subgraph with prop %d steps from %s out %s yield vertices as %s`, level+1, vid, edge.EdgeName(), constants.V)
-	resultOut, err := db.ReturnRow()
+	tx.sql = fmt.Sprintf(`get subgraph with prop %d steps from %s out %s yield vertices as %s`, level+1, vid, edge.EdgeName(), constants.V)
+	resultOut, err := tx.ReturnRow()
 	if err != nil {
 		return nil, err
 	}
-	vertexsOut, err := resultOut.ToVertexs()
+	vertexsOut, err := tx.toVertexs(resultOut)
 	if err != nil {
 		return nil, err
 	}
 
 	// 查询下级, 因为get subgraph默认返回原点本身作为第一层, 所以查询level至少+1
-	db.sql = fmt.Sprintf(`get subgraph with prop %d steps from %s in %s yield vertices as %s`, level+1, vid, edge.EdgeName(), constants.V)
-	resultIn, err := db.ReturnRow()
+	tx.sql = fmt.Sprintf(`get subgraph with prop %d steps from %s in %s yield vertices as %s`, level+1, vid, edge.EdgeName(), constants.V)
+	resultIn, err := tx.ReturnRow()
 	if err != nil {
 		return nil, err
 	}
-	vertexsIn, err := resultIn.ToVertexs()
+	vertexsIn, err := tx.toVertexs(resultIn)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"fmt"
 	converts2 "nebula-orm-go/converts"
 	"nebula-orm-go/dialectors"
 	"nebula-orm-go/model"
@@ -10,34 +11,46 @@ import (
 //
 // 参数:
 // vertex (model.IVertex): 点实体的接口, 需实现IVertex接口
-// set (string): 用于set更新字段的属性, 一次只能修改一个字段(例: parent_key = 'O(∩_∩)O')
+// set (string): 用于set更新字段的属性, 多个属性以逗号分隔(例: parent_key = 'O(∩_∩)O')
 // where (string): 用于补充过滤条件(例: parent_key == '无')
 //
 // @Author: 罗德
 // @Date: 2024/5/28
 func (db *DB) UpsertVertex(vertex model.IVertex, set string, where string) (*dialectors.ResultSet, error) {
+	if err := callBeforeUpdate(vertex); err != nil {
+		return nil, err
+	}
+	if err := db.checkVid(vertex); err != nil {
+		return nil, err
+	}
 	sql, err := converts2.ConvertToUpsertVertexSql(vertex, set, where)
 	if err != nil {
 		return nil, err
 	}
-	return db.execute(sql)
+	return db.withModel(vertex.TagName()).execute(sql)
 }
 
 // UpdateVertex 根据给定的顶点模型执行Upsert操作, 如果顶点存在则更新, 不存在则忽略。
 //
 // 参数:
 // vertex (model.IVertex): 点实体的接口, 需实现IVertex接口
-// set (string): 用于set更新字段的属性, 一次只能修改一个字段(例: parent_key = 'O(∩_∩)O')
+// set (string): 用于set更新字段的属性, 多个属性以逗号分隔(例: parent_key = 'O(∩_∩)O')
 // where (string): 用于补充过滤条件(例: parent_key == '无')
 //
 // @Author: 罗德
 // @Date: 2024/5/28
 func (db *DB) UpdateVertex(vertex model.IVertex, set string, where string) (*dialectors.ResultSet, error) {
+	if err := callBeforeUpdate(vertex); err != nil {
+		return nil, err
+	}
+	if err := db.checkVid(vertex); err != nil {
+		return nil, err
+	}
 	sql, err := converts2.ConvertToUpdateVertexSql(vertex, set, where)
 	if err != nil {
 		return nil, err
 	}
-	return db.execute(sql)
+	return db.withModel(vertex.TagName()).execute(sql)
 }
 
 // UpsertEdge 根据给定的边模型执行Upsert操作, 如果边存在则更新, 不存在则插入, 更新性能低于update。
@@ -48,11 +61,17 @@ func (db *DB) UpdateVertex(vertex model.IVertex, set string, where string) (*dia
 // @Author: 罗德
 // @Date: 2024/5/28
 func (db *DB) UpsertEdge(edge model.IEdge, set string, where string) (*dialectors.ResultSet, error) {
+	if err := callBeforeUpdate(edge); err != nil {
+		return nil, err
+	}
+	if err := db.checkVid(edge); err != nil {
+		return nil, err
+	}
 	sql, err := converts2.ConvertToUpsertEdgeSql(edge, set, where)
 	if err != nil {
 		return nil, err
 	}
-	return db.execute(sql)
+	return db.withModel(edge.EdgeName()).execute(sql)
 }
 
 // UpdateEdge 根据给定的边模型执行Upsert操作, 如果边存在则更新, 不存在则忽略。
@@ -63,9 +82,84 @@ func (db *DB) UpsertEdge(edge model.IEdge, set string, where string) (*dialector
 // @Author: 罗德
 // @Date: 2024/5/28
 func (db *DB) UpdateEdge(edge model.IEdge, set string, where string) (*dialectors.ResultSet, error) {
+	if err := callBeforeUpdate(edge); err != nil {
+		return nil, err
+	}
+	if err := db.checkVid(edge); err != nil {
+		return nil, err
+	}
 	sql, err := converts2.ConvertToUpdateEdgeSql(edge, set, where)
 	if err != nil {
 		return nil, err
 	}
-	return db.execute(sql)
+	return db.withModel(edge.EdgeName()).execute(sql)
+}
+
+// Updates 按结构体或 map 更新 Model 指定的点/边的多个属性, 点边不存在时忽略。
+// 结构体只更新非零值字段, map 更新全部键, 可以通过 Select/Omit 过滤属性, 通过 When 指定更新条件。
+// 模型带有版本号字段(`nebula:"version,version"`)时自动按版本号更新, 版本号不一致时返回 ErrStaleObject,
+// 更新成功且 Model 传入指针时新版本号会写回模型。
+//
+// 参数:
+// values (interface{}): 结构体(指针)或 map[string]interface{}, 为 nil 时使用 Model 指定的模型本身
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func (db *DB) Updates(values interface{}) (*dialectors.ResultSet, error) {
+	return db.updates(values, false)
+}
+
+// Upserts 按结构体或 map 更新 Model 指定的点/边的多个属性, 点边不存在时插入, 用法同 Updates。
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func (db *DB) Upserts(values interface{}) (*dialectors.ResultSet, error) {
+	return db.updates(values, true)
+}
+
+// 生成并执行多属性的 update/upsert 语句
+//
+// @Author: 罗德
+// @Date: 2024/6/30
+func (db *DB) updates(values interface{}, upsert bool) (*dialectors.ResultSet, error) {
+	tx := db.getInstance()
+	if err := callBeforeUpdate(tx.dest); err != nil {
+		return nil, err
+	}
+	if err := tx.checkVid(tx.dest); err != nil {
+		return nil, err
+	}
+	opt := converts2.UpdateOption{Select: tx.selects, Omit: tx.omits, When: tx.when}
+	ver, err := versionOf(tx.dest)
+	if err != nil {
+		return nil, err
+	}
+
+	var sql string
+	switch m := tx.dest.(type) {
+	case model.IVertex:
+		if upsert {
+			sql, err = converts2.ConvertToUpsertVertexValuesSql(m, values, opt)
+		} else {
+			sql, err = converts2.ConvertToUpdateVertexValuesSql(m, values, opt)
+		}
+		tx = tx.withModel(m.TagName())
+	case model.IEdge:
+		if upsert {
+			sql, err = converts2.ConvertToUpsertEdgeValuesSql(m, values, opt)
+		} else {
+			sql, err = converts2.ConvertToUpdateEdgeValuesSql(m, values, opt)
+		}
+		tx = tx.withModel(m.EdgeName())
+	default:
+		return nil, fmt.Errorf("请先通过 Model 指定实现 IVertex 或 IEdge 的点边模型, 实际为 %T", tx.dest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result, err := tx.execute(sql)
+	if err != nil || tx.statement != nil || ver == nil {
+		return result, err
+	}
+	return result, ver.check(tx.model, result)
 }
@@ -0,0 +1,76 @@
+package orm
+
+import (
+	"errors"
+	"nebula-orm-go/config"
+	"nebula-orm-go/dialectors/mock"
+	"nebula-orm-go/model"
+	"testing"
+)
+
+// 模拟拨号器返回的错误
+var errTest = errors.New("测试错误")
+
+// 记录钩子调用的点
+type hookVertex struct {
+	model.VModel
+	Name  string `nebula:"name"`
+	calls []string
+	fail  string // 返回错误的钩子名称
+}
+
+func (v *hookVertex) TagName() string {
+	return "hook_vertex"
+}
+
+func (v *hookVertex) call(name string) error {
+	v.calls = append(v.calls, name)
+	if v.fail == name {
+		return errors.New(name + " 失败")
+	}
+	return nil
+}
+
+func (v *hookVertex) BeforeInsert() error { return v.call("BeforeInsert") }
+func (v *hookVertex) AfterInsert() error  { return v.call("AfterInsert") }
+func (v *hookVertex) BeforeUpdate() error { return v.call("BeforeUpdate") }
+func (v *hookVertex) BeforeDelete() error { return v.call("BeforeDelete") }
+
+// 测试用边
+type testEdge struct {
+	model.EModel
+	Degree float64 `nebula:"degree"`
+}
+
+func (e testEdge) EdgeName() string {
+	return "follow"
+}
+
+// 使用模拟拨号器打开数据库, 测试结束时检查期望都已执行
+func openMock(t *testing.T, opts ...config.Option) (*DB, *mock.Dialer) {
+	t.Helper()
+	dialer := mock.New()
+	db, err := Open(dialer, config.Config{}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := dialer.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	return db, dialer
+}
+
+// 断言字符串切片相等
+func assertStrings(t *testing.T, name string, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s = %q, 期望 %q", name, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s = %q, 期望 %q", name, got, want)
+		}
+	}
+}
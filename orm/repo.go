@@ -0,0 +1,442 @@
+package orm
+
+import (
+	"fmt"
+	"nebula-orm-go/dialectors"
+	"nebula-orm-go/model"
+	"nebula-orm-go/utils"
+	"reflect"
+	"strings"
+)
+
+// 查询时 id(vertex)、src(edge)、dst(edge) 的别名, 以下划线结尾避免与属性名冲突
+const (
+	yieldVid = "vid_"
+	yieldSrc = "src_"
+	yieldDst = "dst_"
+)
+
+// Repo 点模型的泛型仓储, 查询结果直接解码为 T, 无需手动调用 UnmarshalResultSet。
+// T 可以是结构体或结构体指针, 需要嵌入 model.VModel(或实现 model.ISetVid)才能在查询结果中写入VID。
+// users := orm.NewRepo[*User](db); user, err := users.Get("u1")
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+type Repo[T model.IVertex] struct {
+	db *DB
+}
+
+// NewRepo 创建点模型的泛型仓储, 之后的语句沿用 db 的调用链设置(例如 Debug、DryRun)
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func NewRepo[T model.IVertex](db *DB) *Repo[T] {
+	return &Repo[T]{db: db}
+}
+
+// Get 根据VID查询单个点, 点不存在时返回 dialectors.RecordNotFoundError
+//
+// 参数:
+// vid (interface{}): 点的VID, 按模型的 GetPolicy 处理
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *Repo[T]) Get(vid interface{}) (T, error) {
+	var zero T
+	vertexs, err := r.GetMany(vid)
+	if err != nil {
+		return zero, err
+	}
+	if len(vertexs) == 0 {
+		return zero, dialectors.RecordNotFoundError
+	}
+	return vertexs[0], nil
+}
+
+// GetMany 根据VID查询多个点, 不存在的点不会返回, 返回顺序与 graphd 返回的顺序一致
+//
+// 参数:
+// vids (...interface{}): 点的VID, 按模型的 GetPolicy 处理
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *Repo[T]) GetMany(vids ...interface{}) ([]T, error) {
+	if len(vids) == 0 {
+		return nil, fmt.Errorf("参数为空")
+	}
+	m, err := newModel[T]()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := r.vids(m, vids)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.db.withModel(m.TagName()).execute(fetchSql(m, m.TagName(), keys))
+	if err != nil {
+		return nil, err
+	}
+	return decodeModels[T](result, func(v reflect.Value, row []interface{}) {
+		if setter, ok := v.Addr().Interface().(model.ISetVid); ok {
+			setter.SetVid(row[0])
+		}
+	})
+}
+
+// Insert 插入一个点, 与 DB.InsertVertex 相同, 返回写入生成的VID后的点。
+// T 不是指针时生成的VID无法写回传入的 vertex, 需要使用返回值
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *Repo[T]) Insert(vertex T) (T, error) {
+	generated, err := generateVid(vertex)
+	if err != nil {
+		return vertex, err
+	}
+	if v, ok := generated.(T); ok {
+		vertex = v
+	}
+	return vertex, r.db.InsertVertex(vertex)
+}
+
+// InsertMany 以一条语句插入多个点, 与 DB.InsertVertexBatch 相同, 生成的VID会写回 vertexs
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *Repo[T]) InsertMany(vertexs []T) error {
+	if len(vertexs) == 0 {
+		return fmt.Errorf("参数为空")
+	}
+	models := make([]model.IVertex, len(vertexs))
+	for i, vertex := range vertexs {
+		models[i] = vertex
+	}
+	err := r.db.InsertVertexBatch(models)
+	for i, vertex := range models {
+		if v, ok := vertex.(T); ok {
+			vertexs[i] = v
+		}
+	}
+	return err
+}
+
+// Delete 删除点及其关联的边, 与 DB.DeleteVertexBatch 相同
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *Repo[T]) Delete(vertexs ...T) error {
+	if len(vertexs) == 0 {
+		return fmt.Errorf("参数为空")
+	}
+	models := make([]model.IVertex, len(vertexs))
+	for i, vertex := range vertexs {
+		models[i] = vertex
+	}
+	return r.db.DeleteVertexBatch(models)
+}
+
+// Exists 判断点是否存在(点上存在该标签)
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *Repo[T]) Exists(vid interface{}) (bool, error) {
+	m, err := newModel[T]()
+	if err != nil {
+		return false, err
+	}
+	keys, err := r.vids(m, []interface{}{vid})
+	if err != nil {
+		return false, err
+	}
+	result, err := r.db.withModel(m.TagName()).execute(fetchSql(m, m.TagName(), keys))
+	if err != nil {
+		return false, err
+	}
+	return result.GetRowSize() > 0, nil
+}
+
+// Count 统计标签的点数量, 通过 lookup 实现, 需要先为标签创建索引。
+// 与 DB.Lookup 相同, 执行前校验标签存在索引, 没有索引时返回错误; 试运行时不校验并返回0
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *Repo[T]) Count() (int64, error) {
+	m, err := newModel[T]()
+	if err != nil {
+		return 0, err
+	}
+	sql := fmt.Sprintf("lookup on %s yield id(vertex) as %s | yield count(*) as count", m.TagName(), yieldVid)
+	return r.db.count(m, sql)
+}
+
+// 按模型的VID策略处理VID, 指定了图空间VID类型时先校验
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *Repo[T]) vids(m T, vids []interface{}) ([]string, error) {
+	keys := make([]string, len(vids))
+	for i, vid := range vids {
+		if vidType := r.db.VidType(); vidType != nil {
+			if err := vidType.Check(vid, m.GetPolicy()); err != nil {
+				return nil, fmt.Errorf("点 %s: %w", m.TagName(), err)
+			}
+		}
+		keys[i] = vidLiteral(vid, m.GetPolicy())
+	}
+	return keys, nil
+}
+
+// EdgeKey 边的起点、终点VID与 rank
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+type EdgeKey struct {
+	Src  interface{}
+	Dst  interface{}
+	Rank int64
+}
+
+// EdgeRepo 边模型的泛型仓储, 查询结果直接解码为 E。
+// E 可以是结构体或结构体指针, 需要嵌入 model.EModel(或实现 model.ISetEdgeVid)才能在查询结果中写入起点、终点。
+// follows := orm.NewEdgeRepo[*Follow](db); follow, err := follows.Get("u1", "u2")
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+type EdgeRepo[E model.IEdge] struct {
+	db *DB
+}
+
+// NewEdgeRepo 创建边模型的泛型仓储, 之后的语句沿用 db 的调用链设置(例如 Debug、DryRun)
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func NewEdgeRepo[E model.IEdge](db *DB) *EdgeRepo[E] {
+	return &EdgeRepo[E]{db: db}
+}
+
+// Get 根据起点、终点查询 rank 为0的单条边, 边不存在时返回 dialectors.RecordNotFoundError, 其它 rank 通过 GetMany 查询
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *EdgeRepo[E]) Get(src, dst interface{}) (E, error) {
+	var zero E
+	edges, err := r.GetMany(EdgeKey{Src: src, Dst: dst})
+	if err != nil {
+		return zero, err
+	}
+	if len(edges) == 0 {
+		return zero, dialectors.RecordNotFoundError
+	}
+	return edges[0], nil
+}
+
+// GetMany 根据起点、终点与 rank 查询多条边, 不存在的边不会返回
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *EdgeRepo[E]) GetMany(keys ...EdgeKey) ([]E, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("参数为空")
+	}
+	m, err := newModel[E]()
+	if err != nil {
+		return nil, err
+	}
+	edgeKeys, err := r.keys(m, keys)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.db.withModel(m.EdgeName()).execute(fetchSql(m, m.EdgeName(), edgeKeys))
+	if err != nil {
+		return nil, err
+	}
+	return decodeModels[E](result, func(v reflect.Value, row []interface{}) {
+		if setter, ok := v.Addr().Interface().(model.ISetEdgeVid); ok {
+			setter.SetVidSrc(row[0])
+			setter.SetVidDst(row[1])
+		}
+		if setter, ok := v.Addr().Interface().(model.ISetEdgeRank); ok {
+			if rank, ok := row[2].(int64); ok {
+				setter.SetRank(rank)
+			}
+		}
+	})
+}
+
+// Insert 插入一条边, 与 DB.InsertEdge 相同
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *EdgeRepo[E]) Insert(edge E) error {
+	return r.db.InsertEdge(edge)
+}
+
+// InsertMany 以一条语句插入多条边, 与 DB.InsertEdgeBatch 相同
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *EdgeRepo[E]) InsertMany(edges []E) error {
+	if len(edges) == 0 {
+		return fmt.Errorf("参数为空")
+	}
+	models := make([]model.IEdge, len(edges))
+	for i, edge := range edges {
+		models[i] = edge
+	}
+	return r.db.InsertEdgeBatch(models)
+}
+
+// Delete 删除边, 与 DB.DeleteEdgeBatch 相同
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *EdgeRepo[E]) Delete(edges ...E) error {
+	if len(edges) == 0 {
+		return fmt.Errorf("参数为空")
+	}
+	models := make([]model.IEdge, len(edges))
+	for i, edge := range edges {
+		models[i] = edge
+	}
+	return r.db.DeleteEdgeBatch(models)
+}
+
+// Exists 判断 rank 为0的边是否存在
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *EdgeRepo[E]) Exists(src, dst interface{}) (bool, error) {
+	m, err := newModel[E]()
+	if err != nil {
+		return false, err
+	}
+	edgeKeys, err := r.keys(m, []EdgeKey{{Src: src, Dst: dst}})
+	if err != nil {
+		return false, err
+	}
+	result, err := r.db.withModel(m.EdgeName()).execute(fetchSql(m, m.EdgeName(), edgeKeys))
+	if err != nil {
+		return false, err
+	}
+	return result.GetRowSize() > 0, nil
+}
+
+// Count 统计边类型的边数量, 通过 lookup 实现, 需要先为边类型创建索引。
+// 与 DB.Lookup 相同, 执行前校验边类型存在索引, 没有索引时返回错误; 试运行时不校验并返回0
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *EdgeRepo[E]) Count() (int64, error) {
+	m, err := newModel[E]()
+	if err != nil {
+		return 0, err
+	}
+	sql := fmt.Sprintf("lookup on %s yield src(edge) as %s | yield count(*) as count", m.EdgeName(), yieldSrc)
+	return r.db.count(m, sql)
+}
+
+// 按模型的VID策略生成边键 src->dst@rank, 指定了图空间VID类型时先校验
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (r *EdgeRepo[E]) keys(m E, keys []EdgeKey) ([]string, error) {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		if vidType := r.db.VidType(); vidType != nil {
+			if err := vidType.Check(key.Src, m.GetVidSrcPolicy()); err != nil {
+				return nil, fmt.Errorf("边 %s 的起点: %w", m.EdgeName(), err)
+			}
+			if err := vidType.Check(key.Dst, m.GetVidDstPolicy()); err != nil {
+				return nil, fmt.Errorf("边 %s 的终点: %w", m.EdgeName(), err)
+			}
+		}
+		parts[i] = formatEdgeKey(key.Src, m.GetVidSrcPolicy(), key.Dst, m.GetVidDstPolicy(), key.Rank)
+	}
+	return parts, nil
+}
+
+// 按 DB.Lookup 的规则校验模型的索引后执行统计语句并返回数量, 试运行时返回0
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func (db *DB) count(m interface{}, sql string) (int64, error) {
+	q := db.Lookup(m)
+	if q.err != nil {
+		return 0, q.err
+	}
+	if err := q.checkIndex(); err != nil {
+		return 0, err
+	}
+	result, err := q.db.withModel(q.name).execute(sql)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	err = result.UnmarshalResultSet(&count)
+	return count, err
+}
+
+// 创建模型实例, 模型为指针时创建指向的结构体
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func newModel[T any]() (T, error) {
+	var m T
+	typ := reflect.TypeOf(&m).Elem()
+	switch {
+	case typ.Kind() == reflect.Struct:
+		return m, nil
+	case typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Struct:
+		return reflect.New(typ.Elem()).Interface().(T), nil
+	default:
+		return m, fmt.Errorf("仓储的模型类型必须是结构体或结构体指针, 实际为 %s", typ)
+	}
+}
+
+// 生成模型属性的 yield 片段(以逗号开头), 形如 , tag.prop as prop
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func yieldProps(m interface{}, name string) string {
+	typ := reflect.Indirect(reflect.ValueOf(m)).Type()
+	var sb strings.Builder
+	for i := 0; i < typ.NumField(); i++ {
+		if prop := utils.GetPropName(typ.Field(i)); prop != "" {
+			sb.WriteString(fmt.Sprintf(", %s.%s as %s", name, prop, prop))
+		}
+	}
+	return sb.String()
+}
+
+// 将结果集解码为模型切片, 每行解码后调用 set 写入 yield 的 VID 列(结果集的前几列)
+//
+// @Author: 罗德
+// @Date: 2024/7/9
+func decodeModels[T any](result *dialectors.ResultSet, set func(v reflect.Value, row []interface{})) ([]T, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	structType := typ
+	if typ.Kind() == reflect.Ptr {
+		structType = typ.Elem()
+	}
+	slice := reflect.New(reflect.SliceOf(structType))
+	if err := result.UnmarshalResultSet(slice.Interface()); err != nil {
+		return nil, err
+	}
+	values := slice.Elem()
+	models := make([]T, values.Len())
+	for i := range models {
+		v := values.Index(i)
+		cells := result.GetRows()[i].GetValues()
+		row := make([]interface{}, len(cells))
+		for j, cell := range cells {
+			row[j] = utils.NValueToInterface(cell)
+		}
+		set(v, row)
+		if typ.Kind() == reflect.Ptr {
+			models[i] = v.Addr().Interface().(T)
+		} else {
+			models[i] = v.Interface().(T)
+		}
+	}
+	return models, nil
+}
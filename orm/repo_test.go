@@ -0,0 +1,115 @@
+package orm
+
+import (
+	"errors"
+	"nebula-orm-go/config"
+	"nebula-orm-go/dialectors"
+	"nebula-orm-go/dialectors/memory"
+	"strings"
+	"testing"
+)
+
+// 仓储与 Fetch 使用同一条 fetch 语句, 边键包含 rank
+func TestRepoStatement(t *testing.T) {
+	db, _ := openMock(t)
+	tx := db.DryRun()
+	if _, err := NewRepo[*saveVertex](tx).GetMany("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewEdgeRepo[testEdge](tx).GetMany(EdgeKey{Src: "a", Dst: "b"}, EdgeKey{Src: "a", Dst: "b", Rank: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewEdgeRepo[testEdge](tx).Exists("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	assertStrings(t, "语句", tx.Statement().NGQLs(), []string{
+		"fetch prop on save_vertex 'a','b' yield id(vertex) as vid_, save_vertex.name as name, save_vertex.level as level",
+		"fetch prop on follow 'a'->'b'@0,'a'->'b'@2 yield src(edge) as src_, dst(edge) as dst_, rank(edge) as rank_, follow.degree as degree",
+		"fetch prop on follow 'a'->'b'@0 yield src(edge) as src_, dst(edge) as dst_, rank(edge) as rank_, follow.degree as degree",
+	})
+}
+
+// 使用内存图引擎查询不同 rank 的边
+func TestEdgeRepo(t *testing.T) {
+	dialer := memory.MustNew(memory.WithInitSql("create space s(vid_type=FIXED_STRING(16)); use s; " +
+		"create edge follow(degree double); " +
+		"insert edge follow(degree) values 'a'->'b':(0.5), 'a'->'b'@2:(2.0)"))
+	db, err := Open(dialer, config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := NewEdgeRepo[*testEdge](db)
+	edge, err := repo.Get("a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edge.Src != "a" || edge.Dst != "b" || edge.Degree != 0.5 {
+		t.Fatalf("rank 为0的边 = %+v", edge)
+	}
+	edges, err := repo.GetMany(EdgeKey{Src: "a", Dst: "b", Rank: 2}, EdgeKey{Src: "a", Dst: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 1 || edges[0].Degree != 2 {
+		t.Fatalf("rank 为2的边 = %+v", edges)
+	}
+	if _, err = repo.Get("b", "a"); !errors.Is(err, dialectors.RecordNotFoundError) {
+		t.Fatalf("边不存在时应返回 RecordNotFoundError, 实际为 %v", err)
+	}
+	exists, err := repo.Exists("a", "b")
+	if err != nil || !exists {
+		t.Fatalf("Exists = %v, %v", exists, err)
+	}
+}
+
+// 非指针模型通过返回值获得生成的VID, 指针模型直接写回
+func TestRepoInsert(t *testing.T) {
+	db, dialer := openMock(t)
+	dialer.Expect("insert vertex generated(name) values 'id-a':('a')")
+	dialer.Expect("insert vertex generated(name) values 'id-b':('b')")
+
+	vertex, err := NewRepo[generatedVertex](db).Insert(generatedVertex{Name: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vertex.Vid != "id-a" {
+		t.Errorf("返回的VID = %v, 期望 id-a", vertex.Vid)
+	}
+	pointer := &generatedVertex{Name: "b"}
+	inserted, err := NewRepo[*generatedVertex](db).Insert(pointer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inserted != pointer || pointer.Vid != "id-b" {
+		t.Errorf("指针模型的VID = %v, 期望写回 id-b", pointer.Vid)
+	}
+}
+
+// Count 与 Lookup 一样要求标签(边类型)存在索引
+func TestRepoCount(t *testing.T) {
+	db := openMemory(t, "create space s(vid_type=FIXED_STRING(16)); use s; "+
+		"create tag save_vertex(name string, level int); create edge follow(degree double); "+
+		"insert vertex save_vertex(name, level) values 'a':('甲', 1), 'b':('乙', 2); "+
+		"insert edge follow(degree) values 'a'->'b':(0.5)")
+
+	if _, err := NewRepo[*saveVertex](db).Count(); err == nil || !strings.Contains(err.Error(), "没有索引") {
+		t.Fatalf("标签没有索引时应返回错误, 实际为 %v", err)
+	}
+	if _, err := NewEdgeRepo[*testEdge](db).Count(); err == nil || !strings.Contains(err.Error(), "没有索引") {
+		t.Fatalf("边类型没有索引时应返回错误, 实际为 %v", err)
+	}
+
+	for _, sql := range []string{"create tag index save_name on save_vertex(name(16))", "create edge index follow_degree on follow(degree)"} {
+		if _, err := db.dialer.Execute(sql); err != nil {
+			t.Fatal(err)
+		}
+	}
+	count, err := NewRepo[*saveVertex](db).Count()
+	if err != nil || count != 2 {
+		t.Fatalf("点数量 = %d, %v", count, err)
+	}
+	count, err = NewEdgeRepo[*testEdge](db).Count()
+	if err != nil || count != 1 {
+		t.Fatalf("边数量 = %d, %v", count, err)
+	}
+}
@@ -0,0 +1,70 @@
+package orm
+
+import (
+	"strings"
+	"sync"
+)
+
+// Statement 试运行模式下记录的nGQL语句, 按执行顺序保存,
+// 一个方法可能生成多条语句(例如 GetBothVertexByVid 会分别查询上级与下级)。
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+type Statement struct {
+	mu    sync.Mutex
+	ngqls []string
+}
+
+// NGQLs 返回已记录的全部nGQL语句的副本
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func (s *Statement) NGQLs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ngqls := make([]string, len(s.ngqls))
+	copy(ngqls, s.ngqls)
+	return ngqls
+}
+
+// Last 返回最后一条记录的nGQL语句, 没有记录时返回空字符串
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func (s *Statement) Last() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.ngqls) == 0 {
+		return ""
+	}
+	return s.ngqls[len(s.ngqls)-1]
+}
+
+// Reset 清空已记录的语句, 便于复用同一个试运行调用链
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func (s *Statement) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ngqls = nil
+}
+
+// String 以分号换行拼接全部语句, 可直接粘贴到 nebula-console 中执行
+//
+// @Author: 罗德
+// @Date: 2024/6/25
+func (s *Statement) String() string {
+	ngqls := s.NGQLs()
+	if len(ngqls) == 0 {
+		return ""
+	}
+	return strings.Join(ngqls, ";\n") + ";"
+}
+
+// 记录一条语句
+func (s *Statement) add(ngql string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ngqls = append(s.ngqls, strings.TrimSpace(ngql))
+}
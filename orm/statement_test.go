@@ -0,0 +1,141 @@
+package orm
+
+import (
+	"nebula-orm-go/clause"
+	"nebula-orm-go/model"
+	"testing"
+)
+
+// 试运行生成的语句与记录方式
+func TestStatement(t *testing.T) {
+	db, dialer := openMock(t)
+	if db.Statement() != nil {
+		t.Fatal("未开启试运行时 Statement 应为 nil")
+	}
+	tx := db.DryRun()
+	if tx.Statement().Last() != "" || tx.Statement().String() != "" {
+		t.Fatal("没有记录语句时应返回空字符串")
+	}
+	a := &hookVertex{Name: "甲"}
+	a.Vid = "a"
+	if err := tx.InsertVertex(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.DeleteVertex(a); err != nil {
+		t.Fatal(err)
+	}
+	want := "insert vertex hook_vertex(name) values 'a':('甲');\ndelete vertex 'a' with edge;"
+	if got := tx.Statement().String(); got != want {
+		t.Fatalf("String = %q, 期望 %q", got, want)
+	}
+	if got := tx.Statement().Last(); got != "delete vertex 'a' with edge" {
+		t.Fatalf("Last = %q", got)
+	}
+	// 删除前钩子照常调用, 插入后钩子不调用
+	assertStrings(t, "钩子", a.calls, []string{"BeforeInsert", "BeforeDelete"})
+	tx.Statement().Reset()
+	if len(tx.Statement().NGQLs()) != 0 {
+		t.Fatal("Reset 后应清空语句")
+	}
+	if len(dialer.Executed()) != 0 {
+		t.Fatalf("试运行不应执行语句: %q", dialer.Executed())
+	}
+}
+
+// Updates/Upserts 的语句, 带版本号时包含版本号条件, 试运行时不校验也不修改版本号
+func TestStatementUpdates(t *testing.T) {
+	db, _ := openMock(t)
+	// 调用链会保留 Model、Select 等状态, 每条语句使用新的试运行调用链
+	var ngqls []string
+	account := versionAccount{VModel: model.VModel{Vid: "a"}, Balance: 1, Version: 2}
+	tx := db.DryRun()
+	if _, err := tx.Model(&account).Updates(map[string]interface{}{"balance": 100}); err != nil {
+		t.Fatal(err)
+	}
+	ngqls = append(ngqls, tx.Statement().NGQLs()...)
+	tx = db.DryRun()
+	if _, err := tx.Model(&account).Select("Balance").When(clause.Gt("balance", 0)).Upserts(&account); err != nil {
+		t.Fatal(err)
+	}
+	ngqls = append(ngqls, tx.Statement().NGQLs()...)
+	v := &hookVertex{Name: "乙"}
+	v.Vid = "b"
+	tx = db.DryRun()
+	if _, err := tx.Model(v).Updates(map[string]interface{}{"name": "丙"}); err != nil {
+		t.Fatal(err)
+	}
+	ngqls = append(ngqls, tx.Statement().NGQLs()...)
+	assertStrings(t, "语句", ngqls, []string{
+		"update vertex on account 'a' set balance = 100, version = version + 1 when version == 2 yield balance as balance,version as version",
+		"upsert vertex on account 'a' set balance = 1, version = coalesce(version, 0) + 1 when (balance > 0) and (version == 2) yield balance as balance,version as version",
+		"update vertex on hook_vertex 'b' set name = '丙'  yield name as name",
+	})
+	if account.Version != 2 {
+		t.Fatalf("试运行时版本号 = %d, 期望保持 2", account.Version)
+	}
+	assertStrings(t, "钩子", v.calls, []string{"BeforeUpdate"})
+}
+
+// 分组插入按标签与属性列分组, 每组一条语句
+func TestStatementGrouped(t *testing.T) {
+	db, _ := openMock(t)
+	tx := db.DryRun()
+	a := &hookVertex{Name: "甲"}
+	a.Vid = "a"
+	b := &hookVertex{Name: "乙"}
+	b.Vid = "b"
+	vertexs := []model.IVertex{a, saveVertex{VModel: model.VModel{Vid: "x"}, Name: "其它"}, b}
+	if err := tx.InsertVertexGrouped(vertexs); err != nil {
+		t.Fatal(err)
+	}
+	edges := []model.IEdge{
+		testEdge{EModel: model.EModel{Src: "a", Dst: "b"}, Degree: 0.5},
+		testEdge{EModel: model.EModel{Src: "b", Dst: "a"}, Degree: 1},
+	}
+	if err := tx.InsertEdgeGrouped(edges); err != nil {
+		t.Fatal(err)
+	}
+	assertStrings(t, "语句", tx.Statement().NGQLs(), []string{
+		"insert vertex hook_vertex(name) values 'a':('甲'), 'b':('乙')",
+		"insert vertex save_vertex(name,level) values 'x':('其它',0)",
+		"insert edge follow(degree) values 'a' -> 'b':(0.5), 'b' -> 'a':(1)",
+	})
+}
+
+// Fetch 按标签(边类型)合并语句, 试运行时不报告不存在的点边
+func TestStatementFetch(t *testing.T) {
+	db, _ := openMock(t)
+	tx := db.DryRun()
+	a := saveVertex{VModel: model.VModel{Vid: "a"}}
+	b := saveVertex{VModel: model.VModel{Vid: "b"}}
+	e := testEdge{EModel: model.EModel{Src: "a", Dst: "b"}}
+	if err := tx.Fetch(&a, &b, &e); err != nil {
+		t.Fatal(err)
+	}
+	assertStrings(t, "语句", tx.Statement().NGQLs(), []string{
+		"fetch prop on save_vertex 'a','b' yield id(vertex) as vid_, save_vertex.name as name, save_vertex.level as level",
+		"fetch prop on follow 'a'->'b'@0 yield src(edge) as src_, dst(edge) as dst_, rank(edge) as rank_, follow.degree as degree",
+	})
+}
+
+// Lookup 的过滤条件与返回属性
+func TestStatementLookup(t *testing.T) {
+	db, _ := openMock(t)
+	tx := db.DryRun()
+	var vertexs []saveVertex
+	err := tx.Lookup(&saveVertex{}).Where(clause.Eq("name", "甲")).Where(clause.Gt("level", 1)).Yield("Level").Limit(10).Find(&vertexs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var edges []*testEdge
+	if err = tx.Lookup(&testEdge{}).Find(&edges); err != nil {
+		t.Fatal(err)
+	}
+	assertStrings(t, "语句", tx.Statement().NGQLs(), []string{
+		"lookup on save_vertex where (save_vertex.name == '甲') and (save_vertex.level > 1) yield id(vertex) as vid_, save_vertex.level as level | limit 10",
+		"lookup on follow yield src(edge) as src_, dst(edge) as dst_, rank(edge) as rank_, follow.degree as degree",
+	})
+	if _, err = tx.Lookup(&saveVertex{}).Where(clause.Eq("unknown", 1)).build(); err == nil {
+		t.Fatal("过滤不存在的属性时应返回错误")
+	}
+}
@@ -0,0 +1,61 @@
+package orm
+
+import (
+	"nebula-orm-go/dialectors"
+	"nebula-orm-go/utils"
+	"reflect"
+)
+
+// 乐观锁版本号, 由模型中带 version 选项的字段确定
+//
+// @Author: 罗德
+// @Date: 2024/7/3
+type version struct {
+	prop  string        // 版本号属性名
+	field reflect.Value // 模型的版本号字段
+	old   int64         // 更新前的版本号
+}
+
+// 获取模型的版本号, 模型没有版本号字段时返回nil
+//
+// @Author: 罗德
+// @Date: 2024/7/3
+func versionOf(in interface{}) (*version, error) {
+	prop, field, ok, err := utils.GetVersionField(in)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &version{prop: prop, field: field, old: field.Int()}, nil
+}
+
+// check 根据 update/upsert yield 返回的版本号判断更新是否生效: 语句以 version == old 为条件并将版本号加一,
+// 只有返回 old+1 时才是本次写入的结果; when 条件不成立时语句不会修改点边, 返回的是当前版本号, 此时返回 *StaleObjectError。
+// 更新生效时将新版本号写回模型(模型为指针时)
+//
+// @Author: 罗德
+// @Date: 2024/7/3
+func (v *version) check(name string, result *dialectors.ResultSet) error {
+	stale := &StaleObjectError{Model: name, Version: v.old}
+	if result.GetRowSize() < 1 {
+		return stale
+	}
+	record, err := result.GetRowValuesByIndex(0)
+	if err != nil {
+		return err
+	}
+	value, err := record.GetValueByColName(v.prop)
+	if err != nil {
+		return err
+	}
+	if value.IsInt() {
+		current, _ := value.AsInt()
+		stale.Current = &current
+	}
+	if stale.Current == nil || *stale.Current != v.old+1 {
+		return stale
+	}
+	if v.field.CanSet() {
+		v.field.SetInt(v.old + 1)
+	}
+	return nil
+}
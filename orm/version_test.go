@@ -0,0 +1,124 @@
+package orm
+
+import (
+	"errors"
+	"nebula-orm-go/config"
+	"nebula-orm-go/dialectors/memory"
+	"nebula-orm-go/dialectors/mock"
+	"nebula-orm-go/model"
+	"testing"
+)
+
+// 带版本号的测试点
+type versionAccount struct {
+	model.VModel
+	Balance int64 `nebula:"balance"`
+	Version int64 `nebula:"version,version"`
+}
+
+func (v versionAccount) TagName() string {
+	return "account"
+}
+
+// 乐观锁: 只有返回的版本号为 old+1 时才算更新成功, when 条件不成立时返回的其它版本号视为冲突
+func TestUpdatesVersion(t *testing.T) {
+	sql := "update vertex on account 'a' set balance = 100, version = version + 1 when version == 3 yield balance as balance,version as version"
+	tests := []struct {
+		name    string
+		current int64 // 语句返回的版本号
+		stale   bool
+		want    int64 // 执行后结构体中的版本号
+	}{
+		{"本次更新生效", 3 + 1, false, 3 + 1},
+		{"其它调用方已更新两次", 3 + 2, true, 3},
+		{"版本号未变化", 3, true, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialer := mock.New()
+			dialer.Expect(sql).WillReturnRows([]string{"balance", "version"}, []interface{}{int64(100), tt.current})
+			db, err := Open(dialer, config.Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			account := versionAccount{VModel: model.VModel{Vid: "a"}, Version: 3}
+			_, err = db.Model(&account).Updates(map[string]interface{}{"balance": 100})
+			if got := errors.Is(err, ErrStaleObject); got != tt.stale {
+				t.Fatalf("ErrStaleObject = %v, 期望 %v, err = %v", got, tt.stale, err)
+			}
+			var stale *StaleObjectError
+			if tt.stale && (!errors.As(err, &stale) || stale.Current == nil || *stale.Current != tt.current) {
+				t.Fatalf("冲突详情错误: %v", err)
+			}
+			if account.Version != tt.want {
+				t.Fatalf("版本号 = %d, 期望 %d", account.Version, tt.want)
+			}
+			if err = dialer.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// Save 生成 upsert 语句, 新插入的点版本号为 1
+func TestSaveVersion(t *testing.T) {
+	sql := "upsert vertex on account 'a' set balance = 5, version = coalesce(version, 0) + 1 when (version is null) or (version == 0) yield balance as balance,version as version"
+	tests := []struct {
+		name    string
+		current int64
+		stale   bool
+		want    int64
+	}{
+		{"新插入", 1, false, 1},
+		{"其它调用方已更新", 2, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialer := mock.New()
+			dialer.Expect(sql).WillReturnRows([]string{"balance", "version"}, []interface{}{int64(5), tt.current})
+			db, err := Open(dialer, config.Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			account := versionAccount{VModel: model.VModel{Vid: "a"}, Balance: 5}
+			err = db.Save(&account)
+			if got := errors.Is(err, ErrStaleObject); got != tt.stale {
+				t.Fatalf("ErrStaleObject = %v, 期望 %v, err = %v", got, tt.stale, err)
+			}
+			if account.Version != tt.want {
+				t.Fatalf("版本号 = %d, 期望 %d", account.Version, tt.want)
+			}
+			if err = dialer.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// 在内存图空间中连续更新, 版本号逐次加一, 使用过期版本号的更新返回冲突
+func TestUpdatesVersionMemory(t *testing.T) {
+	dialer, err := memory.New(memory.WithInitSql("create space test(vid_type=FIXED_STRING(32)); use test; " +
+		"create tag account(balance int, version int); insert vertex account(balance, version) values 'a':(0, 0)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open(dialer, config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := versionAccount{VModel: model.VModel{Vid: "a"}}
+	for i := int64(1); i <= 3; i++ {
+		if _, err = db.Model(&account).Updates(map[string]interface{}{"balance": i * 10}); err != nil {
+			t.Fatal(err)
+		}
+		if account.Version != i {
+			t.Fatalf("第 %d 次更新后版本号 = %d", i, account.Version)
+		}
+	}
+	old := versionAccount{VModel: model.VModel{Vid: "a"}, Version: 1}
+	_, err = db.Model(&old).Updates(map[string]interface{}{"balance": 0})
+	var stale *StaleObjectError
+	if !errors.As(err, &stale) || stale.Current == nil || *stale.Current != 3 || old.Version != 1 {
+		t.Fatalf("过期版本号的更新 err = %v, 版本号 = %d", err, old.Version)
+	}
+}
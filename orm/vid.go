@@ -0,0 +1,109 @@
+package orm
+
+import (
+	"fmt"
+	"nebula-orm-go/model"
+	"nebula-orm-go/utils"
+	"reflect"
+)
+
+// DetectVidType 通过 describe space 获取图空间的VID类型, 之后写入前按该类型校验点边的VID, 与 config.WithVidType 作用相同。
+// VID类型在同一个 Open 返回的 db 及其全部调用链之间共享, 可以与其它协程中的调用链并发调用, 已经创建的调用链同样按新的VID类型校验。
+// 语句直接通过拨号器执行, 不受 DryRun、Explain 的影响
+//
+// 参数:
+// space (string): 图空间名称
+//
+// @Author: 罗德
+// @Date: 2024/7/5
+func (db *DB) DetectVidType(space string) (*utils.VidType, error) {
+	result, err := db.dialer.Execute(fmt.Sprintf("describe space `%s`", space))
+	if err != nil {
+		return nil, err
+	}
+	if result.GetRowSize() < 1 {
+		return nil, fmt.Errorf("图空间[%s]不存在", space)
+	}
+	record, err := result.GetRowValuesByIndex(0)
+	if err != nil {
+		return nil, err
+	}
+	value, err := record.GetValueByColName("Vid Type")
+	if err != nil {
+		return nil, err
+	}
+	text, err := value.AsString()
+	if err != nil {
+		return nil, fmt.Errorf("图空间[%s]的VID类型: %w", space, err)
+	}
+	vidType, err := utils.ParseVidType(text)
+	if err != nil {
+		return nil, err
+	}
+	db.vidType.Store(vidType)
+	return vidType, nil
+}
+
+// VidType 返回图空间的VID类型, 未通过 config.WithVidType 或 DetectVidType 指定时返回nil
+//
+// @Author: 罗德
+// @Date: 2024/7/5
+func (db *DB) VidType() *utils.VidType {
+	if db.vidType == nil {
+		return nil
+	}
+	return db.vidType.Load()
+}
+
+// 校验点的VID或边的起点、终点是否符合图空间的VID类型, 未指定VID类型时不校验
+//
+// @Author: 罗德
+// @Date: 2024/7/5
+func (db *DB) checkVid(in interface{}) error {
+	vidType := db.VidType()
+	if vidType == nil {
+		return nil
+	}
+	switch m := in.(type) {
+	case model.IVertex:
+		if err := vidType.Check(m.GetVid(), m.GetPolicy()); err != nil {
+			return fmt.Errorf("点 %s: %w", m.TagName(), err)
+		}
+	case model.IEdge:
+		if err := vidType.Check(m.GetVidSrc(), m.GetVidSrcPolicy()); err != nil {
+			return fmt.Errorf("边 %s 的起点: %w", m.EdgeName(), err)
+		}
+		if err := vidType.Check(m.GetVidDst(), m.GetVidDstPolicy()); err != nil {
+			return fmt.Errorf("边 %s 的终点: %w", m.EdgeName(), err)
+		}
+	}
+	return nil
+}
+
+// 点的VID为空(nil 或零值)且模型实现了 model.IVidGenerate 时生成VID。
+// 模型为指针时直接写入模型, 否则返回写入VID后的副本
+//
+// @Author: 罗德
+// @Date: 2024/7/6
+func generateVid(vertex model.IVertex) (model.IVertex, error) {
+	generate, ok := vertex.(model.IVidGenerate)
+	if !ok || (vertex.GetVid() != nil && !reflect.ValueOf(vertex.GetVid()).IsZero()) {
+		return vertex, nil
+	}
+	vid, err := generate.VidGenerator().Generate(vertex)
+	if err != nil {
+		return nil, fmt.Errorf("生成点 %s 的VID失败: %w", vertex.TagName(), err)
+	}
+	if setter, ok := vertex.(model.ISetVid); ok && reflect.ValueOf(vertex).Kind() == reflect.Ptr {
+		setter.SetVid(vid)
+		return vertex, nil
+	}
+	copied := reflect.New(reflect.TypeOf(vertex))
+	copied.Elem().Set(reflect.ValueOf(vertex))
+	setter, ok := copied.Interface().(model.ISetVid)
+	if !ok {
+		return nil, fmt.Errorf("%T 需要嵌入 model.VModel 或实现 model.ISetVid 才能写入生成的VID", vertex)
+	}
+	setter.SetVid(vid)
+	return copied.Elem().Interface().(model.IVertex), nil
+}
@@ -0,0 +1,131 @@
+package orm
+
+import (
+	"context"
+	"nebula-orm-go/model"
+	"nebula-orm-go/vidgen"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// DetectVidType 直接通过拨号器执行 describe space, 之后按VID类型校验写入的点
+func TestDetectVidType(t *testing.T) {
+	db, dialer := openMock(t)
+	dialer.Expect("describe space `s`").
+		WillReturnRows([]string{"ID", "Name", "Vid Type"}, []interface{}{int64(1), "s", "FIXED_STRING(4)"})
+
+	// 试运行与 explain 不影响 describe space
+	vidType, err := db.DryRun().Explain().DetectVidType("s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vidType.String() != "FIXED_STRING(4)" {
+		t.Fatalf("VID类型 = %s", vidType)
+	}
+
+	db, dialer = openMock(t)
+	dialer.Expect("describe space `s`").
+		WillReturnRows([]string{"ID", "Name", "Vid Type"}, []interface{}{int64(1), "s", "INT64"})
+	if _, err = db.DetectVidType("s"); err != nil {
+		t.Fatal(err)
+	}
+	v := &hookVertex{Name: "甲"}
+	v.Vid = "a"
+	if err = db.InsertVertex(v); err == nil || !strings.Contains(err.Error(), "必须是整数") {
+		t.Fatalf("INT64 图空间写入字符串VID时应返回错误, 实际为 %v", err)
+	}
+
+	db, dialer = openMock(t)
+	dialer.Expect("describe space `missing`").WillReturnRows([]string{"ID", "Name", "Vid Type"})
+	if _, err = db.DetectVidType("missing"); err == nil {
+		t.Fatal("图空间不存在时应返回错误")
+	}
+}
+
+// DetectVidType 之前创建的调用链同样按新的VID类型校验, 并发调用不产生数据竞争
+func TestDetectVidTypeShared(t *testing.T) {
+	db, dialer := openMock(t)
+	dialer.Expect("describe space `s`").
+		WillReturnRows([]string{"ID", "Name", "Vid Type"}, []interface{}{int64(1), "s", "INT64"}).Times(8)
+
+	tx := db.WithContext(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := db.DetectVidType("s"); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			_ = tx.VidType()
+		}()
+	}
+	wg.Wait()
+
+	if vidType := tx.VidType(); vidType == nil || vidType.String() != "INT64" {
+		t.Fatalf("调用链的VID类型 = %v, 期望 INT64", vidType)
+	}
+	v := &hookVertex{Name: "甲"}
+	v.Vid = "a"
+	if err := tx.InsertVertex(v); err == nil || !strings.Contains(err.Error(), "必须是整数") {
+		t.Fatalf("之前创建的调用链写入字符串VID时应返回错误, 实际为 %v", err)
+	}
+}
+
+// 按名称生成VID的点
+type generatedVertex struct {
+	model.VModel
+	Name string `nebula:"name"`
+}
+
+func (v generatedVertex) TagName() string {
+	return "generated"
+}
+
+func (v generatedVertex) VidGenerator() model.IVidGenerator {
+	return vidgen.Func(func(vertex model.IVertex) (interface{}, error) {
+		if v, ok := vertex.(*generatedVertex); ok {
+			return "id-" + v.Name, nil
+		}
+		return "id-" + vertex.(generatedVertex).Name, nil
+	})
+}
+
+// 批量插入时指针元素直接写入VID, 非指针元素在传入的切片中被替换为写入VID后的副本
+func TestInsertBatchGeneratedVid(t *testing.T) {
+	inserts := map[string]func(db *DB, vertexs []model.IVertex) error{
+		"InsertVertexBatch":   (*DB).InsertVertexBatch,
+		"InsertVertexGrouped": (*DB).InsertVertexGrouped,
+		"InsertVertexChunked": func(db *DB, vertexs []model.IVertex) error {
+			_, err := db.InsertVertexChunked(vertexs)
+			return err
+		},
+	}
+	for name, insert := range inserts {
+		t.Run(name, func(t *testing.T) {
+			db, dialer := openMock(t)
+			dialer.Expect("insert vertex generated(name) values 'id-a':('a'), 'id-b':('b'), 'c':('c')")
+
+			pointer := &generatedVertex{Name: "b"}
+			preset := generatedVertex{Name: "c"}
+			preset.Vid = "c"
+			vertexs := []model.IVertex{generatedVertex{Name: "a"}, pointer, preset}
+			if err := insert(db, vertexs); err != nil {
+				t.Fatal(err)
+			}
+			if vid := vertexs[0].GetVid(); vid != "id-a" {
+				t.Errorf("非指针元素的VID = %v, 期望替换为写入VID后的副本", vid)
+			}
+			if vertexs[1] != pointer || pointer.Vid != "id-b" {
+				t.Errorf("指针元素应保持不变并写入VID, 实际为 %v", vertexs[1])
+			}
+			if vertexs[2] != preset {
+				t.Errorf("已有VID的元素不应被替换")
+			}
+		})
+	}
+}
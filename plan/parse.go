@@ -0,0 +1,408 @@
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 匹配表格/ dot 文本中的执行统计, 兼容 JSON 与 key: value 两种写法, 例如 "rows": 1 / execTime: 12(us) / totalTime: 15us
+var (
+	rowsRegexp      = regexp.MustCompile(`"?rows"?\s*[:=]\s*"?(\d+)`)
+	execTimeRegexp  = regexp.MustCompile(`"?execTime"?\s*[:=]\s*"?(\d+)\s*\(?(us|ms|s)?`)
+	totalTimeRegexp = regexp.MustCompile(`"?totalTime"?\s*[:=]\s*"?(\d+)\s*\(?(us|ms|s)?`)
+	descRegexp      = regexp.MustCompile(`^"?([A-Za-z_][\w ]*?)"?\s*:\s*(.*)$`)
+	optimizeRegexp  = regexp.MustCompile(`optimize time (\d+)\s*\(?(us|ms|s)?`)
+	durationRegexp  = regexp.MustCompile(`^(\d+)\s*\(?(us|ms|s)?`)
+
+	// dot 的属性列表, 引号内的 ] 不结束列表, 例如 [label="{Loop_5|...\]...}", shape=Mrecord]
+	dotAttrs        = `\[((?:[^\]"\\]|\\.|"(?:[^"\\]|\\.)*")*)\]`
+	dotNodeRegexp   = regexp.MustCompile(`"([^"]+)"\s*` + dotAttrs)
+	dotEdgeRegexp   = regexp.MustCompile(`"([^"]+)"\s*->\s*"([^"]+)"(?:\s*` + dotAttrs + `)?`)
+	dotLabelRegexp  = regexp.MustCompile(`label\s*=\s*"((?:[^"\\]|\\.)*)"`)
+	dotDashedRegexp = regexp.MustCompile(`style\s*=\s*"?dashed`)
+)
+
+// Parse 解析控制台输出的执行计划文本, 自动识别 dot 格式与 row/tck 表格格式
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func Parse(text string) (*Plan, error) {
+	if strings.Contains(text, "digraph") || dotEdgeRegexp.MatchString(text) {
+		return ParseDot(text)
+	}
+	return ParseTable(text)
+}
+
+// ParseTable 解析 row/tck 格式的表格文本, 表头至少包含 id、name 两列,
+// 可选 dependencies、profiling data、operator info 列; 单元格跨多行时 id 为空的行视为上一行的延续。
+// profiling data 为 JSON(并行执行时为数组)或 key: value 文本, operator info 中跨多行的 JSON 值合并到同一个键;
+// 表格之前的 Execution Plan (optimize time 84 us) 解析为优化器耗时。
+//
+//	| id | name        | dependencies | profiling data | operator info |
+//	| 2  | Project     | 1            |                |               |
+//	| 1  | GetVertices | 0            |                |               |
+//	| 0  | Start       |              |                |               |
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func ParseTable(text string) (*Plan, error) {
+	var header map[string]int
+	var nodes []*Node
+	// 按算子累积的 profiling data / operator info 文本, 全部行读取完成后再解析
+	profiles := make(map[*Node]*strings.Builder)
+	infos := make(map[*Node]*strings.Builder)
+
+	format := FormatRow
+	hasInfo := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "|") {
+			continue
+		}
+		cells := strings.Split(strings.Trim(line, "|"), "|")
+		for i := range cells {
+			cells[i] = strings.TrimSpace(cells[i])
+		}
+
+		// 第一行表头
+		if header == nil {
+			header = make(map[string]int, len(cells))
+			for i, cell := range cells {
+				header[strings.ToLower(cell)] = i
+			}
+			if _, ok := header["id"]; !ok {
+				return nil, fmt.Errorf("执行计划表头缺少 id 列: %s", line)
+			}
+			if _, ok := header["name"]; !ok {
+				return nil, fmt.Errorf("执行计划表头缺少 name 列: %s", line)
+			}
+			if _, ok := header["profiling data"]; !ok {
+				format = FormatTck
+			}
+			continue
+		}
+
+		cell := func(name string) string {
+			if i, ok := header[name]; ok && i < len(cells) {
+				return cells[i]
+			}
+			return ""
+		}
+
+		if id := cell("id"); id != "" {
+			nodeID, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("执行计划算子ID无效: %s", id)
+			}
+			node := &Node{ID: nodeID, Name: cell("name"), Description: make(map[string]string)}
+			for _, dep := range strings.Split(cell("dependencies"), ",") {
+				if dep = strings.TrimSpace(dep); dep == "" {
+					continue
+				}
+				depID, err := strconv.ParseInt(dep, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("算子 %s[%d] 的依赖无效: %s", node.Name, node.ID, dep)
+				}
+				node.Dependencies = append(node.Dependencies, depID)
+			}
+			nodes = append(nodes, node)
+			profiles[node] = new(strings.Builder)
+			infos[node] = new(strings.Builder)
+		}
+		if len(nodes) == 0 {
+			continue
+		}
+		node := nodes[len(nodes)-1]
+		profiles[node].WriteString(cell("profiling data") + "\n")
+		infos[node].WriteString(cell("operator info") + "\n")
+		hasInfo = hasInfo || cell("operator info") != ""
+	}
+
+	// tck 格式没有 operator info
+	if !hasInfo {
+		format = FormatTck
+	}
+	for _, node := range nodes {
+		node.Profiles = parseProfiles(profiles[node].String())
+		parseDescription(node.Description, strings.Split(infos[node].String(), "\n"))
+	}
+	return build(format, parseOptimizeTime(text), nodes)
+}
+
+// ParseDot 解析 dot 格式的执行计划文本, 节点名称形如 "Project_2"(算子名_算子ID),
+// 边 "A"->"B" 按数据流方向理解为 B 依赖 A; 若按此理解 Start 算子出现了依赖, 则认为边的方向相反。
+// Loop/Select 等没有 label 的节点(shape=diamond)只有名称, 虚线边(style=dashed)表示分支而非依赖, 会被忽略。
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func ParseDot(text string) (*Plan, error) {
+	var nodes []*Node
+	index := make(map[string]*Node)
+
+	// 先取出边, 避免边的属性列表被当作节点
+	edges := dotEdgeRegexp.FindAllStringSubmatch(text, -1)
+	for _, match := range dotNodeRegexp.FindAllStringSubmatch(dotEdgeRegexp.ReplaceAllString(text, ""), -1) {
+		if _, ok := index[match[1]]; ok {
+			continue
+		}
+		node, err := newDotNode(match[1])
+		if err != nil {
+			return nil, err
+		}
+		node.Description = make(map[string]string)
+		if label := dotLabelRegexp.FindStringSubmatch(match[2]); label != nil {
+			parts := splitDotLabel(label[1])
+			if len(parts) > 1 {
+				parts = parts[1:] // 第一段为节点名称
+			}
+			lines := strings.Split(strings.Join(parts, "\n"), "\n")
+			if profile, ok := parseProfile(strings.Join(lines, "\n")); ok {
+				node.Profiles = append(node.Profiles, profile)
+			}
+			parseDescription(node.Description, lines)
+		}
+		nodes = append(nodes, node)
+		index[match[1]] = node
+	}
+
+	dependencies := edges[:0]
+	for _, edge := range edges {
+		if !dotDashedRegexp.MatchString(edge[3]) {
+			dependencies = append(dependencies, edge)
+		}
+	}
+	reversed := false
+	for _, edge := range dependencies {
+		if node, ok := index[edge[2]]; ok && node.Name == "Start" {
+			reversed = true
+			break
+		}
+	}
+	for _, edge := range dependencies {
+		from, to := edge[1], edge[2]
+		if reversed {
+			from, to = to, from
+		}
+		dependent, ok := index[to]
+		if !ok {
+			return nil, fmt.Errorf("dot 执行计划中的节点不存在: %s", to)
+		}
+		dependency, ok := index[from]
+		if !ok {
+			return nil, fmt.Errorf("dot 执行计划中的节点不存在: %s", from)
+		}
+		dependent.Dependencies = append(dependent.Dependencies, dependency.ID)
+	}
+
+	return build(FormatDot, parseOptimizeTime(text), nodes)
+}
+
+// 拆分 Mrecord 节点的 label: 去掉外层花括号, 按未转义的 | 拆分字段, 并还原 \{ \" \[ 等转义与 \l 换行
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func splitDotLabel(label string) []string {
+	if strings.HasPrefix(label, "{") && strings.HasSuffix(label, "}") && !strings.HasSuffix(label, `\}`) {
+		label = label[1 : len(label)-1]
+	}
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(label); i++ {
+		switch c := label[i]; {
+		case c == '\\' && i+1 < len(label):
+			i++
+			switch label[i] {
+			case 'l', 'n', 'r':
+				part.WriteByte('\n')
+			default:
+				part.WriteByte(label[i])
+			}
+		case c == '|':
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(c)
+		}
+	}
+	return append(parts, part.String())
+}
+
+// 解析 dot 节点名称 Name_ID
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func newDotNode(name string) (*Node, error) {
+	i := strings.LastIndex(name, "_")
+	if i < 0 {
+		return nil, fmt.Errorf("dot 执行计划节点名称无效: %s", name)
+	}
+	id, err := strconv.ParseInt(name[i+1:], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("dot 执行计划节点名称无效: %s", name)
+	}
+	return &Node{ID: id, Name: name[:i]}, nil
+}
+
+// 解析 profiling data: graphd 3.x 为 JSON 对象, 并行执行多次时为对象数组, 其它统计保存到 Profile.Other;
+// 不是 JSON 时按 key: value 文本解析一条统计
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func parseProfiles(text string) []Profile {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	var stats []map[string]json.RawMessage
+	var err error
+	if strings.HasPrefix(text, "[") {
+		err = json.Unmarshal([]byte(text), &stats)
+	} else {
+		var stat map[string]json.RawMessage
+		err = json.Unmarshal([]byte(text), &stat)
+		stats = append(stats, stat)
+	}
+	if err != nil {
+		if profile, ok := parseProfile(text); ok {
+			return []Profile{profile}
+		}
+		return nil
+	}
+
+	profiles := make([]Profile, 0, len(stats))
+	for _, stat := range stats {
+		var profile Profile
+		for key, raw := range stat {
+			var value string
+			if json.Unmarshal(raw, &value) != nil {
+				// 数字或嵌套对象保留紧凑的 JSON 文本
+				compact := new(bytes.Buffer)
+				_ = json.Compact(compact, raw)
+				value = compact.String()
+			}
+			switch key {
+			case "version":
+			case "rows":
+				profile.Rows, _ = strconv.ParseInt(value, 10, 64)
+			case "execTime", "totalTime":
+				var duration time.Duration
+				if match := durationRegexp.FindStringSubmatch(value); match != nil {
+					duration = parseDuration(match[1], match[2])
+				}
+				if key == "execTime" {
+					profile.ExecDuration = duration
+				} else {
+					profile.TotalDuration = duration
+				}
+			default:
+				if profile.Other == nil {
+					profile.Other = make(map[string]string)
+				}
+				profile.Other[key] = value
+			}
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+// 解析控制台输出的 Execution Plan (optimize time 84 us), 没有时返回0
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func parseOptimizeTime(text string) time.Duration {
+	if match := optimizeRegexp.FindStringSubmatch(text); match != nil {
+		return parseDuration(match[1], match[2])
+	}
+	return 0
+}
+
+// 从文本中解析执行统计, 没有任何统计字段时返回false
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func parseProfile(text string) (Profile, bool) {
+	var profile Profile
+	found := false
+	if match := rowsRegexp.FindStringSubmatch(text); match != nil {
+		profile.Rows, _ = strconv.ParseInt(match[1], 10, 64)
+		found = true
+	}
+	if match := execTimeRegexp.FindStringSubmatch(text); match != nil {
+		profile.ExecDuration = parseDuration(match[1], match[2])
+		found = true
+	}
+	if match := totalTimeRegexp.FindStringSubmatch(text); match != nil {
+		profile.TotalDuration = parseDuration(match[1], match[2])
+		found = true
+	}
+	return profile, found
+}
+
+// 将数值与单位转换为 time.Duration, 未标明单位时按微秒处理(与 graphd 返回一致)
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func parseDuration(value, unit string) time.Duration {
+	n, _ := strconv.ParseInt(value, 10, 64)
+	switch unit {
+	case "ms":
+		return time.Duration(n) * time.Millisecond
+	case "s":
+		return time.Duration(n) * time.Second
+	default:
+		return time.Duration(n) * time.Microsecond
+	}
+}
+
+// 将 key: value 形式的多行算子信息解析到 desc 中, 不符合该形式的行以及括号未闭合时的后续行(例如格式化的 JSON)追加到上一个键的值
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func parseDescription(desc map[string]string, lines []string) {
+	lastKey := ""
+	depth := 0
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if match := descRegexp.FindStringSubmatch(line); match != nil && depth <= 0 {
+			lastKey = match[1]
+			desc[lastKey] = match[2]
+			depth = nesting(match[2])
+			continue
+		}
+		if lastKey != "" {
+			desc[lastKey] += line
+			depth += nesting(line)
+		}
+	}
+}
+
+// 统计一行中未闭合的括号层数, 引号内的括号不计
+func nesting(line string) int {
+	depth := 0
+	quoted, escaped := false, false
+	for _, c := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted:
+			escaped = c == '\\'
+			quoted = c != '"'
+		case c == '"':
+			quoted = true
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+	return depth
+}
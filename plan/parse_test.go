@@ -0,0 +1,199 @@
+package plan
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 使用 go test ./plan -update 重新生成 testdata 下的快照文件
+var update = flag.Bool("update", false, "重新生成 testdata 下的 golden 快照文件")
+
+// testdata 下的 *.txt 为控制台 explain/profile 的原始输出, 解析后的计划树渲染结果保存为同名 .golden 快照
+func TestParseFixtures(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		parse   func(string) (*Plan, error)
+		root    string
+		nodes   int
+		profile map[int64][]Profile // 算子ID -> 期望的执行统计
+	}{
+		{name: "explain_fetch_row", format: FormatRow, parse: ParseTable, root: "Project", nodes: 3},
+		{name: "profile_fetch_row", format: FormatRow, parse: ParseTable, root: "Project", nodes: 3, profile: map[int64][]Profile{
+			2: {{Rows: 1, ExecDuration: 12 * time.Microsecond, TotalDuration: 15 * time.Microsecond}},
+			1: {{Rows: 1, ExecDuration: 1071 * time.Microsecond, TotalDuration: 1203 * time.Microsecond,
+				Other: map[string]string{"total_rpc": `{"storaged0:9779":{"exec":"402(us)","total":"863(us)"}}`}}},
+			0: {{Rows: 0, ExecDuration: 3 * time.Microsecond, TotalDuration: 48 * time.Microsecond}},
+		}},
+		{name: "explain_go_row", format: FormatRow, parse: ParseTable, root: "Project", nodes: 8},
+		{name: "profile_go_row", format: FormatRow, parse: ParseTable, root: "Project", nodes: 8, profile: map[int64][]Profile{
+			6: {
+				{Rows: 3, ExecDuration: 210 * time.Microsecond, TotalDuration: 250 * time.Microsecond},
+				{Rows: 2, ExecDuration: 190 * time.Microsecond, TotalDuration: 230 * time.Microsecond},
+			},
+		}},
+		{name: "explain_fetch_dot", format: FormatDot, parse: ParseDot, root: "Project", nodes: 3},
+		{name: "profile_go_dot", format: FormatDot, parse: ParseDot, root: "Project", nodes: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.name+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, parse := range []func(string) (*Plan, error){tt.parse, Parse} {
+				plan, err := parse(string(data))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if plan.Format != tt.format || plan.Root.Name != tt.root || len(plan.Nodes) != tt.nodes {
+					t.Fatalf("format=%s root=%s nodes=%d, 期望 %s %s %d", plan.Format, plan.Root.Name, len(plan.Nodes), tt.format, tt.root, tt.nodes)
+				}
+				if plan.OptimizeTime != 84*time.Microsecond {
+					t.Errorf("OptimizeTime = %s, 期望 84µs", plan.OptimizeTime)
+				}
+				for id, want := range tt.profile {
+					if got := plan.Node(id).Profiles; !reflect.DeepEqual(got, want) {
+						t.Errorf("算子 %d 的执行统计 = %+v, 期望 %+v", id, got, want)
+					}
+				}
+				assertGolden(t, tt.name, plan.String())
+			}
+		})
+	}
+}
+
+// 跨多行的 JSON 值合并到同一个键, 不会把 JSON 的字段当作新的键
+func TestParseTableDescription(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "profile_fetch_row.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := ParseTable(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"outputVar": `{"colNames": [],"type": "DATASET","name": "__GetVertices_1"}`,
+		"inputVar":  "",
+		"space":     "1",
+		"dedup":     "false",
+		"src":       `"player100"`,
+		"props":     `[{"props": ["age"],"tagId": 2}]`,
+		"filter":    "(player.age>30)",
+	}
+	if got := plan.Node(1).Description; !reflect.DeepEqual(got, want) {
+		t.Errorf("Description = %v, 期望 %v", got, want)
+	}
+}
+
+// Loop 节点没有 label, 虚线的分支边不作为依赖, 否则 Start_1 会被当作依赖方而反转全部边
+func TestParseDotBranch(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "profile_go_dot.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := ParseDot(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loop := plan.Node(5); loop == nil || !reflect.DeepEqual(loop.Dependencies, []int64{4, 0}) || len(loop.Description) != 0 {
+		t.Fatalf("Loop = %+v", loop)
+	}
+	if start := plan.Node(1); len(start.Dependencies) != 0 {
+		t.Errorf("Start_1 的依赖 = %v, 期望为空", start.Dependencies)
+	}
+	if got, want := plan.Node(7).Description["outputVar"], `{"colNames":[],"type":"DATASET","name":"__Project_7"}`; got != want {
+		t.Errorf("outputVar = %s, 期望 %s", got, want)
+	}
+}
+
+func TestParseProfiles(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Profile
+	}{
+		{name: "空", text: " \n", want: nil},
+		{name: "tck", text: `{"rows":2,"version":0}`, want: []Profile{{Rows: 2}}},
+		{name: "旧版文本", text: "ver: 0, rows: 1, execTime: 38us, totalTime: 40us", want: []Profile{
+			{Rows: 1, ExecDuration: 38 * time.Microsecond, TotalDuration: 40 * time.Microsecond},
+		}},
+		{name: "毫秒", text: `{"rows":1,"execTime":"3(ms)","totalTime":"1(s)"}`, want: []Profile{
+			{Rows: 1, ExecDuration: 3 * time.Millisecond, TotalDuration: time.Second},
+		}},
+		{name: "其它统计", text: `[{"rows":1,"total_rpc_time":"5(us)"},{"rows":2,"version":1}]`, want: []Profile{
+			{Rows: 1, Other: map[string]string{"total_rpc_time": "5(us)"}},
+			{Rows: 2},
+		}},
+		{name: "无统计", text: "outputVar: x", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseProfiles(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseProfiles() = %+v, 期望 %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTableTck(t *testing.T) {
+	text := strings.Join([]string{
+		"| id | name        | dependencies | profiling data         | operator info |",
+		"|  1 | GetVertices | 0            | {\"rows\":1,\"version\":0} |               |",
+		"|  0 | Start       |              | {\"rows\":0,\"version\":0} |               |",
+	}, "\n")
+	plan, err := ParseTable(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Format != FormatTck || plan.Root.Rows() != 1 || plan.OptimizeTime != 0 {
+		t.Errorf("format=%s rows=%d optimize=%s", plan.Format, plan.Root.Rows(), plan.OptimizeTime)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		err  string
+	}{
+		{name: "缺少 id 列", text: "| name |\n| Start |", err: "缺少 id 列"},
+		{name: "算子ID无效", text: "| id | name |\n| x | Start |", err: "算子ID无效"},
+		{name: "依赖不存在", text: "| id | name | dependencies |\n| 1 | Project | 0 |", err: "依赖的算子 0 不存在"},
+		{name: "空计划", text: "| id | name |", err: "执行计划为空"},
+		{name: "dot 节点不存在", text: "digraph {\n\"Project_1\"[label=\"{Project_1}\"];\n\"Start_0\"->\"Project_1\";\n}", err: "节点不存在: Start_0"},
+		{name: "dot 节点名称无效", text: "digraph {\n\"Project\"[shape=Mrecord];\n}", err: "节点名称无效"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.text); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("Parse() error = %v, 期望包含 %q", err, tt.err)
+			}
+		})
+	}
+}
+
+// 比较快照文件, -update 时重新生成
+func assertGolden(t *testing.T, name string, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取快照失败, 请使用 -update 生成: %v", err)
+	}
+	if string(want) != got {
+		t.Errorf("%s 与快照不一致\n期望:\n%s\n实际:\n%s", name, want, got)
+	}
+}
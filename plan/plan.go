@@ -0,0 +1,238 @@
+// Package plan 解析 EXPLAIN/PROFILE 返回的执行计划, 支持 graphd 返回的结构化计划(PlanDescription),
+// 以及控制台输出的 row/tck 表格文本与 dot 文本, 统一转换为 Go 的计划树并渲染为文本。
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+package plan
+
+import (
+	"fmt"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 执行计划格式
+const (
+	FormatRow = "row"
+	FormatDot = "dot"
+	FormatTck = "tck"
+)
+
+// Profile 算子的一次执行统计(PROFILE 才有), 并行执行的算子可能有多条
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+type Profile struct {
+	Rows          int64             `json:"rows"`           // 输出行数
+	ExecDuration  time.Duration     `json:"exec_duration"`  // 算子执行耗时
+	TotalDuration time.Duration     `json:"total_duration"` // 算子总耗时(包含调度)
+	Other         map[string]string `json:"other,omitempty"`
+}
+
+// Node 执行计划中的一个算子
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+type Node struct {
+	ID           int64             `json:"id"`                    // 算子ID
+	Name         string            `json:"name"`                  // 算子名称, 例如 Project、GetNeighbors
+	OutputVar    string            `json:"output_var,omitempty"`  // 输出变量
+	Description  map[string]string `json:"description,omitempty"` // 算子信息(operator info)
+	Profiles     []Profile         `json:"profiles,omitempty"`    // 执行统计
+	Dependencies []int64           `json:"dependencies"`          // 依赖的算子ID
+	Children     []*Node           `json:"-"`                     // 依赖的算子, 与 Dependencies 一一对应
+}
+
+// Rows 返回算子所有执行统计的输出行数之和
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func (n *Node) Rows() int64 {
+	var rows int64
+	for _, profile := range n.Profiles {
+		rows += profile.Rows
+	}
+	return rows
+}
+
+// ExecDuration 返回算子所有执行统计的执行耗时之和
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func (n *Node) ExecDuration() time.Duration {
+	var duration time.Duration
+	for _, profile := range n.Profiles {
+		duration += profile.ExecDuration
+	}
+	return duration
+}
+
+// Plan 执行计划树
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+type Plan struct {
+	Format       string        `json:"format"`        // 计划格式
+	OptimizeTime time.Duration `json:"optimize_time"` // 优化器耗时
+	Root         *Node         `json:"-"`             // 根算子(没有被其他算子依赖的算子)
+	Nodes        []*Node       `json:"nodes"`         // 所有算子, 按ID降序排列
+}
+
+// FromPlanDescription 将 graphd 返回的结构化执行计划转换为计划树
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func FromPlanDescription(desc *graph.PlanDescription) (*Plan, error) {
+	if desc == nil {
+		return nil, fmt.Errorf("结果集中没有执行计划, 请确认语句使用了 explain 或 profile")
+	}
+
+	nodes := make([]*Node, 0, len(desc.GetPlanNodeDescs()))
+	for _, nodeDesc := range desc.GetPlanNodeDescs() {
+		node := &Node{
+			ID:           nodeDesc.GetId(),
+			Name:         string(nodeDesc.GetName()),
+			OutputVar:    string(nodeDesc.GetOutputVar()),
+			Description:  make(map[string]string, len(nodeDesc.GetDescription())),
+			Dependencies: nodeDesc.GetDependencies(),
+		}
+		for _, pair := range nodeDesc.GetDescription() {
+			node.Description[string(pair.GetKey())] = string(pair.GetValue())
+		}
+		for _, stats := range nodeDesc.GetProfiles() {
+			profile := Profile{
+				Rows:          stats.GetRows(),
+				ExecDuration:  time.Duration(stats.GetExecDurationInUs()) * time.Microsecond,
+				TotalDuration: time.Duration(stats.GetTotalDurationInUs()) * time.Microsecond,
+			}
+			if len(stats.GetOtherStats()) > 0 {
+				profile.Other = make(map[string]string, len(stats.GetOtherStats()))
+				for key, value := range stats.GetOtherStats() {
+					profile.Other[key] = string(value)
+				}
+			}
+			node.Profiles = append(node.Profiles, profile)
+		}
+		nodes = append(nodes, node)
+	}
+
+	return build(string(desc.GetFormat()), time.Duration(desc.GetOptimizeTimeInUs())*time.Microsecond, nodes)
+}
+
+// 根据算子的依赖关系构建计划树: 关联子算子并找出根算子
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func build(format string, optimizeTime time.Duration, nodes []*Node) (*Plan, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("执行计划为空")
+	}
+	if format == "" {
+		format = FormatRow
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID > nodes[j].ID
+	})
+	index := make(map[int64]*Node, len(nodes))
+	for _, node := range nodes {
+		index[node.ID] = node
+	}
+
+	depended := make(map[int64]bool, len(nodes))
+	for _, node := range nodes {
+		node.Children = make([]*Node, 0, len(node.Dependencies))
+		for _, dep := range node.Dependencies {
+			child, ok := index[dep]
+			if !ok {
+				return nil, fmt.Errorf("算子 %s[%d] 依赖的算子 %d 不存在", node.Name, node.ID, dep)
+			}
+			node.Children = append(node.Children, child)
+			depended[dep] = true
+		}
+	}
+
+	plan := &Plan{Format: format, OptimizeTime: optimizeTime, Nodes: nodes}
+	for _, node := range nodes {
+		if !depended[node.ID] {
+			plan.Root = node
+			break
+		}
+	}
+	if plan.Root == nil {
+		return nil, fmt.Errorf("执行计划存在循环依赖, 无法确定根算子")
+	}
+	return plan, nil
+}
+
+// Node 根据算子ID查找算子, 不存在时返回nil
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func (p *Plan) Node(id int64) *Node {
+	for _, node := range p.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+	return nil
+}
+
+// String 将计划树渲染为缩进文本, 每行一个算子, 包含输出行数与执行耗时(PROFILE)以及算子信息
+//
+//	Project[3] rows=10 exec=35µs total=120µs
+//	└─ Filter[2] rows=10 exec=20µs total=80µs condition=(v.player.age>30)
+//	   └─ GetVertices[1] ...
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func (p *Plan) String() string {
+	builder := new(strings.Builder)
+	if p.OptimizeTime > 0 {
+		builder.WriteString(fmt.Sprintf("optimize time: %s\n", p.OptimizeTime))
+	}
+	visited := make(map[int64]bool, len(p.Nodes))
+	render(builder, p.Root, "", "", visited)
+	return builder.String()
+}
+
+// 递归渲染算子及其依赖, 已渲染过的算子(例如 Loop/Select 分支共享的算子)只输出引用
+//
+// @Author: 罗德
+// @Date: 2024/6/21
+func render(builder *strings.Builder, node *Node, prefix, childPrefix string, visited map[int64]bool) {
+	builder.WriteString(prefix)
+	builder.WriteString(fmt.Sprintf("%s[%d]", node.Name, node.ID))
+	if visited[node.ID] {
+		builder.WriteString(" (见上文)\n")
+		return
+	}
+	visited[node.ID] = true
+
+	if len(node.Profiles) > 0 {
+		var total time.Duration
+		for _, profile := range node.Profiles {
+			total += profile.TotalDuration
+		}
+		builder.WriteString(fmt.Sprintf(" rows=%d exec=%s total=%s", node.Rows(), node.ExecDuration(), total))
+	}
+	keys := make([]string, 0, len(node.Description))
+	for key := range node.Description {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		builder.WriteString(fmt.Sprintf(" %s=%s", key, node.Description[key]))
+	}
+	builder.WriteString("\n")
+
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			render(builder, child, childPrefix+"└─ ", childPrefix+"   ", visited)
+		} else {
+			render(builder, child, childPrefix+"├─ ", childPrefix+"│  ", visited)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Package tracing 语句级链路追踪抽象, 概念与 OpenTelemetry 保持一致(Tracer/Span/Attribute), 但不依赖其SDK,
+// 使用方可以用几行代码将 OpenTelemetry 的 trace.Tracer 适配为 ITracer。
+//
+// @Author: 罗德
+// @Date: 2024/6/20
+package tracing
+
+import "context"
+
+// Span 属性名, 参考 OpenTelemetry 数据库语义约定
+const (
+	AttrDBSystem    = "db.system"            // 固定为 nebula
+	AttrDBName      = "db.name"              // 图空间名称
+	AttrDBOperation = "db.operation"         // 语句类型, 见 utils.GetStatementKind
+	AttrDBStatement = "db.statement"         // 执行的nGQL, 开启脱敏时属性值会被替换
+	AttrModel       = "db.nebula.model"      // 点/边模型名称(TagName/EdgeName)
+	AttrRows        = "db.nebula.rows"       // 返回的行数
+	AttrErrorCode   = "db.nebula.error_code" // nebula错误码, 成功为0
+)
+
+// SystemNebula db.system 属性值
+const SystemNebula = "nebula"
+
+// Attribute Span 属性键值对
+//
+// @Author: 罗德
+// @Date: 2024/6/20
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// ITracer 追踪器接口, 为每条语句创建一个 Span。
+// Start 返回的 context 应携带新建的 Span, 以便使用方继续向下传播。
+//
+// @Author: 罗德
+// @Date: 2024/6/20
+type ITracer interface {
+	Start(ctx context.Context, name string) (context.Context, ISpan)
+}
+
+// ISpan 一次语句执行对应的 Span
+//
+// @Author: 罗德
+// @Date: 2024/6/20
+type ISpan interface {
+	// SetAttributes 设置 Span 属性
+	SetAttributes(attrs ...Attribute)
+	// RecordError 记录错误并将 Span 状态标记为失败
+	RecordError(err error)
+	// End 结束 Span
+	End()
+}
+
+// Nop 不做任何记录的追踪器, 未配置追踪时使用
+//
+// @Author: 罗德
+// @Date: 2024/6/20
+var Nop ITracer = nopTracer{}
+
+type nopTracer struct{}
+
+func (nopTracer) Start(ctx context.Context, _ string) (context.Context, ISpan) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(...Attribute) {}
+func (nopSpan) RecordError(error)          {}
+func (nopSpan) End()                       {}
@@ -0,0 +1,21 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type ctxKey struct{}
+
+// 未配置追踪时原样返回上下文, Span 的方法可以安全调用
+func TestNop(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "parent")
+	got, span := Nop.Start(ctx, "nebula.fetch")
+	if got != ctx || span == nil {
+		t.Fatalf("Start() = %v, %v", got, span)
+	}
+	span.SetAttributes(Attribute{Key: AttrDBSystem, Value: SystemNebula})
+	span.RecordError(errors.New("测试错误"))
+	span.End()
+}
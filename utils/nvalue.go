@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"fmt"
+	nebula_type "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"reflect"
+	"time"
+)
+
+// InterfaceToNValue 将 Go 语言的值转换为 nebula_type.Value, 是 NValueToInterface 的逆操作, 用于构造结果集(例如测试中的模拟结果集)。
+// 支持: nil、bool、整型、浮点型、string、[]byte、time.Time(转换为UTC的datetime)、nebula的各类值类型及其指针、
+// 切片/数组(转换为列表)、以 string 为键的map(转换为映射), 以及 *nebula_type.Value 本身。
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func InterfaceToNValue(v interface{}) (*nebula_type.Value, error) {
+	value := nebula_type.NewValue()
+	switch val := v.(type) {
+	case nil:
+		null := nebula_type.NullType___NULL__
+		value.NVal = &null
+	case *nebula_type.Value:
+		return val, nil
+	case nebula_type.Value:
+		return &val, nil
+	case bool:
+		value.BVal = &val
+	case string:
+		value.SVal = []byte(val)
+	case []byte:
+		value.SVal = val
+	case time.Time:
+		utc := val.UTC()
+		value.DtVal = &nebula_type.DateTime{
+			Year:     int16(utc.Year()),
+			Month:    int8(utc.Month()),
+			Day:      int8(utc.Day()),
+			Hour:     int8(utc.Hour()),
+			Minute:   int8(utc.Minute()),
+			Sec:      int8(utc.Second()),
+			Microsec: int32(utc.Nanosecond() / 1000),
+		}
+	case nebula_type.Date:
+		value.DVal = &val
+	case *nebula_type.Date:
+		value.DVal = val
+	case nebula_type.Time:
+		value.TVal = &val
+	case *nebula_type.Time:
+		value.TVal = val
+	case nebula_type.DateTime:
+		value.DtVal = &val
+	case *nebula_type.DateTime:
+		value.DtVal = val
+	case nebula_type.Vertex:
+		value.VVal = &val
+	case *nebula_type.Vertex:
+		value.VVal = val
+	case nebula_type.Edge:
+		value.EVal = &val
+	case *nebula_type.Edge:
+		value.EVal = val
+	case *nebula_type.Path:
+		value.PVal = val
+	default:
+		return reflectToNValue(reflect.ValueOf(v))
+	}
+	return value, nil
+}
+
+// 通过反射转换数值、切片与map类型
+//
+// @Author: 罗德
+// @Date: 2024/6/24
+func reflectToNValue(val reflect.Value) (*nebula_type.Value, error) {
+	value := nebula_type.NewValue()
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := val.Int()
+		value.IVal = &i
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i := int64(val.Uint())
+		value.IVal = &i
+	case reflect.Float32, reflect.Float64:
+		f := val.Float()
+		value.FVal = &f
+	case reflect.String:
+		value.SVal = []byte(val.String())
+	case reflect.Bool:
+		b := val.Bool()
+		value.BVal = &b
+	case reflect.Slice, reflect.Array:
+		list := make([]*nebula_type.Value, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			item, err := InterfaceToNValue(val.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			list[i] = item
+		}
+		value.LVal = &nebula_type.NList{Values: list}
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map的键必须是string类型, 实际为: %s", val.Type().Key())
+		}
+		kvs := make(map[string]*nebula_type.Value, val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			item, err := InterfaceToNValue(iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			kvs[iter.Key().String()] = item
+		}
+		value.MVal = &nebula_type.NMap{Kvs: kvs}
+	case reflect.Ptr:
+		if val.IsNil() {
+			return InterfaceToNValue(nil)
+		}
+		return InterfaceToNValue(val.Elem().Interface())
+	default:
+		return nil, fmt.Errorf("不支持转换为nebula值的类型: %s", val.Type())
+	}
+	return value, nil
+}
@@ -0,0 +1,88 @@
+package utils
+
+import "strings"
+
+// 语句类型, 用于日志、指标、链路追踪等按语句分类统计的场景
+const (
+	StatementInsertVertex = "insert_vertex"
+	StatementInsertEdge   = "insert_edge"
+	StatementUpdateVertex = "update_vertex"
+	StatementUpdateEdge   = "update_edge"
+	StatementUpsertVertex = "upsert_vertex"
+	StatementUpsertEdge   = "upsert_edge"
+	StatementDeleteVertex = "delete_vertex"
+	StatementDeleteEdge   = "delete_edge"
+	StatementMatch        = "match"
+	StatementGo           = "go"
+	StatementFetch        = "fetch"
+	StatementLookup       = "lookup"
+	StatementSubgraph     = "get_subgraph"
+	StatementFindPath     = "find_path"
+	StatementSchema       = "schema" // create/drop/alter/show/describe/use 等管理语句
+	StatementOther        = "other"
+)
+
+// 以两个关键字区分的语句类型, 例如 insert vertex / insert edge
+var statementKinds = map[string]string{
+	"insert vertex": StatementInsertVertex,
+	"insert edge":   StatementInsertEdge,
+	"update vertex": StatementUpdateVertex,
+	"update edge":   StatementUpdateEdge,
+	"upsert vertex": StatementUpsertVertex,
+	"upsert edge":   StatementUpsertEdge,
+	"delete vertex": StatementDeleteVertex,
+	"delete edge":   StatementDeleteEdge,
+	"get subgraph":  StatementSubgraph,
+	"find shortest": StatementFindPath,
+	"find all":      StatementFindPath,
+	"find noloop":   StatementFindPath,
+}
+
+// 以单个关键字区分的语句类型
+var statementKeywords = map[string]string{
+	"match":    StatementMatch,
+	"optional": StatementMatch,
+	"go":       StatementGo,
+	"fetch":    StatementFetch,
+	"lookup":   StatementLookup,
+	"create":   StatementSchema,
+	"drop":     StatementSchema,
+	"alter":    StatementSchema,
+	"show":     StatementSchema,
+	"describe": StatementSchema,
+	"desc":     StatementSchema,
+	"use":      StatementSchema,
+}
+
+// GetStatementKind 根据nGQL的起始关键字识别语句类型, 忽略大小写与 explain/profile 前缀, 无法识别时返回 StatementOther。
+// 多条语句以分号或管道符拼接时, 以第一条语句为准。
+//
+// @Author: 罗德
+// @Date: 2024/6/19
+func GetStatementKind(ngql string) string {
+	words := strings.Fields(strings.ToLower(ngql))
+	// 跳过 explain/profile 及其 format 选项, 统计被分析的语句本身
+	for len(words) > 0 && (words[0] == "explain" || words[0] == "profile") {
+		words = words[1:]
+		if len(words) > 0 && strings.HasPrefix(words[0], "format") {
+			words = words[1:]
+		}
+	}
+	if len(words) == 0 {
+		return StatementOther
+	}
+
+	if len(words) > 1 {
+		if kind, ok := statementKinds[words[0]+" "+words[1]]; ok {
+			return kind
+		}
+	}
+	if kind, ok := statementKeywords[strings.TrimRight(words[0], ";(")]; ok {
+		return kind
+	}
+	// match(v:tag) 这类关键字与括号相连的写法
+	if strings.HasPrefix(words[0], "match(") {
+		return StatementMatch
+	}
+	return StatementOther
+}
@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"fmt"
+	"nebula-orm-go/clause"
+	"nebula-orm-go/constants"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 结构体标签选项
+const (
+	TagOptionVersion        = "version"        // 乐观锁版本号属性, 例如 `nebula:"version,version"`
+	TagOptionAutoCreateTime = "autoCreateTime" // 插入时写入当前时间, 例如 `nebula:"created_at,autoCreateTime"`
+	TagOptionAutoUpdateTime = "autoUpdateTime" // 插入与更新时写入当前时间, 例如 `nebula:"updated_at,autoUpdateTime:datetime"`
+	TagOptionDefault        = "default"        // 零值字段插入时使用的默认值(nGQL 表达式), 例如 `nebula:"status,default:'new'"`
+	TagOptionType           = "type"           // time.Time 字段的属性类型, 例如 `nebula:"ts,type:timestamp"`
+)
+
+// 时间属性的类型, 作为 type、autoCreateTime、autoUpdateTime 的选项值
+const (
+	TimeTypeTimestamp = "timestamp"
+	TimeTypeDatetime  = "datetime"
+)
+
+// NebulaTag 解析后的 nebula 结构体标签, 格式为 属性名[,选项[:值]...], 例如 `nebula:"ver,version"`
+//
+// @Author: 罗德
+// @Date: 2024/7/3
+type NebulaTag struct {
+	Name    string            // 属性名, 为空或 "-" 表示字段不映射属性
+	Options map[string]string // 选项名 -> 选项值, 没有值的选项为空字符串
+}
+
+// ParseNebulaTag 解析 nebula 结构体标签
+//
+// @Author: 罗德
+// @Date: 2024/7/3
+func ParseNebulaTag(tag string) NebulaTag {
+	parts := splitTag(tag)
+	parsed := NebulaTag{Name: strings.TrimSpace(parts[0]), Options: make(map[string]string, len(parts)-1)}
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(part, ":")
+		if key = strings.TrimSpace(key); key != "" {
+			parsed.Options[key] = strings.TrimSpace(value)
+		}
+	}
+	return parsed
+}
+
+// 按逗号拆分标签, 单引号、双引号字符串以及括号内的逗号不拆分, 例如 default:'a,b'、default:[1,2]
+//
+// @Author: 罗德
+// @Date: 2024/7/3
+func splitTag(tag string) []string {
+	var parts []string
+	var quote rune
+	depth, start := 0, 0
+	escaped := false
+	for i, r := range tag {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case (r == ')' || r == ']' || r == '}') && depth > 0:
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, tag[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, tag[start:])
+}
+
+// Has 是否包含选项
+//
+// @Author: 罗德
+// @Date: 2024/7/3
+func (t NebulaTag) Has(option string) bool {
+	_, ok := t.Options[option]
+	return ok
+}
+
+// TimeFunc 返回自动时间选项写入的当前时间函数 timestamp() 或 datetime()。
+// 属性类型由选项值指定, 未指定时按 TimeType 确定
+//
+// @Author: 罗德
+// @Date: 2024/7/4
+func (t NebulaTag) TimeFunc(option string, typ reflect.Type) string {
+	if kind := t.Options[option]; kind == TimeTypeTimestamp || kind == TimeTypeDatetime {
+		return kind + "()"
+	}
+	return t.TimeType(typ) + "()"
+}
+
+// TimeType 返回时间属性的类型 timestamp 或 datetime: 依次取 type、autoCreateTime、autoUpdateTime 选项的值,
+// 都未指定时 time.Time 字段为 datetime, 其它字段为 timestamp
+//
+// @Author: 罗德
+// @Date: 2024/7/4
+func (t NebulaTag) TimeType(typ reflect.Type) string {
+	for _, option := range []string{TagOptionType, TagOptionAutoCreateTime, TagOptionAutoUpdateTime} {
+		if kind := t.Options[option]; kind == TimeTypeTimestamp || kind == TimeTypeDatetime {
+			return kind
+		}
+	}
+	if typ == reflect.TypeOf(time.Time{}) {
+		return TimeTypeDatetime
+	}
+	return TimeTypeTimestamp
+}
+
+// TimeValue 将 time.Time 字段的值编码为 nGQL 字面量: 属性类型(见 TimeType)为 timestamp 时为 Unix 秒数,
+// 否则为 datetime(...)(见 clause.Value)
+//
+// @Author: 罗德
+// @Date: 2024/7/4
+func (t NebulaTag) TimeValue(value time.Time) string {
+	if t.TimeType(reflect.TypeOf(value)) == TimeTypeTimestamp {
+		return strconv.FormatInt(value.Unix(), 10)
+	}
+	literal, _ := clause.Value(value)
+	return literal
+}
+
+// InsertValue 返回零值字段插入时的取值: 自动时间字段为当前时间函数, 带默认值的字段为默认值;
+// ok 为 false 表示字段没有这些选项, 按字段本身的值插入
+//
+// @Author: 罗德
+// @Date: 2024/7/4
+func (t NebulaTag) InsertValue(typ reflect.Type) (value string, ok bool) {
+	switch {
+	case t.Has(TagOptionAutoCreateTime):
+		return t.TimeFunc(TagOptionAutoCreateTime, typ), true
+	case t.Has(TagOptionAutoUpdateTime):
+		return t.TimeFunc(TagOptionAutoUpdateTime, typ), true
+	case t.Options[TagOptionDefault] != "":
+		return t.Options[TagOptionDefault], true
+	}
+	return "", false
+}
+
+// GetPropName 返回字段映射的属性名, 字段没有 nebula 标签或标签为 "-" 时返回空字符串
+//
+// @Author: 罗德
+// @Date: 2024/7/3
+func GetPropName(field reflect.StructField) string {
+	name := ParseNebulaTag(field.Tag.Get(constants.StructTagName)).Name
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// GetVersionField 查找模型中带 version 选项的乐观锁字段, 返回属性名与字段值, 模型没有版本号字段时 ok 为 false。
+// 版本号字段必须是整数类型, 每个模型最多一个
+//
+// @Author: 罗德
+// @Date: 2024/7/3
+func GetVersionField(v any) (prop string, field reflect.Value, ok bool, err error) {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	if val.Kind() != reflect.Struct {
+		return "", reflect.Value{}, false, nil
+	}
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		tag := ParseNebulaTag(typ.Field(i).Tag.Get(constants.StructTagName))
+		if !tag.Has(TagOptionVersion) {
+			continue
+		}
+		if ok {
+			return "", reflect.Value{}, false, fmt.Errorf("%s 只能有一个版本号字段", typ)
+		}
+		switch typ.Field(i).Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		default:
+			return "", reflect.Value{}, false, fmt.Errorf("%s 的版本号字段 %s 必须是整数类型", typ, typ.Field(i).Name)
+		}
+		prop, field, ok = tag.Name, val.Field(i), true
+	}
+	return prop, field, ok, nil
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	nebula_type "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 标签选项的值中可以包含被引号或括号包围的逗号
+func TestParseNebulaTag(t *testing.T) {
+	tests := []struct {
+		tag     string
+		name    string
+		options map[string]string
+	}{
+		{"chain_key", "chain_key", map[string]string{}},
+		{"-", "-", map[string]string{}},
+		{"ver, version", "ver", map[string]string{"version": ""}},
+		{"updated_at,autoUpdateTime:timestamp", "updated_at", map[string]string{"autoUpdateTime": "timestamp"}},
+		{"status,default:'a,b'", "status", map[string]string{"default": "'a,b'"}},
+		{`status,default:"a,b",version`, "status", map[string]string{"default": `"a,b"`, "version": ""}},
+		{`status,default:'it\',s',version`, "status", map[string]string{"default": `'it\',s'`, "version": ""}},
+		{"tags,default:[1, 2]", "tags", map[string]string{"default": "[1, 2]"}},
+		{"point,default:ST_Point(1, 2)", "point", map[string]string{"default": "ST_Point(1, 2)"}},
+		{"at,default:datetime('2024-07-01T00:00:00'),autoCreateTime", "at",
+			map[string]string{"default": "datetime('2024-07-01T00:00:00')", "autoCreateTime": ""}},
+		{"a,,b", "a", map[string]string{"b": ""}},
+	}
+	for _, tt := range tests {
+		got := ParseNebulaTag(tt.tag)
+		if got.Name != tt.name || !reflect.DeepEqual(got.Options, tt.options) {
+			t.Errorf("ParseNebulaTag(%q) = %q %v, 期望 %q %v", tt.tag, got.Name, got.Options, tt.name, tt.options)
+		}
+	}
+}
+
+// time.Time 按 type 或自动时间选项声明的属性类型编码
+func TestTimeValue(t *testing.T) {
+	at := time.Date(2024, 7, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"at", "datetime('2024-07-01T00:00:00.000000')"},
+		{"at,autoCreateTime", "datetime('2024-07-01T00:00:00.000000')"},
+		{"at,autoUpdateTime:datetime", "datetime('2024-07-01T00:00:00.000000')"},
+		{"at,autoCreateTime:timestamp", "1719792000"},
+		{"at,autoUpdateTime:timestamp", "1719792000"},
+		{"at,type:timestamp", "1719792000"},
+		{"at,type:timestamp,autoUpdateTime", "1719792000"},
+		{"at,type:datetime", "datetime('2024-07-01T00:00:00.000000')"},
+		{"at,type:date", "datetime('2024-07-01T00:00:00.000000')"},
+	}
+	for _, tt := range tests {
+		if got := ParseNebulaTag(tt.tag).TimeValue(at); got != tt.want {
+			t.Errorf("%q: TimeValue = %s, 期望 %s", tt.tag, got, tt.want)
+		}
+	}
+}
+
+// 带 type 选项的零值自动时间字段使用对应的当前时间函数
+func TestTimeFunc(t *testing.T) {
+	timeType := reflect.TypeOf(time.Time{})
+	tests := []struct {
+		tag    string
+		option string
+		typ    reflect.Type
+		want   string
+	}{
+		{"at,autoCreateTime", TagOptionAutoCreateTime, timeType, "datetime()"},
+		{"at,autoCreateTime", TagOptionAutoCreateTime, reflect.TypeOf(int64(0)), "timestamp()"},
+		{"at,autoCreateTime,type:timestamp", TagOptionAutoCreateTime, timeType, "timestamp()"},
+		{"at,autoUpdateTime:datetime,type:timestamp", TagOptionAutoUpdateTime, timeType, "datetime()"},
+	}
+	for _, tt := range tests {
+		if got := ParseNebulaTag(tt.tag).TimeFunc(tt.option, tt.typ); got != tt.want {
+			t.Errorf("%q: TimeFunc = %s, 期望 %s", tt.tag, got, tt.want)
+		}
+	}
+}
+
+// 带 type 选项的模型
+type timeModel struct {
+	At time.Time `nebula:"at"`
+	Ts time.Time `nebula:"ts,type:timestamp"`
+	Dt time.Time `nebula:"dt,type:datetime"`
+}
+
+// time.Time 字段按标签声明的属性类型编码, 再解码回相同的时间
+func TestTimeFieldRoundTrip(t *testing.T) {
+	at := time.Date(2024, 7, 1, 8, 0, 0, 0, time.UTC)
+	fields, values := GetNebulaTag(timeModel{At: at, Ts: at, Dt: at})
+	wantValues := []string{"datetime('2024-07-01T08:00:00.000000')", "1719820800", "datetime('2024-07-01T08:00:00.000000')"}
+	if !reflect.DeepEqual(fields, []string{"at", "ts", "dt"}) || !reflect.DeepEqual(values, wantValues) {
+		t.Fatalf("GetNebulaTag = %q %q, 期望 %q", fields, values, wantValues)
+	}
+
+	ts := int64(1719820800)
+	timestamp := &nebula_type.Value{IVal: &ts}
+	datetime := &nebula_type.Value{DtVal: &nebula_type.DateTime{Year: 2024, Month: 7, Day: 1, Hour: 8}}
+	null := nebula_type.NullType___NULL__
+	tests := []struct {
+		name  string
+		field string
+		value *nebula_type.Value
+		want  time.Time
+		err   bool
+	}{
+		{name: "未声明类型读取 datetime", field: "At", value: datetime, want: at},
+		{name: "未声明类型读取 timestamp", field: "At", value: timestamp, want: at},
+		{name: "timestamp", field: "Ts", value: timestamp, want: at},
+		{name: "timestamp 类型不匹配", field: "Ts", value: datetime, err: true},
+		{name: "datetime", field: "Dt", value: datetime, want: at},
+		{name: "null", field: "Ts", value: &nebula_type.Value{NVal: &null}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := timeModel{Ts: at}
+			val := reflect.ValueOf(&m).Elem()
+			structField, _ := val.Type().FieldByName(tt.field)
+			err := SetStructFieldValue(val.FieldByName(tt.field), structField, tt.value)
+			if (err != nil) != tt.err {
+				t.Fatalf("SetStructFieldValue() error = %v", err)
+			}
+			if got := val.FieldByName(tt.field).Interface().(time.Time); !tt.err && !got.Equal(tt.want) {
+				t.Errorf("解码为 %s, 期望 %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -25,10 +25,10 @@ func GetStructFieldTagMap(typ reflect.Type) map[string]int {
 	tagMap := make(map[string]int)
 	// 遍历结构体的所有字段
 	for i := 0; i < typ.NumField(); i++ {
-		// 获取当前字段的标记值，使用 constants.StructTagName 指定的标签名称
-		tag := typ.Field(i).Tag.Get(constants.StructTagName)
+		// 获取当前字段映射的属性名，使用 constants.StructTagName 指定的标签名称
+		tag := GetPropName(typ.Field(i))
 		// 如果标签不存在或被设置为 "-", 表示该字段不应被处理，跳过
-		if tag == "" || tag == "-" {
+		if tag == "" {
 			continue
 		}
 		// 将标签名与字段索引存入映射中
@@ -69,6 +69,12 @@ func SetFieldValue(field reflect.Value, nValue *nebula_type.Value) error {
 	case reflect.Struct: // 结构体型，目前仅处理 time.Time 类型
 		switch field.Type().String() {
 		case "time.Time":
+			// datetime 属性按UTC时间转换
+			if dt := nValue.GetDtVal(); dt != nil {
+				field.Set(reflect.ValueOf(time.Date(int(dt.Year), time.Month(dt.Month), int(dt.Day), int(dt.Hour),
+					int(dt.Minute), int(dt.Sec), int(dt.Microsec)*1000, time.UTC)))
+				break
+			}
 			ts := nValue.GetIVal()                       // 获取整数值
 			field.Set(reflect.ValueOf(time.Unix(ts, 0))) // 转换为 Time 类型并设置
 		default:
@@ -81,6 +87,35 @@ func SetFieldValue(field reflect.Value, nValue *nebula_type.Value) error {
 	return nil
 }
 
+// SetStructFieldValue 同 SetFieldValue, 但 time.Time 字段按结构体标签声明的属性类型解码(见 NebulaTag.TimeType):
+// 属性类型为 timestamp 时只接受整数值并按 Unix 秒数转换, 为 datetime 时同 SetFieldValue; null 值解码为零值时间
+//
+// 参数:
+// - field (reflect.Value): 结构体字段的反射值，数据将被设置到这里。
+// - structField (reflect.StructField): 字段的定义, 用于读取 nebula 标签。
+// - nValue (*nebula_type.Value): Nebula Graph 数据库中的值对象。
+//
+// @Author: 罗德
+// @Date: 2024/7/4
+func SetStructFieldValue(field reflect.Value, structField reflect.StructField, nValue *nebula_type.Value) error {
+	if field.Type() != reflect.TypeOf(time.Time{}) {
+		return SetFieldValue(field, nValue)
+	}
+	if nValue.IsSetNVal() {
+		field.Set(reflect.ValueOf(time.Time{}))
+		return nil
+	}
+	tag := ParseNebulaTag(structField.Tag.Get(constants.StructTagName))
+	if tag.TimeType(field.Type()) == TimeTypeTimestamp {
+		if !nValue.IsSetIVal() {
+			return fmt.Errorf("字段 %s 的属性类型为 timestamp, 返回值不是整数: %s", structField.Name, nValue)
+		}
+		field.Set(reflect.ValueOf(time.Unix(nValue.GetIVal(), 0)))
+		return nil
+	}
+	return SetFieldValue(field, nValue)
+}
+
 // NValueToInterface 将 nebula_type.Value 类型的值转换为 Go 语言的 interface{} 类型。
 // nebula_type.Value 可能包含不同类型的数据，此函数根据 Value 内部实际设置的值类型，
 // 返回相应的基本数据类型值或者复杂的结构体，以提高代码的灵活性和兼容性。
@@ -152,7 +187,8 @@ func NValueToInterface(p *nebula_type.Value) interface{} {
 // @Date: 2024/5/27
 func GetVClause(v any, vertex string) (string, error) {
 	var parts []string
-	val := reflect.ValueOf(v)
+	// 兼容传入模型指针的情况(例如实现了指针接收者钩子的模型)
+	val := reflect.Indirect(reflect.ValueOf(v))
 	typ := val.Type()
 
 	if val.Kind() != reflect.Struct {
@@ -161,7 +197,7 @@ func GetVClause(v any, vertex string) (string, error) {
 
 	for i := 0; i < val.NumField(); i++ {
 		field := typ.Field(i)
-		tag := field.Tag.Get(constants.StructTagName)
+		tag := GetPropName(field)
 		if tag != "" {
 			parts = append(parts, fmt.Sprintf("%s.%s.%s as %s", constants.V, vertex, tag, tag))
 		}
@@ -177,7 +213,8 @@ func GetVClause(v any, vertex string) (string, error) {
 // @Date: 2024/5/27
 func GetClause(v any) (string, error) {
 	var parts []string
-	val := reflect.ValueOf(v)
+	// 兼容传入模型指针的情况(例如实现了指针接收者钩子的模型)
+	val := reflect.Indirect(reflect.ValueOf(v))
 	typ := val.Type()
 
 	if val.Kind() != reflect.Struct {
@@ -186,7 +223,7 @@ func GetClause(v any) (string, error) {
 
 	for i := 0; i < val.NumField(); i++ {
 		field := typ.Field(i)
-		tag := field.Tag.Get(constants.StructTagName)
+		tag := GetPropName(field)
 		if tag != "" {
 			parts = append(parts, fmt.Sprintf("%s as %s", tag, tag))
 		}
@@ -203,7 +240,8 @@ func GetClause(v any) (string, error) {
 func GetNebulaTag(v any) ([]string, []string) {
 	var fields []string
 	var values []string
-	val := reflect.ValueOf(v)
+	// 兼容传入模型指针的情况(例如实现了指针接收者钩子的模型)
+	val := reflect.Indirect(reflect.ValueOf(v))
 	typ := val.Type()
 
 	if val.Kind() != reflect.Struct {
@@ -212,12 +250,23 @@ func GetNebulaTag(v any) ([]string, []string) {
 
 	for i := 0; i < val.NumField(); i++ {
 		field := typ.Field(i)
-		tag := field.Tag.Get(constants.StructTagName)
+		tag := GetPropName(field)
 		if tag != "" {
 			// 获取字段值
 			fieldValue := val.Field(i)
 			// 这里简单地使用字符串形式表示字段值，实际情况可能需要更复杂的处理，特别是对于非基本类型
 			valueStr := fmt.Sprintf("%s", GetVidWithPolicy(fieldValue.Interface(), 0))
+			nebulaTag := ParseNebulaTag(field.Tag.Get(constants.StructTagName))
+			// time.Time 按属性类型编码为 datetime 字面量或 Unix 秒数
+			if t, ok := fieldValue.Interface().(time.Time); ok {
+				valueStr = nebulaTag.TimeValue(t)
+			}
+			// 零值的自动时间、默认值字段使用当前时间函数或默认值
+			if fieldValue.IsZero() {
+				if value, ok := nebulaTag.InsertValue(field.Type); ok {
+					valueStr = value
+				}
+			}
 			fields = append(fields, tag)
 			values = append(values, valueStr)
 		}
@@ -237,7 +286,7 @@ func GetVidWithPolicy(vid interface{}, policy constants.Policy) string {
 
 	// 使用类型断言确定vid的具体类型，并据此格式化vid
 	switch vid.(type) {
-	case int, int8, int32, int64, float32, float64:
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
 		// 对于数字类型，直接转换为字符串
 		vidStr = fmt.Sprint(vid)
 	case string:
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"nebula-orm-go/constants"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// VidType 图空间的 VID 类型, 对应 describe space 返回的 Vid Type 列
+//
+// @Author: 罗德
+// @Date: 2024/7/5
+type VidType struct {
+	Int64  bool // VID 类型是否为 INT64
+	Length int  // FIXED_STRING(n) 的最大字节数, Int64 为 true 时无意义
+}
+
+// ParseVidType 解析 VID 类型, 支持 INT64(或 INT) 与 FIXED_STRING(n), 不区分大小写
+//
+// @Author: 罗德
+// @Date: 2024/7/5
+func ParseVidType(s string) (*VidType, error) {
+	upper := strings.ToUpper(strings.TrimSpace(s))
+	switch {
+	case upper == "INT64" || upper == "INT":
+		return &VidType{Int64: true}, nil
+	case strings.HasPrefix(upper, "FIXED_STRING(") && strings.HasSuffix(upper, ")"):
+		length, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(upper, "FIXED_STRING("), ")"))
+		if err != nil || length <= 0 {
+			return nil, fmt.Errorf("无效的VID类型: %s", s)
+		}
+		return &VidType{Length: length}, nil
+	}
+	return nil, fmt.Errorf("无效的VID类型: %s, 仅支持 INT64 与 FIXED_STRING(n)", s)
+}
+
+// String 返回 nGQL 中的 VID 类型, 例如 INT64、FIXED_STRING(64)
+func (t *VidType) String() string {
+	if t.Int64 {
+		return "INT64"
+	}
+	return fmt.Sprintf("FIXED_STRING(%d)", t.Length)
+}
+
+// Check 校验 VID 是否符合图空间的 VID 类型:
+//   - INT64 图空间的 VID 必须是不超过 int64 范围的整数, 或使用 PolicyHash 将字符串哈希为整数
+//   - FIXED_STRING(n) 图空间的 VID 必须是字符串, 且不超过 n 个字节(graphd 对超长的 VID 不会截断而是报错), 不能使用 PolicyHash
+//
+// @Author: 罗德
+// @Date: 2024/7/5
+func (t *VidType) Check(vid interface{}, policy constants.Policy) error {
+	if vid == nil {
+		return fmt.Errorf("VID不能为空")
+	}
+	val := reflect.ValueOf(vid)
+	if t.Int64 {
+		if policy == constants.PolicyHash {
+			return nil
+		}
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if val.Uint() > math.MaxInt64 {
+				return fmt.Errorf("VID[%d]超出 INT64 的范围", val.Uint())
+			}
+			return nil
+		}
+		return fmt.Errorf("INT64 图空间的VID必须是整数, 实际为 %T(%v), 字符串VID请使用 PolicyHash", vid, vid)
+	}
+	if policy == constants.PolicyHash {
+		return fmt.Errorf("%s 图空间的VID不能使用 PolicyHash", t)
+	}
+	if val.Kind() != reflect.String {
+		return fmt.Errorf("%s 图空间的VID必须是字符串, 实际为 %T(%v)", t, vid, vid)
+	}
+	if length := len(val.String()); length > t.Length {
+		return fmt.Errorf("VID[%s]长度为%d字节, 超过图空间的 %s", val.String(), length, t)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"math"
+	"nebula-orm-go/constants"
+	"strings"
+	"testing"
+)
+
+// 解析 describe space 返回的 VID 类型
+func TestParseVidType(t *testing.T) {
+	tests := []struct {
+		text string
+		want string // 解析后的 String(), 为空表示解析失败
+	}{
+		{"INT64", "INT64"},
+		{"int", "INT64"},
+		{" fixed_string(32) ", "FIXED_STRING(32)"},
+		{"FIXED_STRING(1)", "FIXED_STRING(1)"},
+		{"FIXED_STRING(0)", ""},
+		{"FIXED_STRING(-1)", ""},
+		{"FIXED_STRING(a)", ""},
+		{"FIXED_STRING(32", ""},
+		{"STRING", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		vidType, err := ParseVidType(tt.text)
+		switch {
+		case tt.want == "" && err == nil:
+			t.Errorf("ParseVidType(%q) 应返回错误, 实际为 %s", tt.text, vidType)
+		case tt.want != "" && err != nil:
+			t.Errorf("ParseVidType(%q) 失败: %v", tt.text, err)
+		case tt.want != "" && vidType.String() != tt.want:
+			t.Errorf("ParseVidType(%q) = %s, 期望 %s", tt.text, vidType, tt.want)
+		}
+	}
+}
+
+// 按 VID 类型校验 VID 的类型、范围与长度
+func TestVidTypeCheck(t *testing.T) {
+	int64Type := &VidType{Int64: true}
+	stringType := &VidType{Length: 6}
+	tests := []struct {
+		name    string
+		typ     *VidType
+		vid     interface{}
+		policy  constants.Policy
+		wantErr string // 为空表示校验通过
+	}{
+		{"整数", int64Type, 42, constants.PolicyNothing, ""},
+		{"int64 最大值", int64Type, int64(math.MaxInt64), constants.PolicyNothing, ""},
+		{"uint64 不溢出", int64Type, uint64(math.MaxInt64), constants.PolicyNothing, ""},
+		{"uint64 溢出", int64Type, uint64(math.MaxInt64) + 1, constants.PolicyNothing, "超出 INT64 的范围"},
+		{"INT64 使用字符串", int64Type, "a", constants.PolicyNothing, "必须是整数"},
+		{"INT64 使用浮点数", int64Type, 1.5, constants.PolicyNothing, "必须是整数"},
+		{"字符串哈希", int64Type, "a", constants.PolicyHash, ""},
+		{"空VID", int64Type, nil, constants.PolicyNothing, "不能为空"},
+		{"字符串", stringType, "abc", constants.PolicyNothing, ""},
+		{"长度等于上限", stringType, "abcdef", constants.PolicyNothing, ""},
+		{"长度按字节计算", stringType, "根节点", constants.PolicyNothing, "长度为9字节"},
+		{"FIXED_STRING 使用整数", stringType, 1, constants.PolicyNothing, "必须是字符串"},
+		{"FIXED_STRING 使用哈希", stringType, "a", constants.PolicyHash, "不能使用 PolicyHash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.typ.Check(tt.vid, tt.policy)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("校验失败: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("错误 = %v, 期望包含 %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package vidgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"nebula-orm-go/model"
+	"time"
+)
+
+// ULID 使用的 Crockford Base32 字符表
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID 按时间排序的 ULID 生成策略, 前 48 位为毫秒时间戳, 后 80 位随机, 返回 26 个字符的字符串
+//
+// @Author: 罗德
+// @Date: 2024/7/6
+func ULID() model.IVidGenerator {
+	return Func(func(model.IVertex) (interface{}, error) {
+		return NewULID()
+	})
+}
+
+// NewULID 生成 ULID
+//
+// @Author: 罗德
+// @Date: 2024/7/6
+func NewULID() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[6:]); err != nil {
+		return "", err
+	}
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(time.Now().UnixMilli()))
+	copy(id[:6], ms[2:])
+
+	// 128 位按 5 位一组编码为 26 个字符, 最高位补 2 个 0
+	var buf [26]byte
+	for i := range buf {
+		var index byte
+		for bit := i*5 - 2; bit < i*5+3; bit++ {
+			index <<= 1
+			if bit >= 0 && id[bit/8]&(0x80>>(bit%8)) != 0 {
+				index |= 1
+			}
+		}
+		buf[i] = crockford[index]
+	}
+	return string(buf[:]), nil
+}
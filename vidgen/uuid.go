@@ -0,0 +1,73 @@
+package vidgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"nebula-orm-go/model"
+	"time"
+)
+
+// UUIDv4 随机 UUID 生成策略, 返回 36 个字符的字符串, 例如 0b7a3c1e-5f2d-4c8a-9e6b-1d2f3a4b5c6d
+//
+// @Author: 罗德
+// @Date: 2024/7/6
+func UUIDv4() model.IVidGenerator {
+	return Func(func(model.IVertex) (interface{}, error) {
+		return NewUUIDv4()
+	})
+}
+
+// UUIDv7 按时间排序的 UUID 生成策略, 前 48 位为毫秒时间戳, 返回 36 个字符的字符串
+//
+// @Author: 罗德
+// @Date: 2024/7/6
+func UUIDv7() model.IVidGenerator {
+	return Func(func(model.IVertex) (interface{}, error) {
+		return NewUUIDv7()
+	})
+}
+
+// NewUUIDv4 生成随机 UUID(版本 4)
+//
+// @Author: 罗德
+// @Date: 2024/7/6
+func NewUUIDv4() (string, error) {
+	var uuid [16]byte
+	if _, err := rand.Read(uuid[:]); err != nil {
+		return "", err
+	}
+	return formatUUID(uuid, 4), nil
+}
+
+// NewUUIDv7 生成按时间排序的 UUID(版本 7)
+//
+// @Author: 罗德
+// @Date: 2024/7/6
+func NewUUIDv7() (string, error) {
+	var uuid [16]byte
+	if _, err := rand.Read(uuid[6:]); err != nil {
+		return "", err
+	}
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(time.Now().UnixMilli()))
+	copy(uuid[:6], ms[2:])
+	return formatUUID(uuid, 7), nil
+}
+
+// 写入版本号与变体(RFC 4122)后格式化为 8-4-4-4-12
+func formatUUID(uuid [16]byte, version byte) string {
+	uuid[6] = uuid[6]&0x0f | version<<4
+	uuid[8] = uuid[8]&0x3f | 0x80
+	var buf [36]byte
+	hex.Encode(buf[0:8], uuid[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], uuid[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], uuid[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], uuid[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], uuid[10:])
+	return string(buf[:])
+}
@@ -0,0 +1,22 @@
+// Package vidgen 内置的VID生成策略, 点模型通过实现 model.IVidGenerate 指定策略, 插入时 Vid 为空则自动生成:
+//   - Snowflake: 雪花算法, 生成趋势递增的 int64, 适用于 INT64 图空间
+//   - UUIDv4/UUIDv7: 36 个字符的 UUID 字符串, 适用于 FIXED_STRING(36) 及以上的图空间
+//   - ULID: 26 个字符、按时间排序的字符串
+//   - Composite/CompositeInt64: 由指定属性计算的哈希, 相同属性值的点得到相同的VID
+//
+// @Author: 罗德
+// @Date: 2024/7/6
+package vidgen
+
+import "nebula-orm-go/model"
+
+// Func 将函数适配为 model.IVidGenerator
+//
+// @Author: 罗德
+// @Date: 2024/7/6
+type Func func(vertex model.IVertex) (interface{}, error)
+
+// Generate 实现 model.IVidGenerator 接口
+func (f Func) Generate(vertex model.IVertex) (interface{}, error) {
+	return f(vertex)
+}
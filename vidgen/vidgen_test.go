@@ -0,0 +1,234 @@
+package vidgen
+
+import (
+	"encoding/hex"
+	"nebula-orm-go/model"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 测试用点
+type testVertex struct {
+	model.VModel
+	Name string `nebula:"name"`
+	Age  int    `nebula:"age"`
+}
+
+func (v testVertex) TagName() string {
+	return "person"
+}
+
+// 拆分雪花ID的时间戳、节点号与序列号
+func splitSnowflake(id int64) (ms, node, sequence int64) {
+	return id >> (snowflakeNodeBits + snowflakeSequenceBits), id >> snowflakeSequenceBits & snowflakeMaxNode, id & snowflakeMaxSequence
+}
+
+func TestNewSnowflake(t *testing.T) {
+	for _, node := range []int64{-1, snowflakeMaxNode + 1} {
+		if _, err := NewSnowflake(node); err == nil {
+			t.Errorf("节点号 %d 应返回错误", node)
+		}
+	}
+	s := MustNewSnowflake(snowflakeMaxNode)
+	before := time.Since(SnowflakeEpoch).Milliseconds()
+	ms, node, sequence := splitSnowflake(s.Next())
+	if node != snowflakeMaxNode || sequence != 0 || ms < before || ms > time.Since(SnowflakeEpoch).Milliseconds() {
+		t.Errorf("ms=%d node=%d sequence=%d", ms, node, sequence)
+	}
+}
+
+// 并发生成的ID互不重复, 每个协程内严格递增
+func TestSnowflakeConcurrent(t *testing.T) {
+	s := MustNewSnowflake(1)
+	const workers, count = 8, 5000
+	results := make([][]int64, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			ids := make([]int64, count)
+			for i := range ids {
+				ids[i] = s.Next()
+			}
+			results[w] = ids
+		}(w)
+	}
+	wg.Wait()
+
+	seen := make(map[int64]bool, workers*count)
+	for _, ids := range results {
+		for i, id := range ids {
+			if seen[id] {
+				t.Fatalf("生成了重复的ID %d", id)
+			}
+			seen[id] = true
+			if i > 0 && id <= ids[i-1] {
+				t.Fatalf("ID 未递增: %d <= %d", id, ids[i-1])
+			}
+			if _, node, _ := splitSnowflake(id); node != 1 {
+				t.Fatalf("ID %d 的节点号为 %d", id, node)
+			}
+		}
+	}
+}
+
+// 时钟回拨时沿用上次的时间戳, 序列号用尽后借用下一毫秒, ID 仍然递增
+func TestSnowflakeClockBackwards(t *testing.T) {
+	s := MustNewSnowflake(0)
+	first := s.Next()
+
+	// 推后起始时间相当于系统时钟回拨
+	epoch := SnowflakeEpoch
+	SnowflakeEpoch = epoch.Add(time.Hour)
+	defer func() { SnowflakeEpoch = epoch }()
+
+	last := first
+	for i := 0; i < snowflakeMaxSequence+10; i++ {
+		id := s.Next()
+		if id <= last {
+			t.Fatalf("时钟回拨后 ID 未递增: %d <= %d", id, last)
+		}
+		last = id
+	}
+	firstMs, _, _ := splitSnowflake(first)
+	lastMs, _, _ := splitSnowflake(last)
+	if lastMs < firstMs || lastMs > firstMs+2 {
+		t.Errorf("时钟回拨后的时间戳 = %d, 期望沿用 %d 并在序列号用尽时递增", lastMs, firstMs)
+	}
+}
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([0-9a-f])[0-9a-f]{3}-([89ab])[0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestUUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		new     func() (string, error)
+		version string
+	}{
+		{name: "v4", new: NewUUIDv4, version: "4"},
+		{name: "v7", new: NewUUIDv7, version: "7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[string]bool)
+			for i := 0; i < 100; i++ {
+				id, err := tt.new()
+				if err != nil {
+					t.Fatal(err)
+				}
+				match := uuidRegexp.FindStringSubmatch(id)
+				if match == nil || match[1] != tt.version {
+					t.Fatalf("%s 不是版本 %s 的 UUID", id, tt.version)
+				}
+				if seen[id] {
+					t.Fatalf("生成了重复的 UUID %s", id)
+				}
+				seen[id] = true
+			}
+		})
+	}
+}
+
+// UUIDv7 的前 48 位为毫秒时间戳
+func TestUUIDv7Timestamp(t *testing.T) {
+	before := time.Now().UnixMilli()
+	id, err := NewUUIDv7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now().UnixMilli()
+	raw, err := hex.DecodeString(strings.ReplaceAll(id, "-", "")[:12])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ms int64
+	for _, b := range raw {
+		ms = ms<<8 | int64(b)
+	}
+	if ms < before || ms > after {
+		t.Errorf("UUIDv7 时间戳 = %d, 期望在 %d~%d 之间", ms, before, after)
+	}
+}
+
+func TestULID(t *testing.T) {
+	before := time.Now().UnixMilli()
+	id, err := NewULID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now().UnixMilli()
+	if len(id) != 26 || strings.Trim(id, crockford) != "" {
+		t.Fatalf("%s 不是 26 个 Crockford Base32 字符", id)
+	}
+	// 第一个字符只有 3 位有效, 最大为 7
+	if id[0] > '7' {
+		t.Errorf("%s 的第一个字符超出 128 位的范围", id)
+	}
+	// 前 10 个字符为 48 位毫秒时间戳
+	var ms int64
+	for _, c := range id[:10] {
+		ms = ms<<5 | int64(strings.IndexRune(crockford, c))
+	}
+	if ms < before || ms > after {
+		t.Errorf("ULID 时间戳 = %d, 期望在 %d~%d 之间", ms, before, after)
+	}
+
+	// 不同毫秒生成的 ULID 按字符串排序
+	time.Sleep(2 * time.Millisecond)
+	next, err := NewULID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next <= id {
+		t.Errorf("ULID 未按时间排序: %s <= %s", next, id)
+	}
+}
+
+func TestComposite(t *testing.T) {
+	a := testVertex{Name: "甲", Age: 30}
+	generate := func(g model.IVidGenerator, v model.IVertex) interface{} {
+		t.Helper()
+		vid, err := g.Generate(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return vid
+	}
+
+	g := Composite("name", "age")
+	vid := generate(g, a)
+	if s, ok := vid.(string); !ok || len(s) != 32 {
+		t.Fatalf("Composite VID = %v, 期望 32 个字符的字符串", vid)
+	}
+	// 相同属性值得到相同的VID, 与是否传入指针、VID 以外的字段无关
+	same := a
+	same.Vid = "x"
+	if generate(g, &same) != vid || generate(Composite("name", "age"), a) != vid {
+		t.Error("相同属性值的VID不同")
+	}
+	// 属性值或属性顺序不同得到不同的VID, 字符串转义后不会与其它组合冲突
+	for _, other := range []interface{}{
+		generate(g, testVertex{Name: "甲", Age: 31}),
+		generate(Composite("age", "name"), a),
+		generate(Composite("name"), testVertex{Name: "甲',30"}),
+	} {
+		if other == vid {
+			t.Errorf("不同的属性组合得到相同的VID %v", vid)
+		}
+	}
+
+	id := generate(CompositeInt64("name", "age"), a)
+	if _, ok := id.(int64); !ok || generate(CompositeInt64("name", "age"), &same) != id {
+		t.Errorf("CompositeInt64 VID = %v", id)
+	}
+
+	for _, props := range [][]string{nil, {"missing"}} {
+		if _, err := Composite(props...).Generate(a); err == nil {
+			t.Errorf("属性 %v 应返回错误", props)
+		}
+	}
+}